@@ -35,14 +35,18 @@
 package acacia
 
 import (
-	"bytes"
+	"compress/gzip"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"syscall"
 	"time"
 )
 
@@ -52,13 +56,15 @@ const (
 	MinBufferSize     = 1_000
 	DefaultBatchSize  = 64 * 1024 // 64 kb
 	flushInterval     = 100 * time.Millisecond
+	barrierTimeout    = 5 * time.Second
+	defaultFileMode   = os.FileMode(0644)
 	cacheInterval     = 100 * time.Millisecond
 	lastDayFormat     = "2006-01-02"
 )
 
-var (
-	timestampFormat = TS.Special
-)
+// defaultTimestampFormat is the layout new loggers start with. It is never
+// mutated: each Log keeps its own layout (see Log.TimestampFormat).
+var defaultTimestampFormat = TS.Special
 
 var (
 	levelDebug    = []byte("DEBUG")
@@ -69,13 +75,76 @@ var (
 )
 
 type config struct {
-	bufferSize int
-	batchSize  int
-	flushEvery time.Duration
+	bufferSize      int
+	batchSize       int
+	flushEvery      time.Duration
+	diagPath        string
+	escalation      *escalation
+	healthThreshold float64
+	idGen           IDGenerator
+	lazyOpen        bool
+	syncPolicy      SyncPolicy
+	critMirror      *criticalMirror
+	preallocate     bool
+	preciseTS       bool
+	epochUnit       time.Duration
+	encoder         Encoder
+	cef             *CEFConfig
+	formatter       Formatter
+	stackLevel      string
+	goroutineID     bool
+	metaFields      []Field
+	onceFields      []Field
+	redact          *redactor
+	chainKey        []byte
+	sampler         *sampler
+	dedup           *dedup
+	quota           *byteQuota
+	filters         []FilterFunc
+	filterMtx       *sync.Mutex // WithWriters: los filtros de los archivos, de a uno
+	transforms      []TransformFunc
+	levelFiles      []*levelFile
+	mirror          *mirrorFile
+	recent          *recentRing
+	clock           func() time.Time
+	jsonSchema      *jsonSchema
+	router          *router
+	ack             *ackState
+	archive         bool
+	fileMode        os.FileMode
+	spillThreshold  int
+	maxMessageSize  int
+	escapeControl   bool
+	header          *HeaderConfig
+	fileHeader      FileHook
+	fileFooter      FileHook
+	recoverPartial  bool
+	noTimestamp     bool
+	staticPrefix    string
+	levelLabels     map[string]string
+	levelPadding    bool
+	retryPath       string
+	retryMax        int64
+	breakerFailures int
+	breakerCooldown time.Duration
+	writers         int
+	gzipOut         bool
+	strictUTF8      bool
+	errs            *errSink // WithWriters: el de los archivos es el del padre
+	createDir       os.FileMode
+	disk            *diskMonitor
+	severities      *[5]int
+	syslog          *syslogHeader
+	noWriter        bool // padre de WithWriters: sin cola ni goroutine writer
 }
 
 type Option func(*config)
 
+// WithBufferSize sets how many entries the queue holds before producers wait
+// for the writer (DefaultBufferSize; values below MinBufferSize are ignored).
+// Each queued entry takes about 100 bytes. That memory is allocated in blocks
+// of 256 entries as the queue fills and released as it drains, so the size
+// bounds the memory rather than reserving it.
 func WithBufferSize(number int) Option {
 	return func(conf *config) {
 		if number >= MinBufferSize {
@@ -92,6 +161,84 @@ func WithBatchSize(number int) Option {
 	}
 }
 
+// WithLazyOpen defers creating the log file until the first record is
+// written, so loggers that never log leave no empty files behind.
+func WithLazyOpen() Option {
+	return func(conf *config) {
+		conf.lazyOpen = true
+	}
+}
+
+// WithCreateDir makes Start create the log directory, and any missing
+// parent, with permissions perm (0755 if 0) instead of failing when it does
+// not exist. Existing directories are left as they are.
+func WithCreateDir(perm os.FileMode) Option {
+	return func(conf *config) {
+		if perm = perm.Perm(); perm == 0 {
+			perm = 0755
+		}
+		conf.createDir = perm
+	}
+}
+
+// WithClock makes the logger take entry timestamps and the date used by
+// daily rotation from clock instead of time.Now, so tests can control both.
+// It implies WithPreciseTimestamps: every entry reads the clock. Intervals
+// (flushing, sampling windows, sync policies…) keep using the real time.
+func WithClock(clock func() time.Time) Option {
+	return func(conf *config) {
+		if clock != nil {
+			conf.clock = clock
+			conf.preciseTS = true
+		}
+	}
+}
+
+// WithPreciseTimestamps stamps every entry with its own time.Now() instead of
+// the timestamp cache refreshed every 100ms, for microsecond-accurate ordering.
+// The time is taken on the logging goroutine and formatted by the writer.
+func WithPreciseTimestamps(enabled bool) Option {
+	return func(conf *config) {
+		conf.preciseTS = enabled
+	}
+}
+
+// WithEpochTimestamps emits the JSON "ts" field as a Unix epoch integer in
+// the given unit (time.Second, time.Millisecond, time.Microsecond or
+// time.Nanosecond) instead of a formatted string. Text output is unchanged.
+func WithEpochTimestamps(unit time.Duration) Option {
+	return func(conf *config) {
+		switch unit {
+		case time.Second, time.Millisecond, time.Microsecond, time.Nanosecond:
+			conf.epochUnit = unit
+		}
+	}
+}
+
+// WithoutTimestamp leaves the timestamp out of every entry, for
+// destinations that add their own, such as journald or the Docker logging
+// drivers: text lines start with the level ("[INFO] msg") and JSON and
+// logfmt entries have no "ts" member. Pretty output drops the time column.
+// CEF keeps its rt= extension, and Format.Binary records and the
+// Entry.Time seen by formatters and layouts still carry the time.
+func WithoutTimestamp() Option {
+	return func(conf *config) {
+		conf.noTimestamp = true
+		conf.staticPrefix = ""
+	}
+}
+
+// WithStaticPrefix is WithoutTimestamp with prefix written where the
+// timestamp went in text and pretty lines ("web-1 [INFO] msg"), e.g. to tell
+// instances apart in a shared stream. Structured formats omit "ts" as with
+// WithoutTimestamp.
+func WithStaticPrefix(prefix string) Option {
+	return func(conf *config) {
+		conf.noTimestamp = true
+		conf.staticPrefix = prefix
+	}
+}
+
 // WithFlushInterval permite configurar cada cuánto el writer dispara un flush periodico.
 func WithFlushInterval(d time.Duration) Option {
 	return func(conf *config) {
@@ -156,55 +303,191 @@ var Level = getLevel{
 	CRITICAL: "CRITICAL",
 }
 
+type getFormat struct {
+	Text   string
+	JSON   string
+	Logfmt string
+	CEF    string
+	Pretty string
+	Syslog string
+	Custom string
+	Binary string
+}
+
+// Format lists the output formats accepted by OutputFormat.
+var Format = getFormat{
+	Text:   "TEXT",
+	JSON:   "JSON",
+	Logfmt: "LOGFMT",
+	CEF:    "CEF",
+	Pretty: "PRETTY",
+	Syslog: "SYSLOG",
+	Custom: "CUSTOM",
+	Binary: "BINARY",
+}
+
 type Log struct {
-	name, path, level string
-	structured        bool
-	status            bool
-	maxSize           int64
-	maxRotation       int
-	daily             bool
-	lastDay           string
-	file              atomic.Value
-	message           chan []byte
-	events            chan logEvent
-	wg                sync.WaitGroup
-	mtx               sync.Mutex
-	buffer            []byte
-	writeBuf          []byte
-	flushEvery        time.Duration
-	cachedTime        atomic.Value
-	timeTicker        *time.Ticker
-	done              chan struct{}
-	closeOnce         sync.Once
-	forceDailyRotate  bool
-	enqueueSeq        uint64
-	dequeueSeq        uint64
-	control           chan controlReq
-	currentSize       int64
+	name, path       string
+	minLevel         int32        // levelRank del nivel mínimo, ver SetLevel
+	format           atomic.Value // string: Format.*, ver lineFormat
+	status           bool
+	maxSize          int64
+	maxRotation      int
+	daily            bool
+	lastDay          string
+	file             atomic.Value
+	queue            *eventRing
+	wake             chan struct{}
+	writerParked     int32
+	closed           int32
+	dropped          uint64
+	wg               sync.WaitGroup
+	mtx              sync.Mutex
+	buffer           []byte
+	writeBuf         []byte
+	flushEvery       time.Duration
+	cachedTime       atomic.Value
+	tsFormat         atomic.Value // string: layout de timestamps de esta instancia
+	utc              int32        // 1: timestamps y fechas de rotación en UTC
+	timeTicker       *time.Ticker
+	sharedTS         bool // refrescada por sharedTimestamps en lugar de timeTicker
+	done             chan struct{}
+	closeOnce        sync.Once
+	forceDailyRotate bool
+	control          chan controlReq
+	currentSize      int64
+	lastFlush        int64 // unix nano del último flush
+	writeErr         error // primer error de escritura desde el último Sync, solo writer
+	binPrev          int64 // timestamp del último registro Format.Binary, solo writer
+	diagPath         string
+	diagMtx          sync.Mutex // diagSignals, aparte de mtx para no esperar al writer
+	diagSignals      chan os.Signal
+	state            writerState // lo que DumpState muestra del writer
+	escalation       *escalation
+	healthThreshold  float64
+	writerAlive      int32
+	idGen            IDGenerator
+	lazyOpen         bool
+	syncPolicy       SyncPolicy
+	syncWanted       uint64        // mayor posición de cola que pidió fsync (SyncOnLevel)
+	syncedUpTo       uint64        // solo writer
+	syncReq          chan struct{} // != nil: los fsync de la política los hace runSyncer
+	syncTarget       uint64        // mayor posición de cola cuyo fsync se pidió al syncer
+	syncedSeq        uint64        // mayor posición de cola ya cubierta por el syncer
+	unsynced         bool          // solo writer
+	lastSync         time.Time
+	critMirror       *criticalMirror
+	preallocate      bool
+	preciseTS        bool
+	epochUnit        time.Duration // != 0: "ts" JSON como entero epoch en esta unidad
+	encoder          Encoder
+	cef              *CEFConfig
+	syslog           *syslogHeader // Format.Syslog
+	color            bool          // colores ANSI en Format.Pretty (ver colorOutput)
+	formatter        Formatter
+	stackLevel       string // "": sin stack traces
+	goroutineID      bool
+	metaFields       []Field // campos fijos resueltos en Start
+	onceFields       []Field // campos solo para la primera entrada estructurada
+	oncePending      int32   // 1: onceFields aún no se emitieron
+	redact           *redactor
+	chain            *hashChain // WithHashChain, solo writer
+	sampler          *sampler
+	sampled          uint64
+	dedup            *dedup // solo writer
+	quota            *byteQuota
+	filters          []FilterFunc
+	filterMtx        *sync.Mutex
+	filtered         uint64
+	transforms       []TransformFunc
+	levelFiles       []*levelFile
+	mirror           *mirrorFile
+	recent           *recentRing
+	clock            func() time.Time // time.Now salvo WithClock
+	schema           *jsonSchema      // nombres de los miembros JSON
+	severities       *[5]int          // WithSeverity, por levelRank
+	sink             io.Writer        // StartWriter: destino en lugar del archivo
+	router           *router
+	ack              *ackState   // AuditMode: espera de escritura por registro
+	archive          bool        // la rotación nunca borra respaldos
+	fileMode         os.FileMode // permisos del archivo y sus respaldos
+	spill            *spillFile  // WithSpillover, solo writer
+	maxMessageSize   int         // WithMaxMessageSize, 0: sin límite
+	escapeControl    bool        // WithControlCharEscaping
+	header           *HeaderConfig
+	headerStart      time.Time
+	headerPending    int32 // 1: el banner espera al primer registro o a una barrera
+	fileHeader       FileHook
+	fileFooter       FileHook
+	rotatedTo        string // nombre que recibió el último archivo rotado, solo writer
+	recoverPartial   bool   // WithPartialLineRecovery
+	noTimestamp      bool   // WithoutTimestamp y WithStaticPrefix
+	staticPrefix     string // ocupa el lugar del timestamp en texto
+	levelTags        *levelTags
+	retry            *retrySpool // WithRetrySpool, solo writer
+	breakerFailures  int         // WithCircuitBreaker, 0: sin breaker en archivos laterales
+	breakerCooldown  time.Duration
+	shards           []*Log    // WithWriters: loggers que escriben cada archivo
+	segs             []segment // lote vectorizado en curso (ver holdLine); solo writer
+	segsOut          []segment
+	segStart         int // inicio de la región del buffer aún sin trozo
+	segBytes         int // bytes en líneas fuera del buffer
+	iovBufs          [][]byte
+	joinBuf          []byte
+	gzipOut          bool         // WithGzipOutput
+	strictUTF8       bool         // WithStrictUTF8
+	errs             *errSink     // Errors
+	gz               *gzip.Writer // stream del archivo gzFile; solo writer
+	gzFile           *os.File
+	gzDirty          bool // datos sin flush point
+	gzFlushed        time.Time
+	shardNext        uint32
 }
 
 // controlReq es un mensaje de control hacia el writer.
 // target indica el número de mensajes encolados que deben haber sido
 // consumidos (y flushados) antes de responder el ack.
+// run, si no es nil, se ejecuta en la goroutine writer justo antes del ack,
+// sin rotaciones ni escrituras concurrentes.
 type controlReq struct {
 	target uint64
 	ack    chan struct{}
+	run    func()
 }
 
 // logEvent representa un evento ligero que será formateado por la goroutine writer.
 // Evita construir []byte por mensaje en el productor para reducir allocs/op.
+// Se mantiene compacto porque cada slot del ring guarda uno.
 type logEvent struct {
-	level    string
 	msgStr   string
 	msgBytes []byte
-	kind     uint8 // 0 = string, 1 = bytes
+	ts       int64                  // unix nano del productor (WithPreciseTimestamps), 0 = caché
+	level    uint8                  // levelRank
+	kind     uint8                  // eventString, eventBytes o eventRaw
+	dupLen   uint32                 // WithDuplicateSuppression: bytes de identidad tras msgBytes, en su capacidad
+	entry    *Entry                 // solo con WithFilter: la entrada que ven los filtros
+	route    string                 // solo con WithRouting: valor del campo de enrutamiento
+	fields   map[string]interface{} // eventMap: entrada JSON por codificar
 }
 
+const (
+	eventString uint8 = iota // msgStr sin formatear
+	eventBytes               // msgBytes del caller sin formatear
+	eventRaw                 // msgBytes es una línea completa de un pool
+	eventBinary              // msgBytes es un registro Format.Binary sin longitud ni timestamp
+	eventMap                 // fields es una entrada JSON que codifica el writer
+	eventHeader              // el banner de WithHeader, que el writer arma en el formato vigente
+)
+
+// poolNews cuenta cuántas veces cada pool tuvo que asignar un buffer nuevo
+// (small, med, mid, big). Solo se incrementa en un miss, no en el hot path.
+var poolNews [4]uint64
+
 var (
-	smallPool = sync.Pool{New: func() interface{} { return make([]byte, 0, 512) }}
-	medPool   = sync.Pool{New: func() interface{} { return make([]byte, 0, 2048) }}
-	midPool   = sync.Pool{New: func() interface{} { return make([]byte, 0, 4096) }}
-	bigPool   = sync.Pool{New: func() interface{} { return make([]byte, 0, 8192) }}
+	smallPool = sync.Pool{New: func() interface{} { atomic.AddUint64(&poolNews[0], 1); return make([]byte, 0, 512) }}
+	medPool   = sync.Pool{New: func() interface{} { atomic.AddUint64(&poolNews[1], 1); return make([]byte, 0, 2048) }}
+	midPool   = sync.Pool{New: func() interface{} { atomic.AddUint64(&poolNews[2], 1); return make([]byte, 0, 4096) }}
+	bigPool   = sync.Pool{New: func() interface{} { atomic.AddUint64(&poolNews[3], 1); return make([]byte, 0, 8192) }}
 )
 
 // getBuf returns a small default buffer (legacy callers).
@@ -246,26 +529,123 @@ func putBuf(b []byte) {
 ///////////////////////////////////////
 
 func (_log *Log) StructuredJSON(state bool) {
-	_log.structured = state
+	if state {
+		_log.format.Store(Format.JSON)
+	} else {
+		_log.format.Store(Format.Text)
+	}
+	for _, shard := range _log.shards {
+		shard.StructuredJSON(state)
+	}
+}
+
+// OutputFormat selects the line format: Format.Text (default), Format.JSON
+// (same as StructuredJSON(true)), Format.Logfmt, Format.CEF (see WithCEF),
+// Format.Syslog (RFC 5424, see WithSyslog) or Format.Pretty (the default
+// format on terminals, colored there unless NO_COLOR is set; FORCE_COLOR also
+// colors StartWriter destinations that are not terminals). Format.Custom
+// selects the Formatter given to WithFormatter. Format.Binary writes compact
+// length-prefixed records (see the binlog package); select it before logging,
+// since a file cannot mix it with lines. Unknown formats, and Format.Custom
+// without a Formatter, are ignored.
+func (_log *Log) OutputFormat(format string) {
+	switch format {
+	case Format.Text, Format.JSON, Format.Logfmt, Format.Pretty, Format.Binary:
+		_log.format.Store(format)
+	case Format.CEF:
+		if _log.cef == nil {
+			_log.cef = defaultCEF()
+		}
+		_log.format.Store(format)
+	case Format.Syslog:
+		if _log.syslog == nil {
+			_log.syslog = defaultSyslog()
+		}
+		_log.format.Store(format)
+	case Format.Custom:
+		if _log.formatter != nil {
+			_log.format.Store(format)
+		}
+	}
+	for _, shard := range _log.shards {
+		shard.OutputFormat(format)
+	}
+}
+
+// lineFormat devuelve el Format.* vigente. Es atómico porque OutputFormat
+// puede cambiarlo mientras la goroutine writer formatea.
+func (_log *Log) lineFormat() string {
+	if format, ok := _log.format.Load().(string); ok {
+		return format
+	}
+	return Format.Text
+}
+
+// structured reports whether entries are key/value (JSON or logfmt).
+func (_log *Log) structured() bool {
+	return _log.lineFormat() != Format.Text
 }
 
 func (_log *Log) Status() bool {
 	return _log.status
 }
 
-func (_log *Log) Dropped() uint64 { return 0 }
+// Dropped returns the number of records discarded because they were logged
+// after Close.
+func (_log *Log) Dropped() uint64 {
+	n := atomic.LoadUint64(&_log.dropped)
+	for _, shard := range _log.shards {
+		n += shard.Dropped()
+	}
+	return n
+}
 
 func (_log *Log) logfString(level string, data interface{}, args ...interface{}) {
+	if _log.recent != nil {
+		_log.remember(level, data, args)
+	}
 	if !_log.shouldLog(level) {
 		return
 	}
+	if _log.transforms != nil || _log.lineFormat() == Format.Binary {
+		msg, fields := _log.dataParts(data, args)
+		_log.transformFields(level, msg, fields)
+		return
+	}
+	if _log.maxMessageSize > 0 {
+		data, args = _log.capMessage(data, args)
+	}
+	if _log.escapeControl && _log.lineFormat() == Format.Text {
+		data, args = _log.escapeMessage(data, args)
+	}
+	if _log.sampler != nil && !_log.sample(level, sampleKey(data)) {
+		return
+	}
+	if _log.redact != nil {
+		if m, ok := data.(map[string]interface{}); ok {
+			data, _ = _log.redact.redactMap(m)
+		}
+	}
+	if level == Level.ERROR && _log.escalation != nil {
+		defer _log.escalate(escalationKey(data))
+	}
+	if level == Level.CRITICAL && _log.critMirror != nil {
+		_log.critMirror.mirror(_log.formatMessageString(data, args...))
+	}
+	stack := _log.stackFor(level)
 
-	if _log.structured {
+	if _log.structured() {
 		var fields map[string]interface{}
+		borrowed := false // fields es el mapa del llamador
 
 		if len(args) == 0 {
 			if f, ok := data.(map[string]interface{}); ok {
+				fields, borrowed = f, true
+			} else if f, ok := structFields(_log.encoder, data); ok {
 				fields = f
+				if _log.redact != nil {
+					fields, _ = _log.redact.redactMap(fields)
+				}
 			}
 		}
 
@@ -273,51 +653,182 @@ func (_log *Log) logfString(level string, data interface{}, args ...interface{})
 			msgStr := _log.formatMessageString(data, args...)
 			fields = map[string]interface{}{"msg": msgStr}
 		}
+		once := _log.takeOnceFields()
+		if id := _log.nextID(); id != "" || stack != "" || _log.goroutineID || len(_log.metaFields) > 0 || once != nil {
+			extended := make(map[string]interface{}, len(fields)+len(_log.metaFields)+len(once)+3)
+			for i := range _log.metaFields {
+				extended[_log.metaFields[i].Key] = _log.metaFields[i].Value()
+			}
+			for i := range once {
+				extended[once[i].Key] = once[i].Value()
+			}
+			for k, v := range fields {
+				extended[k] = v
+			}
+			if id != "" {
+				extended["id"] = id
+			}
+			if stack != "" {
+				extended["stack"] = stack
+			}
+			if _log.goroutineID {
+				extended["goroutine"] = goroutineID()
+			}
+			fields, borrowed = extended, false
+		}
 
-		raw := _log.formatStructuredLog(level, fields)
-		atomic.AddUint64(&_log.enqueueSeq, 1)
-		_log.message <- raw
+		ev := logEvent{level: uint8(levelRank(level)), kind: eventRaw, route: _log.mapRoute(fields)}
+		if _log.filters != nil {
+			msg, id, typed := mapEntryParts(fields)
+			ev.entry = _log.filterEntry(level, msg, id, typed)
+		}
+		switch _log.lineFormat() {
+		case Format.Logfmt:
+			ev.msgBytes = _log.formatLogfmt(level, fields)
+		case Format.CEF:
+			ev.msgBytes = _log.formatCEF(level, fields)
+		case Format.Syslog:
+			ev.msgBytes = _log.formatSyslog(level, fields)
+		case Format.Pretty:
+			ev.msgBytes = _log.formatPretty(level, fields)
+		case Format.Custom:
+			ev.msgBytes = _log.formatCustom(level, fields)
+		default:
+			// JSON: el writer codifica el mapa, pero Lazy, Stringer y los
+			// valores compuestos se resuelven aquí, en la goroutine que registra
+			fields = _log.resolveFields(fields, borrowed)
+			ev.kind, ev.fields, ev.ts = eventMap, fields, _log.mapEventTime()
+		}
+		if _log.dedup != nil && ev.kind == eventRaw {
+			ev.msgBytes, ev.dupLen = _log.mapIdentity(ev.msgBytes, level, fields)
+		}
+		_log.enqueue(ev)
 		return
 	}
-	// FAST: sin formato y sin '%'
-	if len(args) == 0 {
+	// FAST: sin formato y sin '%' (con IDs la línea se arma en el productor)
+	if len(args) == 0 && _log.idGen == nil && !_log.goroutineID && stack == "" {
 		if msgStr, ok := data.(string); ok {
 			if strings.IndexByte(msgStr, '%') == -1 {
-				atomic.AddUint64(&_log.enqueueSeq, 1)
-				_log.events <- logEvent{level: level, msgStr: msgStr, kind: 0}
+				_log.enqueue(logEvent{level: uint8(levelRank(level)), msgStr: msgStr, ts: _log.eventTime(), kind: eventString})
 				return
 			}
 		}
 	}
 
+	id := _log.nextID()
+	if stack != "" {
+		msgStr := _log.formatMessageString(data, args...)
+		raw := _log.setFormatBytesFromString(appendStackText(msgStr, stack), level, id)
+		_log.enqueueLine(raw, level, _log.filterEntry(level, msgStr, id, nil))
+		return
+	}
+	if format, ok := data.(string); ok && len(args) > 0 && _log.filters == nil {
+		// formato directo al buffer del pool, sin el string intermedio de Sprintf
+		if raw, ok := _log.setFormatBytesf(format, args, level, id); ok {
+			_log.enqueueLine(raw, level, nil)
+			return
+		}
+	}
+
 	msgStr := _log.formatMessageString(data, args...)
-	raw := _log.setFormatBytesFromString(msgStr, level)
-	atomic.AddUint64(&_log.enqueueSeq, 1)
-	_log.message <- raw
+	raw := _log.setFormatBytesFromString(msgStr, level, id)
+	_log.enqueueLine(raw, level, _log.filterEntry(level, msgStr, id, nil))
 }
 
 func (_log *Log) logfBytes(level string, msgBytes []byte) {
+	if _log.recent != nil {
+		_log.remember(level, msgBytes, nil)
+	}
 	if !_log.shouldLog(level) {
 		return
 	}
-	atomic.AddUint64(&_log.enqueueSeq, 1)
-	_log.events <- logEvent{level: level, msgBytes: msgBytes, kind: 1}
+	if _log.transforms != nil || _log.lineFormat() == Format.Binary {
+		_log.transformFields(level, string(msgBytes), nil)
+		return
+	}
+	if _log.maxMessageSize > 0 {
+		msgBytes = _log.truncateBytes(msgBytes)
+	}
+	if _log.escapeControl && _log.lineFormat() == Format.Text {
+		msgBytes = escapeControlBytes(msgBytes)
+	}
+	if _log.sampler != nil && !_log.sample(level, hashBytes(msgBytes)) {
+		return
+	}
+	if level == Level.ERROR && _log.escalation != nil {
+		defer _log.escalate(string(msgBytes))
+	}
+	if level == Level.CRITICAL && _log.critMirror != nil {
+		_log.critMirror.mirror(string(msgBytes))
+	}
+	if stack := _log.stackFor(level); stack != "" {
+		msgBytes = []byte(appendStackText(string(msgBytes), stack))
+	}
+	_log.enqueueBytes(level, msgBytes)
+}
+
+// enqueueLine sends a complete text line built by the producer, with the
+// entry filters will see (nil without filters).
+func (_log *Log) enqueueLine(raw []byte, level string, entry *Entry) {
+	var dupLen uint32
+	if _log.dedup != nil {
+		raw, dupLen = lineIdentity(raw)
+	}
+	_log.enqueue(logEvent{msgBytes: raw, level: uint8(levelRank(level)), kind: eventRaw, dupLen: dupLen, entry: entry})
+}
+
+// enqueueBytes sends a caller-owned message to the writer without copying it.
+func (_log *Log) enqueueBytes(level string, msgBytes []byte) {
+	if _log.lineFormat() == Format.Binary {
+		_log.writeFields(level, string(msgBytes), nil)
+		return
+	}
+	if _log.lineFormat() == Format.Custom {
+		msg, id := string(msgBytes), _log.nextID()
+		raw := _log.appendCustomEntry(getBufCap(64+len(msgBytes)), level, msg, id, nil)
+		_log.enqueue(logEvent{msgBytes: raw, level: uint8(levelRank(level)), kind: eventRaw, entry: _log.filterEntry(level, msg, id, nil)})
+		return
+	}
+	if _log.idGen != nil || _log.goroutineID {
+		msg, id := string(msgBytes), _log.nextID()
+		raw := _log.setFormatBytesFromString(msg, level, id)
+		_log.enqueueLine(raw, level, _log.filterEntry(level, msg, id, nil))
+		return
+	}
+	_log.enqueue(logEvent{level: uint8(levelRank(level)), msgBytes: msgBytes, ts: _log.eventTime(), kind: eventBytes})
 }
 
 func (_log *Log) shouldLog(level string) bool {
-	switch _log.level {
-	case Level.DEBUG:
-		return true
-	case Level.INFO:
-		return level == Level.INFO || level == Level.WARN || level == Level.ERROR || level == Level.CRITICAL
-	case Level.WARN:
-		return level == Level.WARN || level == Level.ERROR || level == Level.CRITICAL
-	case Level.ERROR:
-		return level == Level.ERROR || level == Level.CRITICAL
-	case Level.CRITICAL:
-		return level == Level.CRITICAL
+	rank := levelRank(level)
+	return rank >= 0 && int32(rank) >= atomic.LoadInt32(&_log.minLevel)
+}
+
+// SetLevel changes the minimum level while the logger is in use. Invalid
+// levels are ignored.
+func (_log *Log) SetLevel(level string) {
+	if rank := levelRank(strings.ToUpper(level)); rank >= 0 {
+		atomic.StoreInt32(&_log.minLevel, int32(rank))
 	}
-	return false
+}
+
+// Enabled reports whether entries at level would be logged, so callers can
+// skip preparing expensive data:
+//
+//	if log.Enabled(acacia.Level.DEBUG) {
+//		log.Debug("plan: %s", explain(query))
+//	}
+func (_log *Log) Enabled(level string) bool {
+	return _log.shouldLog(strings.ToUpper(level))
+}
+
+// DebugEnabled is Enabled(Level.DEBUG).
+func (_log *Log) DebugEnabled() bool {
+	return _log.shouldLog(Level.DEBUG)
+}
+
+// LogLevel returns the current minimum level.
+func (_log *Log) LogLevel() string {
+	return string(levelBytesFor(uint8(atomic.LoadInt32(&_log.minLevel))))
 }
 
 func (_log *Log) Info(data interface{}, args ...interface{}) {
@@ -360,12 +871,44 @@ func (_log *Log) DebugBytes(msg []byte) {
 	_log.logfBytes(Level.DEBUG, msg)
 }
 
+// Print logs its operands at INFO, formatted as fmt.Sprint does. With Printf
+// and Println it gives *Log the method set many third-party packages expect
+// from a logger.
+func (_log *Log) Print(v ...interface{}) {
+	if _log.shouldLog(Level.INFO) || _log.recent != nil {
+		_log.logfString(Level.INFO, fmt.Sprint(v...))
+	}
+}
+
+// Printf logs at INFO, formatted as fmt.Sprintf does.
+func (_log *Log) Printf(format string, v ...interface{}) {
+	_log.logfString(Level.INFO, format, v...)
+}
+
+// Println logs its operands at INFO, formatted as fmt.Sprintln does, without
+// the trailing newline.
+func (_log *Log) Println(v ...interface{}) {
+	if _log.shouldLog(Level.INFO) || _log.recent != nil {
+		msg := fmt.Sprintln(v...)
+		_log.logfString(Level.INFO, msg[:len(msg)-1])
+	}
+}
+
 func (_log *Log) Write(p []byte) (int, error) {
+	if _log.recent != nil {
+		_log.remember(Level.INFO, strings.TrimSuffix(string(p), "\n"), nil)
+	}
 	if !_log.shouldLog(Level.INFO) {
 		return len(p), nil
 	}
-	atomic.AddUint64(&_log.enqueueSeq, 1)
-	_log.events <- logEvent{level: Level.INFO, msgBytes: p, kind: 1}
+	msg := p
+	if _log.maxMessageSize > 0 {
+		msg = _log.truncateBytes(msg)
+	}
+	if _log.escapeControl && _log.lineFormat() == Format.Text {
+		msg = escapeControlBytes(msg)
+	}
+	_log.enqueueBytes(Level.INFO, msg)
 	return len(p), nil
 }
 
@@ -374,22 +917,29 @@ func (_log *Log) Rotation(sizeMB int, backup int) {
 		backup = 1
 	}
 	_log.maxRotation = backup
+	for _, shard := range _log.shards {
+		shard.Rotation(sizeMB, backup)
+	}
 
 	if sizeMB <= 0 {
 		_log.maxSize = 0
 		return
 	}
 	_log.maxSize = int64(sizeMB) * 1024 * 1024
+	_log.preallocateFile(_log.getFile())
 }
 
 func (_log *Log) DailyRotation(enabled bool) {
 	_log.mtx.Lock()
 	_log.daily = enabled
 	if enabled {
-		_log.lastDay = time.Now().Format(lastDayFormat)
+		_log.lastDay = _log.now().Format(lastDayFormat)
 		_log.forceDailyRotate = true
 	}
 	_log.mtx.Unlock()
+	for _, shard := range _log.shards {
+		shard.DailyRotation(enabled)
+	}
 }
 
 // app.log → app-2025-11-18.log
@@ -404,8 +954,7 @@ func (_log *Log) rotateByDate(day string) error {
 	_log.mtx.Unlock()
 
 	// baseName-YYYY-MM-DD.ext
-	ext := filepath.Ext(name)
-	baseNoExt := strings.TrimSuffix(name, ext)
+	baseNoExt, ext := splitLogExt(name)
 	datedName := fmt.Sprintf("%s-%s%s", baseNoExt, day, ext)
 	datedBase := filepath.Join(dir, datedName)
 
@@ -413,6 +962,7 @@ func (_log *Log) rotateByDate(day string) error {
 	if limit <= 0 {
 		limit = 1000 // Límite de seguridad
 	}
+	limit = _log.rotationLimit(datedBase, limit)
 
 	// Rotar backups fechados: dated.N -> dated.(N+1)
 	for i := limit - 1; i >= 0; i-- {
@@ -420,26 +970,42 @@ func (_log *Log) rotateByDate(day string) error {
 		dst := fmt.Sprintf("%s.%d", datedBase, i+1)
 		if _, err := os.Stat(src); err == nil {
 			if err := os.Rename(src, dst); err != nil {
-				reportInternalError("rotating dated backup file %s: %v", src, err)
+				_log.reportError("rotating dated backup file %s: %v", src, err)
 			}
 		}
 	}
 
+	// un archivo fechado del mismo día (rotación forzada) pasa a ser el .0
+	if _, err := os.Stat(datedBase); err == nil {
+		if err := os.Rename(datedBase, datedBase+".0"); err != nil {
+			_log.reportError("rotating dated file %s: %v", datedBase, err)
+		}
+	}
+	_log.writeFileFooter(oldFile, datedBase, base)
+	oldSize := _log.currentSize // con el footer incluido
 	if err := os.Rename(base, datedBase); err != nil {
-		reportInternalError("renaming base file to dated: %v", err)
+		_log.reportError("renaming base file to dated: %v", err)
 	}
+	_log.rotatedTo = datedBase
 
-	newFile, err := os.OpenFile(base, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	newFile, err := openLogFile(base, _log.fileMode)
 	if err != nil {
-		reportInternalError("opening new file after daily rotation: %v", err)
+		_log.reportError("opening new file after daily rotation: %v", err)
 		return err
 	}
+	_log.preallocateFile(newFile)
 	_log.setFile(newFile)
 	_log.currentSize = 0
 
 	if oldFile != nil {
+		_log.releasePreallocation(oldFile, oldSize)
+		if _log.syncPolicy.kind != syncNever {
+			if err := syncFile(oldFile); err != nil {
+				_log.reportError("fsync old file before daily rotation: %v", err)
+			}
+		}
 		if err := oldFile.Close(); err != nil {
-			reportInternalError("closing old file after daily rotation: %v", err)
+			_log.reportError("closing old file after daily rotation: %v", err)
 		}
 	}
 	return nil
@@ -451,73 +1017,147 @@ func (_log *Log) logRotate() error {
 	oldFile := _log.getFile()
 	maxRot := _log.maxRotation
 	dailyEnabled := _log.daily
-	today := time.Now().Format(lastDayFormat)
+	today := _log.now().Format(lastDayFormat)
 	_log.mtx.Unlock()
 
 	targetStem := base
 	if dailyEnabled {
 		dir, name := filepath.Dir(base), filepath.Base(base)
-		ext := filepath.Ext(name)
-		baseNoExt := strings.TrimSuffix(name, ext)
+		baseNoExt, ext := splitLogExt(name)
 		datedName := fmt.Sprintf("%s-%s%s", baseNoExt, today, ext)
 		targetStem = filepath.Join(dir, datedName)
 	}
 
 	// Rotar la cadena existente targetStem.(n) -> targetStem.(n+1)
+	maxRot = _log.rotationLimit(targetStem, maxRot)
 	for i := maxRot - 1; i >= 0; i-- {
 		src := fmt.Sprintf("%s.%d", targetStem, i)
 		dst := fmt.Sprintf("%s.%d", targetStem, i+1)
 		if _, err := os.Stat(src); err == nil {
 			if err := os.Rename(src, dst); err != nil {
-				reportInternalError("rotating file %s: %v", src, err)
+				_log.reportError("rotating file %s: %v", src, err)
 			}
 		}
 	}
 
 	firstBackup := targetStem + ".0"
+	_log.writeFileFooter(oldFile, firstBackup, base)
+	oldSize := _log.currentSize // con el footer incluido
 	if err := os.Rename(base, firstBackup); err != nil {
-		reportInternalError("renaming base file for size rotation: %v", err)
+		_log.reportError("renaming base file for size rotation: %v", err)
 	}
+	_log.rotatedTo = firstBackup
 
-	newFile, err := os.OpenFile(base, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	newFile, err := openLogFile(base, _log.fileMode)
 	if err != nil {
-		reportInternalError("opening new file: %v", err)
+		_log.reportError("opening new file: %v", err)
 		return err
 	}
+	_log.preallocateFile(newFile)
 	_log.setFile(newFile)
 	_log.currentSize = 0
 
 	if oldFile != nil {
+		_log.releasePreallocation(oldFile, oldSize)
+		if _log.syncPolicy.kind != syncNever {
+			if err := syncFile(oldFile); err != nil {
+				_log.reportError("fsync old file before size rotation: %v", err)
+			}
+		}
 		if err := oldFile.Close(); err != nil {
-			reportInternalError("closing old file after size rotation: %v", err)
+			_log.reportError("closing old file after size rotation: %v", err)
 		}
 	}
 	return nil
 }
 
-func (_log *Log) Close() {
+// Close drains and writes everything logged before the call, fsyncs and
+// closes the files, and stops the logger's goroutines. It returns the first
+// write, fsync or close error, or ErrWriterStalled if the writer did not drain
+// in time. Calls after the first return ErrLoggerClosed.
+func (_log *Log) Close() error {
+	err := ErrLoggerClosed
 	_log.closeOnce.Do(func() {
-		if _log.done != nil {
-			close(_log.done)
+		err = _log.close()
+		if shardErr := _log.closeShards(); err == nil {
+			err = shardErr
 		}
-		if _log.timeTicker != nil {
-			_log.timeTicker.Stop()
+		if _log.errs.owner == _log {
+			_log.errs.close()
 		}
+	})
+	return err
+}
 
-		if _log.events != nil {
-			close(_log.events)
+// close does the work of Close, once.
+func (_log *Log) close() error {
+	var first error
+	keep := func(err error) {
+		if first == nil {
+			first = err
 		}
-		close(_log.message)
-		_log.wg.Wait()
-		if f := _log.getFile(); f != nil {
-			if err := f.Sync(); err != nil {
-				reportInternalError("final file sync error: %v", err)
-			}
-			if err := f.Close(); err != nil {
-				reportInternalError("final file close error: %v", err)
-			}
+	}
+	// barrera previa: todo lo encolado antes de Close queda escrito aunque
+	// el cierre de canales se complique más abajo
+	if err := _log.Barrier(); err != nil {
+		_log.reportError("close barrier: %v", err)
+		keep(err)
+	}
+	atomic.StoreInt32(&_log.closed, 1)
+	if _log.done != nil {
+		close(_log.done)
+	}
+	if _log.timeTicker != nil {
+		_log.timeTicker.Stop()
+	}
+	if _log.sharedTS {
+		_log.unshareTimestamps()
+	}
+	_log.stopDiagnosticsSignal()
+	_log.wg.Wait()
+	if _log.writeErr != nil {
+		keep(_log.writeErr)
+	}
+	if _log.spill != nil {
+		if err := _log.spill.close(); err != nil {
+			_log.reportError("closing spill file: %v", err)
+			keep(err)
 		}
-	})
+	}
+	if _log.retry != nil {
+		if err := _log.retry.close(); err != nil {
+			_log.reportError("closing retry spool: %v", err)
+			keep(err)
+		}
+	}
+	if _log.sink != nil {
+		if err := _log.syncOut(); err != nil {
+			_log.reportError("final writer sync error: %v", err)
+			keep(err)
+		}
+	}
+	if err := _log.closeGzip(); err != nil {
+		_log.reportError("final compressed stream error: %v", err)
+		keep(err)
+	}
+	if f := _log.getFile(); f != nil {
+		if err := syncFile(f); err != nil {
+			_log.reportError("final file sync error: %v", err)
+			keep(err)
+		}
+		if err := f.Close(); err != nil {
+			_log.reportError("final file close error: %v", err)
+			keep(err)
+		}
+	}
+	_log.untrackOpenLog()
+	if err := _log.closeLevelFiles(); err != nil {
+		keep(err)
+	}
+	if _log.mirror != nil {
+		_log.closeMirror()
+	}
+	return first
 }
 
 ///////////////////////////////////////
@@ -533,72 +1173,275 @@ func Start(logName, logPath, logLevel string, opts ...Option) (*Log, error) {
 	}
 	logPath = filepath.Clean(logPath) + string(os.PathSeparator)
 
+	cfg := newConfig(opts)
+	if cfg.createDir != 0 {
+		if err := os.MkdirAll(logPath, cfg.createDir); err != nil {
+			return nil, err
+		}
+	}
 	if _, err := os.Stat(logPath); os.IsNotExist(err) {
 		return nil, fmt.Errorf("path %s does not exist", logPath)
 	}
 
-	logLevel = strings.ToUpper(logLevel)
-	if !verifyLevel(logLevel) {
-		reportInternalError("warning: invalid log level '%s', falling back to INFO", logLevel)
-		logLevel = Level.INFO
+	logName = gzipName(cfg, logName)
+	if cfg.writers > 1 && cfg.ack == nil {
+		return startSharded(logName, logPath, logLevel, cfg)
 	}
-
-	fullPath := filepath.Join(logPath, logName)
-	f, err := os.OpenFile(fullPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
-	if err != nil {
-		return nil, err
+	var f *os.File
+	if !cfg.lazyOpen {
+		var err error
+		f, err = openLogFile(filepath.Join(logPath, logName), cfg.fileMode)
+		if err != nil {
+			return nil, err
+		}
 	}
 
+	return startLog(logName, logPath, logLevel, cfg, f, nil), nil
+}
+
+///////////////////////////////////////
+// P R I V A T E   F U N C T I O N S //
+///////////////////////////////////////
+
+func newConfig(opts []Option) *config {
 	cfg := &config{
-		bufferSize: DefaultBufferSize,
-		batchSize:  DefaultBatchSize,
-		flushEvery: flushInterval,
+		bufferSize:      DefaultBufferSize,
+		batchSize:       DefaultBatchSize,
+		flushEvery:      flushInterval,
+		healthThreshold: DefaultHealthThreshold,
+		encoder:         defaultEncoder,
+		clock:           time.Now,
+		fileMode:        defaultFileMode,
 	}
 	for _, opt := range opts {
 		opt(cfg)
 	}
+	return cfg
+}
 
-	// header := fmt.Sprintf("=== HumanJuan Logger v%s started at %s ===\n", version, time.Now().Format(time.RFC3339))
-	// _, _ = f.WriteString(header)
+// startLog builds the logger around f (nil: not open yet) or, for
+// StartWriter, sink, and starts its goroutines.
+func startLog(logName, logPath, logLevel string, cfg *config, f *os.File, sink io.Writer) *Log {
+	logLevel = strings.ToUpper(logLevel)
+	if !verifyLevel(logLevel) {
+		reportInternalError("warning: invalid log level '%s', falling back to INFO", logLevel)
+		logLevel = Level.INFO
+	}
+	fullPath := filepath.Join(logPath, logName)
+	gzipOut := cfg.gzipOut && sink == nil
 
 	log := &Log{
-		name:        logName,
-		path:        logPath,
-		level:       logLevel,
-		maxSize:     0,
-		maxRotation: 0,
-		daily:       false,
-		lastDay:     time.Now().Format(lastDayFormat),
-		status:      true,
-		message:     make(chan []byte, cfg.bufferSize),
-		events:      make(chan logEvent, 4096),
-		buffer:      make([]byte, 0, cfg.batchSize),
-		writeBuf:    make([]byte, 0, cfg.batchSize),
-		flushEvery:  cfg.flushEvery,
-		done:        make(chan struct{}),
-		control:     make(chan controlReq, 8),
-	}
-
-	log.file.Store(f)
+		name:            logName,
+		path:            logPath,
+		minLevel:        int32(levelRank(logLevel)),
+		maxSize:         0,
+		maxRotation:     0,
+		daily:           false,
+		lastDay:         cfg.clock().Format(lastDayFormat),
+		status:          true,
+		queue:           newEventRing(cfg.bufferSize),
+		wake:            make(chan struct{}, 1),
+		buffer:          make([]byte, 0, cfg.batchSize),
+		writeBuf:        make([]byte, 0, cfg.batchSize),
+		flushEvery:      cfg.flushEvery,
+		done:            make(chan struct{}),
+		control:         make(chan controlReq, 8),
+		diagPath:        cfg.diagPath,
+		escalation:      cfg.escalation,
+		healthThreshold: cfg.healthThreshold,
+		idGen:           cfg.idGen,
+		lazyOpen:        cfg.lazyOpen,
+		syncPolicy:      cfg.syncPolicy,
+		lastSync:        time.Now(),
+		critMirror:      cfg.critMirror,
+		preallocate:     cfg.preallocate && !gzipOut,
+		preciseTS:       cfg.preciseTS && !cfg.noTimestamp,
+		epochUnit:       cfg.epochUnit,
+		encoder:         cfg.encoder,
+		cef:             cfg.cef,
+		syslog:          cfg.syslog,
+		formatter:       cfg.formatter,
+		stackLevel:      cfg.stackLevel,
+		goroutineID:     cfg.goroutineID,
+		metaFields:      cfg.metaFields,
+		onceFields:      cfg.onceFields,
+		redact:          cfg.redact,
+		sampler:         cfg.sampler,
+		dedup:           cfg.dedup,
+		quota:           cfg.quota,
+		severities:      cfg.severities,
+		filters:         cfg.filters,
+		filterMtx:       cfg.filterMtx,
+		transforms:      cfg.transforms,
+		levelFiles:      cfg.levelFiles,
+		mirror:          cfg.mirror,
+		recent:          cfg.recent,
+		clock:           cfg.clock,
+		schema:          defaultJSONSchema,
+		sink:            sink,
+		router:          cfg.router,
+		ack:             cfg.ack,
+		archive:         cfg.archive,
+		fileMode:        cfg.fileMode,
+		maxMessageSize:  cfg.maxMessageSize,
+		escapeControl:   cfg.escapeControl,
+		header:          cfg.header,
+		fileHeader:      cfg.fileHeader,
+		fileFooter:      cfg.fileFooter,
+		recoverPartial:  cfg.recoverPartial && !gzipOut,
+		noTimestamp:     cfg.noTimestamp,
+		staticPrefix:    cfg.staticPrefix,
+		levelTags:       defaultLevelTags,
+		breakerFailures: cfg.breakerFailures,
+		breakerCooldown: cfg.breakerCooldown,
+		gzipOut:         gzipOut,
+		strictUTF8:      cfg.strictUTF8,
+	}
+	if log.errs = cfg.errs; log.errs == nil {
+		log.errs = newErrSink(logName)
+		log.errs.owner = log
+	}
+	if len(log.onceFields) > 0 {
+		log.oncePending = 1
+	}
+	for _, lf := range log.levelFiles {
+		lf.errs = log.errs
+	}
+	if log.router != nil {
+		log.router.all = append([]*levelFile(nil), log.levelFiles...)
+		log.router.errs = log.errs
+	}
+	if log.critMirror != nil {
+		log.critMirror.name = logName
+		log.critMirror.redact = cfg.redact
+	}
+	if log.diagPath == "" {
+		log.diagPath = fullPath + ".diag"
+	}
 
-	if info, err := f.Stat(); err == nil {
-		log.currentSize = info.Size()
+	log.setFile(f)
+
+	if cfg.levelLabels != nil || cfg.levelPadding {
+		log.levelTags = newLevelTags(cfg.levelLabels, cfg.levelPadding)
+	}
+	log.recoverPartialLine(f)
+	if cfg.retryPath != "" && sink != nil {
+		retry, err := openRetrySpool(cfg.retryPath, cfg.retryMax)
+		if err != nil {
+			log.reportError("opening retry spool: %v", err)
+		} else {
+			retry.errs = log.errs
+			log.retry = retry
+		}
+	}
+	if cfg.spillThreshold > 0 && sink == nil {
+		spill, err := openSpill(fullPath+".wal", cfg.spillThreshold)
+		if err != nil {
+			log.reportError("opening spill file: %v", err)
+		} else {
+			log.spill = spill
+		}
+	}
+	if cfg.chainKey != nil {
+		log.chain = newHashChain(cfg.chainKey)
+		switch {
+		case log.spill != nil && log.spill.pending():
+			// lo pendiente de una caída va detrás del archivo principal
+			log.chain.resume(log.spill.f)
+		case f != nil:
+			log.chain.resume(f)
+		}
+	}
+	if f != nil {
+		if info, err := f.Stat(); err == nil {
+			log.currentSize = info.Size()
+		}
 	}
+	console := false
+	if out, ok := sink.(*os.File); ok && f == nil {
+		f, console = out, true
+	}
+	if f != nil {
+		log.color = colorOutput(f, console)
+		if isTerminal(f) {
+			log.format.Store(Format.Pretty)
+		}
+	}
+	tsLayout := defaultTimestampFormat
+	if cfg.jsonSchema != nil {
+		log.schema = cfg.jsonSchema
+		log.format.Store(Format.JSON)
+		tsLayout = TS.RFC3339Nano
+	}
+	if cfg.cef != nil {
+		log.format.Store(Format.CEF)
+	}
+	if cfg.syslog != nil {
+		log.format.Store(Format.Syslog)
+	}
+	if cfg.formatter != nil {
+		log.format.Store(Format.Custom)
+	}
+	log.tsFormat.Store(tsLayout)
 	log.updateTimestampCache()
-	log.timeTicker = time.NewTicker(cacheInterval)
-	log.wg.Add(1)
-	go log.startTimestampCacheUpdater()
+	log.publishState()
+	if f != nil {
+		log.writeFileHeader(f, "")
+	}
+	if log.header != nil {
+		// el banner espera al primer registro para salir en el formato que
+		// se elija justo después de Start
+		log.headerStart = log.now()
+		log.headerPending = 1
+	}
+	switch {
+	case cfg.noTimestamp:
+		// el prefijo fijo no cambia
+	case cfg.preciseTS:
+		// WithClock: la caché debe leer el reloj propio
+		log.timeTicker = time.NewTicker(cacheInterval)
+		log.wg.Add(1)
+		go log.startTimestampCacheUpdater()
+	default:
+		log.sharedTS = true
+		log.shareTimestamps()
+	}
+
+	if log.mirror != nil {
+		// sin WithCircuitBreaker el espejo se pausa tras cada error
+		log.mirror.errs = log.errs
+		log.mirror.brk = newBreaker("mirror file "+log.mirror.path, 1, mirrorRetry, log.errs)
+		if cfg.breakerFailures > 0 {
+			log.mirror.brk = newBreaker("mirror file "+log.mirror.path, cfg.breakerFailures, cfg.breakerCooldown, log.errs)
+		}
+		go log.mirror.run()
+	}
+
+	if cfg.noWriter {
+		// padre de WithWriters: sus registros van directo a los archivos
+		return log
+	}
+	log.trackOpenLog()
+
+	// AuditMode confirma cada registro tras su fsync: ese lo hace el writer
+	if log.syncPolicy.kind != syncNever && sink == nil && log.ack == nil {
+		log.syncReq = make(chan struct{}, 1)
+		log.wg.Add(1)
+		go log.runSyncer()
+	}
+
+	if cfg.disk != nil && sink == nil && canCheckDisk {
+		log.wg.Add(1)
+		go log.monitorDisk(cfg.disk)
+	}
 
 	log.wg.Add(1)
+	atomic.StoreInt32(&log.writerAlive, 1)
 	go log.startWriting()
 
-	return log, nil
+	return log
 }
 
-///////////////////////////////////////
-// P R I V A T E   F U N C T I O N S //
-///////////////////////////////////////
-
 func reportInternalError(format string, args ...interface{}) {
 	_, err := fmt.Fprintf(os.Stderr, "Acacia Internal: "+format+"\n", args...)
 
@@ -621,10 +1464,15 @@ func (_log *Log) startTimestampCacheUpdater() {
 }
 
 func (_log *Log) updateTimestampCache() {
+	if _log.noTimestamp {
+		// el "timestamp" es el prefijo fijo, vacío sin WithStaticPrefix
+		_log.cachedTime.Store([]byte(_log.staticPrefix))
+		return
+	}
 	buf := getBuf()
 	defer putBuf(buf)
-	now := time.Now()
-	buf = now.AppendFormat(buf, timestampFormat)
+	now := _log.now()
+	buf = now.AppendFormat(buf, _log.timestampLayout())
 	cachedCopy := make([]byte, len(buf))
 	copy(cachedCopy, buf)
 	_log.cachedTime.Store(cachedCopy)
@@ -632,6 +1480,7 @@ func (_log *Log) updateTimestampCache() {
 
 func (_log *Log) startWriting() {
 	defer _log.wg.Done()
+	defer atomic.StoreInt32(&_log.writerAlive, 0)
 	interval := _log.flushEvery
 	if interval <= 0 {
 		interval = flushInterval
@@ -639,295 +1488,523 @@ func (_log *Log) startWriting() {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	batch := make([][]byte, 0, 1024)
-
-	levelBytesFor := func(lvl string) []byte {
-		switch lvl {
-		case Level.DEBUG:
-			return levelDebug
-		case Level.INFO:
-			return levelInfo
-		case Level.WARN:
-			return levelWarn
-		case Level.ERROR:
-			return levelError
-		case Level.CRITICAL:
-			return levelCritical
-		default:
-			return levelInfo
-		}
-	}
-	appendLine := func(dst []byte, ts []byte, lvl []byte, msg string) []byte {
-		if len(ts) > 0 {
-			dst = append(dst, ts...)
-		}
-		dst = append(dst, ' ')
-		dst = append(dst, '[')
-		dst = append(dst, lvl...)
-		dst = append(dst, ']', ' ')
-		dst = append(dst, msg...)
-		if len(dst) == 0 || dst[len(dst)-1] != '\n' {
-			dst = append(dst, '\n')
-		}
-		return dst
-	}
-	appendLineBytes := func(dst []byte, ts []byte, lvl []byte, msg []byte) []byte {
-		if len(ts) > 0 {
-			dst = append(dst, ts...)
-		}
-		dst = append(dst, ' ')
-		dst = append(dst, '[')
-		dst = append(dst, lvl...)
-		dst = append(dst, ']', ' ')
-		dst = append(dst, msg...)
-		if len(dst) == 0 || dst[len(dst)-1] != '\n' {
-			dst = append(dst, '\n')
-		}
-		return dst
-	}
-
 	for {
-		select {
-		case first, ok := <-_log.message:
-			if !ok {
-				if len(batch) > 0 {
-					_log.mtx.Lock()
-					for i := range batch {
-						_log.buffer = append(_log.buffer, batch[i]...)
-						putBuf(batch[i])
-					}
-					_log.mtx.Unlock()
-					batch = batch[:0]
-				}
-				// vaciar eventos pendientes antes de finalizar
-				for {
-					select {
-					case ev, ok2 := <-_log.events:
-						if !ok2 {
-							_log.events = nil
-							goto events_drained_on_close
-						}
-						var ts []byte
-						if cachedTS := _log.cachedTime.Load(); cachedTS != nil {
-							ts = cachedTS.([]byte)
-						}
-						lvl := levelBytesFor(ev.level)
-						_log.mtx.Lock()
-						_log.buffer = appendLine(_log.buffer, ts, lvl, ev.msgStr)
-						_log.mtx.Unlock()
-					default:
-						goto events_drained_on_close
-					}
-				}
-			events_drained_on_close:
+		if _log.drainQueues() > 0 {
+			if _log.ack != nil || _log.bufferAboveThreshold(interval) {
 				_log.flush()
-				return
 			}
-
-			batch = append(batch, first)
-			qlen := len(_log.message)
-			drainLimit := 256
-
-			if qlen > 10_000 {
-				drainLimit = 4096
-			} else if qlen > 1000 {
-				drainLimit = 1024
+			// con carga continua el writer no se estaciona: atender ticker,
+			// control y cierre sin bloquear
+			select {
+			case <-ticker.C:
+				_log.flush()
+			case req := <-_log.control:
+				_log.handleControl(req)
+			case <-_log.done:
+				_log.finish()
+				return
+			default:
 			}
+			continue
+		}
 
-			if qlen > 1000 && cap(batch) < 2048 {
-				nb := make([][]byte, 0, 2048)
-				nb = append(nb, batch...)
-				batch = nb
-			}
-			for i := 1; i < drainLimit; i++ {
-				select {
-				case msg := <-_log.message:
-					batch = append(batch, msg)
-				default:
-					i = drainLimit
-				}
-			}
+		// sin trabajo: estacionar el writer hasta que un productor lo despierte.
+		// Se vuelve a mirar la cola después de publicar writerParked para no
+		// perder un despertar (los productores leen writerParked tras publicar).
+		atomic.StoreInt32(&_log.writerParked, 1)
+		if _log.queue.len() > 0 {
+			atomic.StoreInt32(&_log.writerParked, 0)
+			continue
+		}
+		select {
+		case <-_log.wake:
+		case <-ticker.C:
+			_log.flush()
+		case req := <-_log.control:
+			_log.handleControl(req)
+		case <-_log.done:
+			atomic.StoreInt32(&_log.writerParked, 0)
+			_log.finish()
+			return
+		}
+		atomic.StoreInt32(&_log.writerParked, 0)
+	}
+}
 
-			_log.mtx.Lock()
-			for i := range batch {
-				_log.buffer = append(_log.buffer, batch[i]...)
-				putBuf(batch[i])
-			}
-			// Dispara flush más agresivo cuando el intervalo es corto (<= 100ms):
-			// umbral = 2/3 de la capacidad; de lo contrario, 1/2 como antes.
-			capBuf := cap(_log.buffer)
-			threshold := capBuf / 2
-			if interval <= 100*time.Millisecond {
-				threshold = (capBuf * 2) / 3
-			}
-			shouldFlush := len(_log.buffer) >= threshold
-			_log.mtx.Unlock()
-			atomic.AddUint64(&_log.dequeueSeq, uint64(len(batch)))
-			batch = batch[:0]
+// drainQueues moves up to a burst of queued events into the batch buffer and
+// returns how many were consumed. Writer goroutine only.
+func (_log *Log) drainQueues() int {
+	var ts []byte
+	if cachedTS := _log.cachedTime.Load(); cachedTS != nil {
+		ts = cachedTS.([]byte)
+	}
+	layout := _log.timestampLayout()
+	utc := atomic.LoadInt32(&_log.utc) == 1
+
+	// vaciar en ráfagas más grandes cuando la cola está cargada
+	limit := 256
+	if qlen := _log.queue.len(); qlen > 10_000 {
+		limit = 4096
+	} else if qlen > 1000 {
+		limit = 1024
+	}
 
-			if shouldFlush {
-				_log.flush()
+	n := 0
+	var now int64 // para registros binarios sin timestamp del productor
+	_log.mtx.Lock()
+	for n < limit {
+		ev, ok := _log.queue.pop()
+		if !ok {
+			break
+		}
+		n++
+		if ev.kind == eventHeader {
+			ev.msgBytes, ev.kind = _log.headerRecord(false), eventRaw
+			if _log.lineFormat() == Format.Binary {
+				ev.kind = eventBinary
 			}
-
-		case ev, ok := <-_log.events:
-			if !ok {
-				_log.events = nil
-				break
+		}
+		if _log.filters != nil && !_log.passesFilters(&ev) {
+			if ev.kind == eventRaw {
+				putBuf(ev.msgBytes)
 			}
-			processed := 0
-			var ts []byte
-			if cachedTS := _log.cachedTime.Load(); cachedTS != nil {
-				ts = cachedTS.([]byte)
+			continue
+		}
+		if _log.quota != nil {
+			_log.appendQuotaNotice(ts)
+			if !_log.quota.admit(ev.level) {
+				if ev.kind == eventRaw || ev.kind == eventBinary {
+					putBuf(ev.msgBytes)
+				}
+				continue
 			}
-			lvl := levelBytesFor(ev.level)
-			_log.mtx.Lock()
-			if ev.kind == 0 {
-				_log.buffer = appendLine(_log.buffer, ts, lvl, ev.msgStr)
-			} else { // kind == 1 (bytes)
-				_log.buffer = appendLineBytes(_log.buffer, ts, lvl, ev.msgBytes)
+		}
+		if _log.dedup != nil && _log.suppressRepeat(ts, &ev) {
+			continue
+		}
+		if ev.kind == eventMap {
+			ev.msgBytes = _log.formatStructuredLog(levelString(ev.level), ev.fields, ev.ts)
+			ev.kind, ev.fields = eventRaw, nil
+		}
+		start := len(_log.buffer)
+		if ev.kind == eventBinary {
+			if ev.ts == 0 {
+				if now == 0 {
+					now = _log.now().UnixNano()
+				}
+				ev.ts = now
 			}
-			capBuf := cap(_log.buffer)
-			threshold := capBuf / 2
-			if interval <= 100*time.Millisecond {
-				threshold = (capBuf * 2) / 3
+			prev := _log.binPrev
+			_log.buffer = _log.appendBinaryRecord(_log.buffer, ev.ts, ev.msgBytes)
+			putBuf(ev.msgBytes)
+			if _log.quota != nil {
+				_log.quota.charge(len(_log.buffer) - start)
 			}
-			shouldFlush := len(_log.buffer) >= threshold
-			_log.mtx.Unlock()
-			processed++
-
-			// vaciar más eventos disponibles en ráfagas
-			evDrain := 256
-			qlen := len(_log.events)
-			if qlen > 10_000 {
-				evDrain = 4096
-			} else if qlen > 1000 {
-				evDrain = 1024
+			if ev.route != "" {
+				_log.copyToLevelFiles(start, ev.level, true)
+				_log.routeLine(start, ev.route, true)
+				// el siguiente delta del archivo principal es respecto a su registro anterior
+				_log.binPrev = prev
+				continue
 			}
-			for i := 0; i < evDrain; i++ {
-				select {
-				case ev2 := <-_log.events:
-					lvl2 := levelBytesFor(ev2.level)
-					_log.mtx.Lock()
-					if ev2.kind == 0 {
-						_log.buffer = appendLine(_log.buffer, ts, lvl2, ev2.msgStr)
-					} else {
-						_log.buffer = appendLineBytes(_log.buffer, ts, lvl2, ev2.msgBytes)
-					}
-					if !shouldFlush {
-						capBuf := cap(_log.buffer)
-						threshold := capBuf / 2
-						if interval <= 100*time.Millisecond {
-							threshold = (capBuf * 2) / 3
-						}
-						if len(_log.buffer) >= threshold {
-							shouldFlush = true
-						}
-					}
-					_log.mtx.Unlock()
-					processed++
-				default:
-					i = evDrain
+		} else {
+			if ev.kind == eventRaw && len(ev.msgBytes) >= vectorMinLine && ev.route == "" && _log.vectored() &&
+				(!_log.strictUTF8 || _log.lineValid(ev.msgBytes)) {
+				// línea grande: va al kernel tal cual, sin copiarla al lote
+				if _log.quota != nil {
+					_log.quota.charge(len(ev.msgBytes))
 				}
+				_log.holdLine(ev.msgBytes)
+				continue
 			}
-			if processed > 0 {
-				atomic.AddUint64(&_log.dequeueSeq, uint64(processed))
+			_log.buffer = appendEvent(_log.buffer, ts, layout, utc, _log.levelTags, &ev)
+			if _log.strictUTF8 {
+				_log.enforceValid(start, ev.level)
 			}
-			if shouldFlush {
-				_log.flush()
+			if _log.quota != nil {
+				_log.quota.charge(len(_log.buffer) - start)
+			}
+			if ev.route != "" {
+				// sin cadena de hashes: esta línea no va al archivo principal
+				if _log.redact != nil && len(_log.redact.scrubbers) > 0 {
+					_log.buffer = _log.redact.redactLine(_log.buffer, start)
+				}
+				_log.copyToLevelFiles(start, ev.level, false)
+				_log.routeLine(start, ev.route, false)
+				continue
 			}
+			_log.sealLine(start)
+		}
+		if _log.levelFiles != nil {
+			_log.copyToLevelFiles(start, ev.level, ev.kind == eventBinary)
+		}
+	}
+	if _log.quota != nil && n > 0 {
+		// solo quien escribió debe el aviso (el padre de WithWriters nunca)
+		_log.appendQuotaNotice(ts)
+	}
+	if n > 0 {
+		_log.publishState()
+	}
+	_log.mtx.Unlock()
+	return n
+}
 
-		case <-ticker.C:
-			_log.flush()
+// sealLine applies redaction patterns and the hash chain to the line that
+// starts at start in the batch buffer. Writer goroutine only, with mtx held.
+func (_log *Log) sealLine(start int) {
+	if _log.redact != nil && len(_log.redact.scrubbers) > 0 {
+		_log.buffer = _log.redact.redactLine(_log.buffer, start)
+	}
+	if _log.chain != nil {
+		_log.buffer = _log.chain.seal(_log.buffer, start)
+	}
+}
 
+// bufferAboveThreshold dispara flush más agresivo cuando el intervalo es
+// corto (<= 100ms): umbral = 2/3 de la capacidad; de lo contrario, 1/2.
+func (_log *Log) bufferAboveThreshold(interval time.Duration) bool {
+	_log.mtx.Lock()
+	capBuf := cap(_log.buffer)
+	threshold := capBuf / 2
+	if interval <= 100*time.Millisecond {
+		threshold = (capBuf * 2) / 3
+	}
+	above := len(_log.buffer)+_log.segBytes >= threshold
+	_log.mtx.Unlock()
+	return above
+}
+
+// handleControl drains and flushes until every event enqueued before the
+// request has been written, then runs the request hook and acks it.
+func (_log *Log) handleControl(req controlReq) {
+	for {
+		drained := _log.drainQueues()
+		if drained > 0 && _log.queue.dequeued() < req.target {
+			continue
+		}
+		_log.flush()
+		if _log.queue.dequeued() >= req.target {
+			break
+		}
+		if atomic.LoadInt32(&_log.closed) == 1 && drained == 0 {
+			// productores descartados tras Close nunca llegarán al target
+			break
+		}
+		// un productor reservó secuencia pero aún no publicó su slot
+		runtime.Gosched()
+	}
+	if _log.dedup != nil {
+		_log.flushRepeats(true)
+		_log.flush()
+	}
+	if _log.spill != nil {
+		_log.drainSpill()
+	}
+	if _log.retry != nil && _log.retry.pending() {
+		_log.shipBatch(nil, true)
+	}
+	if _log.gz != nil {
+		_log.gzipFlushPoint(true)
+	}
+	if req.run != nil {
+		req.run()
+	}
+	if req.ack != nil {
+		close(req.ack)
+	}
+}
+
+// finish drains everything still queued at Close, flushes it and answers any
+// pending control request.
+func (_log *Log) finish() {
+	for _log.drainQueues() > 0 {
+	}
+	if _log.dedup != nil {
+		_log.flushRepeats(true)
+	}
+	_log.flush()
+	if _log.spill != nil {
+		_log.drainSpill()
+	}
+	if _log.retry != nil && _log.retry.pending() {
+		_log.shipBatch(nil, true)
+	}
+	for {
+		select {
 		case req := <-_log.control:
-			for {
-				drained := make([][]byte, 0, 1024)
-				drainedCount := 0
-				for {
-					select {
-					case msg := <-_log.message:
-						drained = append(drained, msg)
-						drainedCount++
-					default:
-						goto drained_done
-					}
-				}
-			drained_done:
-				if drainedCount > 0 {
-					_log.mtx.Lock()
-					for i := range drained {
-						_log.buffer = append(_log.buffer, drained[i]...)
-						putBuf(drained[i])
-					}
-					_log.mtx.Unlock()
-				}
+			_log.handleControl(req)
+		default:
+			return
+		}
+	}
+}
 
-				evCount := 0
-				var ts2 []byte
-				if cachedTS := _log.cachedTime.Load(); cachedTS != nil {
-					ts2 = cachedTS.([]byte)
-				}
-				for {
-					select {
-					case ev := <-_log.events:
-						lvl := levelBytesFor(ev.level)
-						_log.mtx.Lock()
-						if ev.kind == 0 {
-							_log.buffer = appendLine(_log.buffer, ts2, lvl, ev.msgStr)
-						} else {
-							_log.buffer = appendLineBytes(_log.buffer, ts2, lvl, ev.msgBytes)
-						}
-						_log.mtx.Unlock()
-						evCount++
-					default:
-						goto drained_events_done
-					}
-				}
-			drained_events_done:
-				_log.flush()
+// eventTime returns the producer-side timestamp of an event, or 0 to use the
+// writer's cached timestamp.
+func (_log *Log) eventTime() int64 {
+	if !_log.preciseTS {
+		return 0
+	}
+	return _log.clock().UnixNano()
+}
 
-				if drainedCount > 0 {
-					atomic.AddUint64(&_log.dequeueSeq, uint64(drainedCount))
-				}
-				if evCount > 0 {
-					atomic.AddUint64(&_log.dequeueSeq, uint64(evCount))
-				}
+// mapEventTime returns the producer-side time of a map entry the writer
+// encodes, when precise or epoch timestamps need it, and 0 otherwise.
+func (_log *Log) mapEventTime() int64 {
+	if (!_log.preciseTS && _log.epochUnit == 0) || _log.noTimestamp {
+		return 0
+	}
+	return _log.clock().UnixNano()
+}
 
-				if atomic.LoadUint64(&_log.dequeueSeq) >= req.target {
-					if req.ack != nil {
-						close(req.ack)
-					}
-					break
-				}
+// resolveFields applies fieldValue to the first level of m, so Lazy and
+// method-based values run on the logging goroutine and not on the writer.
+// Composite values (maps, slices, pointers, structs) are encoded here too:
+// the caller may change them as soon as the call returns, so only scalars
+// reach the writer as they are. A borrowed map belongs to the caller, who may
+// reuse it, and is copied.
+func (_log *Log) resolveFields(m map[string]interface{}, borrowed bool) map[string]interface{} {
+	out := m
+	if borrowed {
+		out = make(map[string]interface{}, len(m))
+	}
+	for k, v := range m {
+		out[k] = _log.ownedValue(fieldValue(v))
+	}
+	return out
+}
+
+// ownedValue devuelve v si es un escalar y, si no, su JSON ya codificado.
+func (_log *Log) ownedValue(v interface{}) interface{} {
+	switch v.(type) {
+	case nil, string, bool, int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v
+	}
+	encoded, err := _log.encoder.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("!Marshal error: %v", err)
+	}
+	return json.RawMessage(encoded)
+}
+
+// levelNames maps levelRank to the level names.
+var levelNames = [...]string{Level.DEBUG, Level.INFO, Level.WARN, Level.ERROR, Level.CRITICAL}
+
+// levelString is levelBytesFor as a string, without allocating.
+func levelString(rank uint8) string {
+	if int(rank) >= len(levelNames) {
+		return Level.INFO
+	}
+	return levelNames[rank]
+}
+
+func levelBytesFor(rank uint8) []byte {
+	switch rank {
+	case 0:
+		return levelDebug
+	case 1:
+		return levelInfo
+	case 2:
+		return levelWarn
+	case 3:
+		return levelError
+	case 4:
+		return levelCritical
+	default:
+		return levelInfo
+	}
+}
+
+// appendEvent formats ev into dst. Raw events are already complete lines and
+// their pooled buffer is returned to the pool. layout (in UTC when utc is set)
+// formats per-entry timestamps; tags are the level tags of the logger.
+func appendEvent(dst []byte, ts []byte, layout string, utc bool, tags *levelTags, ev *logEvent) []byte {
+	if ev.kind == eventRaw {
+		dst = append(dst, ev.msgBytes...)
+		putBuf(ev.msgBytes)
+		return dst
+	}
+	if ev.ts != 0 {
+		t := time.Unix(0, ev.ts)
+		if utc {
+			t = t.UTC()
+		}
+		dst = t.AppendFormat(dst, layout)
+		dst = append(dst, ' ')
+	} else if len(ts) > 0 {
+		dst = append(dst, ts...)
+		dst = append(dst, ' ')
+	}
+	dst = append(dst, tags.tag(ev.level)...)
+	if ev.kind == eventString {
+		dst = append(dst, ev.msgStr...)
+	} else {
+		dst = append(dst, ev.msgBytes...)
+	}
+	if len(dst) == 0 || dst[len(dst)-1] != '\n' {
+		dst = append(dst, '\n')
+	}
+	return dst
+}
+
+// Barrier blocks until every record enqueued before the call (that is, every
+// logging call that returned before Barrier was called, from any goroutine)
+// has been written and fsynced. Records logged concurrently with Barrier may
+// or may not be covered. Records enqueued after Barrier returns are never
+// reordered before the ones it covered.
+//
+// It returns the first write or fsync error since the previous barrier,
+// ErrLoggerClosed if the logger is closed and ErrWriterStalled if the writer
+// does not answer in time.
+func (_log *Log) Barrier() error {
+	if _log.shards != nil {
+		return _log.eachShard((*Log).Barrier)
+	}
+	var syncErr error
+	run := func() {
+		syncErr, _log.writeErr = _log.writeErr, nil
+		if err := _log.syncOut(); err != nil && syncErr == nil {
+			syncErr = err
+		}
+		if err := _log.syncLevelFiles(); err != nil && syncErr == nil {
+			syncErr = err
+		}
+		_log.unsynced = false
+		_log.lastSync = time.Now()
+	}
+	if err := _log.barrier(run, barrierTimeout); err != nil {
+		return err
+	}
+	return syncErr
+}
+
+// Sync is Barrier: it returns once everything logged before the call is
+// written and fsynced, with the first write or fsync error since the
+// previous Sync, or a timeout.
+func (_log *Log) Sync() error {
+	return _log.Barrier()
+}
+
+// Flush is Sync without the fsync: it returns once everything logged before
+// the call has been written to the file, so it survives a crash of the
+// process but not necessarily of the machine. It returns the first write
+// error since the previous Flush or Sync, ErrLoggerClosed or
+// ErrWriterStalled.
+func (_log *Log) Flush() error {
+	if _log.shards != nil {
+		return _log.eachShard((*Log).Flush)
+	}
+	var writeErr error
+	run := func() {
+		writeErr, _log.writeErr = _log.writeErr, nil
+	}
+	if err := _log.barrier(run, barrierTimeout); err != nil {
+		return err
+	}
+	return writeErr
+}
+
+// Reopen writes what was logged before the call, then closes the log file
+// and opens its path again, for external tools (logrotate with `create`,
+// backup scripts) that move the file while the process keeps running. Level
+// files are reopened too. If the path cannot be opened, the current file is
+// kept and the error is returned.
+func (_log *Log) Reopen() error {
+	if _log.shards != nil {
+		return _log.eachShard((*Log).Reopen)
+	}
+	var reopenErr error
+	run := func() {
+		if _log.sink != nil {
+			_log.reopenLevelFiles()
+			return
+		}
+		old, oldSize := _log.getFile(), _log.currentSize
+		if err := _log.openFile(); err != nil {
+			reopenErr = err
+			return
+		}
+		if old != nil {
+			_log.releasePreallocation(old, oldSize)
+			if err := syncFile(old); err != nil {
+				_log.reportError("fsync old file before reopen: %v", err)
+			}
+			if err := old.Close(); err != nil {
+				_log.reportError("closing old file on reopen: %v", err)
 			}
 		}
+		_log.reopenLevelFiles()
+	}
+	if err := _log.barrier(run, barrierTimeout); err != nil {
+		return err
 	}
+	return reopenErr
 }
 
-func (_log *Log) Sync() {
-	target := atomic.LoadUint64(&_log.enqueueSeq)
+// barrier asks the writer to drain everything enqueued so far and then run fn
+// on the writer goroutine, with no rotation or write in progress.
+func (_log *Log) barrier(fn func(), wait time.Duration) error {
+	select {
+	case <-_log.done:
+		return ErrLoggerClosed
+	default:
+	}
+	if atomic.LoadInt32(&_log.headerPending) == 1 && (_log.getFile() != nil || _log.sink != nil) {
+		// nada registrado todavía: el banner de un archivo ya abierto sale
+		// ahora; con WithLazyOpen espera al primer registro
+		_log.takeHeader()
+	}
+	if _log.shards != nil {
+		// sin writer propio: basta con que cada archivo llegue a la barrera
+		err := _log.eachShard(func(shard *Log) error { return shard.barrier(nil, wait) })
+		if err == nil && fn != nil {
+			fn()
+		}
+		return err
+	}
+	target := _log.queue.enqueued()
 	ack := make(chan struct{})
-	req := controlReq{target: target, ack: ack}
+	req := controlReq{target: target, ack: ack, run: fn}
 
 	select {
 	case _log.control <- req:
-		// ok
+	case <-_log.done:
+		return ErrLoggerClosed
 	case <-time.After(2 * time.Second):
 		// fallback: no bloquear al caller si el writer no responde
+		return ErrWriterStalled
 	}
 
 	select {
 	case <-ack:
-	case <-time.After(5 * time.Second):
-	}
-	if f := _log.getFile(); f != nil {
-		_ = f.Sync()
+		return nil
+	case <-time.After(wait):
+		return ErrWriterStalled
 	}
 }
 
 func (_log *Log) flush() {
+	if _log.dedup != nil {
+		_log.flushRepeats(false)
+	}
+	atomic.StoreInt64(&_log.lastFlush, time.Now().UnixNano())
+	deq := _log.queue.dequeued()
+	if _log.ack != nil {
+		defer _log.ack.advance(deq)
+	}
+	defer func() {
+		_log.mtx.Lock()
+		_log.publishState()
+		_log.mtx.Unlock()
+	}()
 	_log.mtx.Lock()
 	_log.buffer, _log.writeBuf = _log.writeBuf[:0], _log.buffer
+	segs, segSize := _log.takeSegments()
+	for _, lf := range _log.sideFiles() {
+		lf.buf, lf.out = lf.out[:0], lf.buf
+	}
 
 	needDaily := false
 	dayForRotate := ""
@@ -936,7 +2013,7 @@ func (_log *Log) flush() {
 			needDaily = true
 			dayForRotate = _log.lastDay
 		} else {
-			today := time.Now().Format(lastDayFormat)
+			today := _log.now().Format(lastDayFormat)
 			if today != _log.lastDay {
 				needDaily = true
 				dayForRotate = _log.lastDay
@@ -944,24 +2021,91 @@ func (_log *Log) flush() {
 		}
 	}
 	_log.mtx.Unlock()
+	_log.flushLevelFiles()
+	if _log.router != nil {
+		_log.flushRoutes()
+	}
+	if _log.mirror != nil {
+		_log.mirror.send(_log.writeBuf)
+	}
+
+	if segs != nil {
+		// lote vectorizado: un solo writev salvo que haya que partirlo por
+		// líneas (rotación) o abrir el archivo
+		f := _log.getFile()
+		if f != nil && !needDaily && (_log.maxSize <= 0 || _log.currentSize+segSize <= _log.maxSize) {
+			_log.writeSegments(f, segs)
+			_log.applySyncPolicy(deq, true)
+			_log.writeBuf = _log.writeBuf[:0]
+			return
+		}
+		_log.joinSegments(segs)
+	}
 
 	remaining := _log.writeBuf
+	if _log.spill != nil {
+		if remaining = _log.routeSpill(remaining); remaining == nil {
+			_log.writeBuf = _log.writeBuf[:0]
+			return
+		}
+	}
+
+	if _log.sink != nil {
+		// StartWriter: sin archivo ni rotación
+		if _log.retry != nil {
+			_log.shipBatch(remaining, false)
+		} else if len(remaining) > 0 {
+			_log.writeOut(_log.sink, remaining)
+		}
+		_log.applySyncPolicy(deq, len(remaining) > 0)
+		_log.writeBuf = _log.writeBuf[:0]
+		return
+	}
+
+	if _log.getFile() == nil {
+		// apertura diferida (WithLazyOpen): el archivo se crea con el primer registro
+		if len(remaining) == 0 {
+			return
+		}
+		if err := _log.openFile(); err != nil {
+			_log.reportError("opening log file on first record: %v", err)
+			_log.writeBuf = _log.writeBuf[:0]
+			return
+		}
+	}
 
 	if needDaily {
 		if f := _log.getFile(); f != nil && len(remaining) > 0 {
-			if written, _ := f.Write(remaining); written > 0 {
-				_log.currentSize += int64(written)
-			}
+			_log.writeOut(f, remaining)
+		}
+		if _log.rotateByDate(dayForRotate) == nil {
+			_log.writeRotationHeader()
 		}
-		_ = _log.rotateByDate(dayForRotate)
 		_log.mtx.Lock()
-		_log.lastDay = time.Now().Format(lastDayFormat)
+		_log.lastDay = _log.now().Format(lastDayFormat)
 		_log.forceDailyRotate = false
 		_log.mtx.Unlock()
+		if _log.gz != nil {
+			_log.gzipFlushPoint(false)
+		}
+		_log.applySyncPolicy(deq, len(_log.writeBuf) > 0)
 		_log.writeBuf = _log.writeBuf[:0]
 		return
 	}
 
+	// Format.Binary: el primer registro de un archivo recién rotado debe
+	// llevar timestamp absoluto
+	binary := _log.lineFormat() == Format.Binary
+	var binTS int64
+	rotated := false
+	var fresh int64 // tamaño de un archivo recién rotado (su banner)
+	consume := func(n int) {
+		if binary {
+			binTS = binaryRecordTS(remaining[:n], binTS)
+		}
+		remaining = remaining[n:]
+		rotated = false
+	}
 	for len(remaining) > 0 {
 		f := _log.getFile()
 		if f == nil {
@@ -969,49 +2113,57 @@ func (_log *Log) flush() {
 		}
 
 		if _log.maxSize <= 0 {
-			if written, _ := f.Write(remaining); written > 0 {
-				_log.currentSize += int64(written)
-			}
+			_log.writeOut(f, remaining)
 			remaining = remaining[:0]
 			break
 		}
 
-		lineEnd := bytes.IndexByte(remaining, '\n')
-		var line []byte
-		if lineEnd >= 0 {
-			line = remaining[:lineEnd+1]
-		} else {
-			line = remaining
+		n := _log.recordEnd(remaining)
+		line := remaining[:n]
+		if binary && rotated {
+			line = appendBinaryAbsolute(nil, line, binaryRecordTS(line, binTS))
 		}
 
 		cur := _log.currentSize
 		if cur >= _log.maxSize {
-			_ = _log.logRotate()
+			fresh = _log.rotateForSize()
+			rotated = true
 			continue
 		}
 		allowed := _log.maxSize - cur
-		if int64(len(line)) > allowed && cur > 0 {
-			_ = _log.logRotate()
+		if int64(len(line)) > allowed && cur > fresh {
+			fresh = _log.rotateForSize()
+			rotated = true
 			continue
 		}
 
-		if int64(len(line)) > allowed && cur == 0 {
-			if written, _ := f.Write(line); written > 0 {
-				_log.currentSize += int64(written)
-			}
-			remaining = remaining[len(line):]
-			_ = _log.logRotate()
+		if int64(len(line)) > allowed && cur <= fresh {
+			_log.writeOut(f, line)
+			consume(n)
+			fresh = _log.rotateForSize()
+			rotated = true
 			continue
 		}
 
-		if written, _ := f.Write(line); written > 0 {
-			_log.currentSize += int64(written)
-		}
-		remaining = remaining[len(line):]
+		_log.writeOut(f, line)
+		consume(n)
+	}
+	if _log.gz != nil {
+		_log.gzipFlushPoint(false)
 	}
+	_log.applySyncPolicy(deq, len(_log.writeBuf) > 0)
 	_log.writeBuf = _log.writeBuf[:0]
 }
 
+// rotateForSize rotates by size and returns the size of the new file (the
+// banner, with HeaderConfig.OnRotate).
+func (_log *Log) rotateForSize() int64 {
+	if err := _log.logRotate(); err != nil {
+		return 0
+	}
+	return _log.writeRotationHeader()
+}
+
 func (_log *Log) formatMessageString(data interface{}, args ...interface{}) string {
 	if len(args) == 0 {
 		switch v := data.(type) {
@@ -1026,25 +2178,48 @@ func (_log *Log) formatMessageString(data interface{}, args ...interface{}) stri
 	return fmt.Sprintf(data.(string), args...)
 }
 
-func (_log *Log) formatStructuredLog(level string, fields map[string]interface{}) []byte {
-	var ts string
-	if cachedTS := _log.cachedTime.Load(); cachedTS != nil {
+// formatStructuredLog encodes a map entry as a JSON line. at is the time
+// taken by the producer (see mapEventTime), or 0 to use the current one.
+func (_log *Log) formatStructuredLog(level string, fields map[string]interface{}, at int64) []byte {
+	var ts interface{}
+	if _log.epochUnit != 0 {
+		if at == 0 {
+			at = _log.clock().UnixNano()
+		}
+		ts = at / int64(_log.epochUnit)
+	} else if at != 0 {
+		t := time.Unix(0, at)
+		if atomic.LoadInt32(&_log.utc) == 1 {
+			t = t.UTC()
+		}
+		ts = t.Format(_log.timestampLayout())
+	} else if cachedTS := _log.cachedTime.Load(); cachedTS != nil && !_log.preciseTS {
 		ts = string(cachedTS.([]byte))
 	} else {
-		ts = time.Now().Format(timestampFormat)
+		ts = _log.now().Format(_log.timestampLayout())
 	}
 
+	s := _log.schema
 	finalFields := make(map[string]interface{}, len(fields)+2)
-	finalFields["ts"] = ts
-	finalFields["level"] = level
+	if !_log.noTimestamp {
+		finalFields[s.ts] = ts
+	}
+	finalFields[s.level] = s.levelName(level)
+	if n, ok := _log.severity(level); ok {
+		finalFields[severityKey(s)] = n
+	}
 
 	for k, v := range fields {
-		finalFields[k] = v
+		if k == "msg" {
+			k = s.msg
+		}
+		finalFields[k] = fieldValue(v)
 	}
 
-	jsonBytes, err := json.Marshal(finalFields)
+	jsonBytes, err := _log.encoder.Marshal(finalFields)
 	if err != nil {
-		fallback := fmt.Sprintf(`{"ts":"%s","level":"CRITICAL","msg":"Acacia JSON Marshal failed: %v"}`, ts, err)
+		tsJSON, _ := json.Marshal(ts)
+		fallback := fmt.Sprintf(`%s%s%s%s","%s":"Acacia JSON Marshal failed: %v"}`, s.tsPrefix, tsJSON, s.levelPrefix, s.levelName(Level.CRITICAL), s.msg, err)
 		return []byte(fallback)
 	}
 
@@ -1055,49 +2230,118 @@ func (_log *Log) formatStructuredLog(level string, fields map[string]interface{}
 	return buf
 }
 
-func (_log *Log) setFormatBytesFromString(msg string, level string) []byte {
-	var tsBytes []byte
-	if cachedTS := _log.cachedTime.Load(); cachedTS != nil {
-		tsBytes = cachedTS.([]byte)
+func (_log *Log) setFormatBytesFromString(msg string, level string, id string) []byte {
+	buf := _log.lineHeader(len(msg), level, id)
+	buf = append(buf, msg...)
+	if len(buf) == 0 || buf[len(buf)-1] != '\n' {
+		buf = append(buf, '\n')
 	}
+	return buf
+}
 
-	var levelBytes []byte
-	switch level {
-	case Level.DEBUG:
-		levelBytes = levelDebug
-	case Level.INFO:
-		levelBytes = levelInfo
-	case Level.WARN:
-		levelBytes = levelWarn
-	case Level.ERROR:
-		levelBytes = levelError
-	case Level.CRITICAL:
-		levelBytes = levelCritical
+// setFormatBytesf is setFormatBytesFromString with the message formatted by
+// appendf. It reports false (and releases the buffer) when the format needs fmt.
+func (_log *Log) setFormatBytesf(format string, args []interface{}, level string, id string) ([]byte, bool) {
+	buf := _log.lineHeader(len(format)+16*len(args), level, id)
+	buf, ok := appendf(buf, format, args)
+	if !ok {
+		putBuf(buf)
+		return nil, false
+	}
+	if len(buf) == 0 || buf[len(buf)-1] != '\n' {
+		buf = append(buf, '\n')
 	}
+	return buf, true
+}
 
-	need := len(tsBytes) + 1 + 1 + len(levelBytes) + 2 + len(msg) + 1
-	if need <= 0 {
-		need = 64 // fallback minimal
+// lineHeader returns a pooled buffer holding "<ts> [LEVEL] [id] [g<n>] " with
+// room for a message of about msgLen bytes.
+func (_log *Log) lineHeader(msgLen int, level string, id string) []byte {
+	var tsBytes []byte
+	if cachedTS := _log.cachedTime.Load(); cachedTS != nil {
+		tsBytes = cachedTS.([]byte)
 	}
+	levelTag := _log.levelTags.tag(uint8(levelRank(level)))
+
+	need := len(tsBytes) + 1 + len(levelTag) + len(id) + 3 + msgLen + 1
 	buf := getBufCap(need)
 
-	if len(tsBytes) > 0 {
+	if _log.preciseTS {
+		buf = _log.now().AppendFormat(buf, _log.timestampLayout())
+		buf = append(buf, ' ')
+	} else if len(tsBytes) > 0 {
 		buf = append(buf, tsBytes...)
+		buf = append(buf, ' ')
 	}
-	buf = append(buf, ' ')
-	buf = append(buf, '[')
-	buf = append(buf, levelBytes...)
-	buf = append(buf, ']', ' ')
-	buf = append(buf, msg...)
-	if len(buf) == 0 || buf[len(buf)-1] != '\n' {
-		buf = append(buf, '\n')
+	buf = append(buf, levelTag...)
+	buf = appendID(buf, id)
+	if _log.goroutineID {
+		buf = appendGoroutineTag(buf)
 	}
 	return buf
 }
 
+// TimestampFormat sets the timestamp layout of this logger only (see TS for
+// common layouts). It is safe to call while other goroutines are logging;
+// entries already queued may still carry the previous layout.
 func (_log *Log) TimestampFormat(format string) {
-	timestampFormat = format
+	_log.tsFormat.Store(format)
 	_log.updateTimestampCache()
+	for _, shard := range _log.shards {
+		shard.TimestampFormat(format)
+	}
+}
+
+// UseUTC makes timestamps (text and JSON) and daily-rotation date boundaries
+// use UTC instead of local time.
+func (_log *Log) UseUTC(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+	atomic.StoreInt32(&_log.utc, v)
+	_log.updateTimestampCache()
+	_log.mtx.Lock()
+	_log.lastDay = _log.now().Format(lastDayFormat)
+	_log.mtx.Unlock()
+	for _, shard := range _log.shards {
+		shard.UseUTC(enabled)
+	}
+}
+
+// now returns the current time in the logger's time zone.
+func (_log *Log) now() time.Time {
+	if atomic.LoadInt32(&_log.utc) == 1 {
+		return _log.clock().UTC()
+	}
+	return _log.clock()
+}
+
+// timestampLayout returns the layout set with TimestampFormat.
+func (_log *Log) timestampLayout() string {
+	if layout, ok := _log.tsFormat.Load().(string); ok {
+		return layout
+	}
+	return defaultTimestampFormat
+}
+
+// levelRank orders levels: DEBUG < INFO < WARN < ERROR < CRITICAL.
+// Unknown levels return -1.
+func levelRank(lvl string) int {
+	switch lvl {
+	case Level.DEBUG:
+		return 0
+	case Level.INFO:
+		return 1
+	case Level.WARN:
+		return 2
+	case Level.ERROR:
+		return 3
+	case Level.CRITICAL:
+		return 4
+	default:
+		return -1
+	}
 }
 
 func verifyLevel(lvl string) bool {
@@ -1116,7 +2360,55 @@ func (_log *Log) getFile() *os.File {
 	return nil
 }
 
+// openFile opens the base log file and resets the size accounting from disk.
+func (_log *Log) openFile() error {
+	f, err := openLogFile(filepath.Join(_log.path, _log.name), _log.fileMode)
+	if err != nil {
+		return err
+	}
+	_log.recoverPartialLine(f)
+	_log.currentSize = 0
+	if info, err := f.Stat(); err == nil {
+		_log.currentSize = info.Size()
+	}
+	if _log.chain != nil && _log.chain.prev == nil {
+		_log.chain.resume(f)
+	}
+	_log.setFile(f)
+	_log.writeFileHeader(f, "")
+	_log.preallocateFile(f)
+	return nil
+}
+
+// writeOut writes p to the log file, counting the bytes written and keeping
+// the first error for the next Sync or Close. Writer goroutine only.
+func (_log *Log) writeOut(w io.Writer, p []byte) {
+	if f, ok := w.(*os.File); ok && f == _log.gzFile && f != nil {
+		w, _log.gzDirty = _log.gz, true
+	}
+	written, err := w.Write(p)
+	if written > 0 {
+		_log.currentSize += int64(written)
+	}
+	if err != nil {
+		_log.keepWriteErr(err)
+	}
+}
+
+// syncFile fsyncs f. Terminals and pipes cannot be synced (EINVAL); for them
+// there is nothing to persist, so that is not an error.
+func syncFile(f interface{ Sync() error }) error {
+	err := f.Sync()
+	if errors.Is(err, syscall.EINVAL) || errors.Is(err, syscall.ENOTSUP) {
+		return nil
+	}
+	return err
+}
+
 func (_log *Log) setFile(f *os.File) {
+	if _log.gzipOut {
+		_log.startGzip(f)
+	}
 	if f != nil {
 		_log.file.Store(f)
 	} else {
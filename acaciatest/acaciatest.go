@@ -0,0 +1,155 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Package acaciatest provides an in-memory acacia logger and assertion
+// helpers, so unit tests can check what code logs without temp files or
+// sleeps.
+package acaciatest
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+// Logger is an *acacia.Log whose entries are kept in memory instead of being
+// written. Pass Logger.Log to the code under test.
+type Logger struct {
+	*acacia.Log
+	t       testing.TB
+	mtx     sync.Mutex
+	entries []acacia.Entry
+}
+
+// New returns a Logger at DEBUG level, closed when the test ends. opts are
+// applied as in acacia.Start; the entries observed are the ones that pass
+// every filter, after transforms and redaction. Observed entries are also
+// counted by Filtered.
+func New(t testing.TB, opts ...acacia.Option) *Logger {
+	t.Helper()
+	l := &Logger{t: t}
+	opts = append(opts, acacia.WithLazyOpen(), acacia.WithFilter(l.observe))
+	lg, err := acacia.Start(filepath.Base(os.DevNull), filepath.Dir(os.DevNull), acacia.Level.DEBUG, opts...)
+	if err != nil {
+		t.Fatalf("acaciatest: %v", err)
+	}
+	l.Log = lg
+	t.Cleanup(func() { lg.Close() })
+	return l
+}
+
+// observe is the last filter: it keeps the entry and drops it.
+func (l *Logger) observe(e acacia.Entry) bool {
+	l.mtx.Lock()
+	l.entries = append(l.entries, e)
+	l.mtx.Unlock()
+	return false
+}
+
+// ObservedEntries returns every entry logged so far, in order. Entries from
+// logging calls that returned before it are always included.
+func (l *Logger) ObservedEntries() []acacia.Entry {
+	l.Sync()
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return append([]acacia.Entry(nil), l.entries...)
+}
+
+// FilterLevel returns the observed entries at level.
+func (l *Logger) FilterLevel(level string) []acacia.Entry {
+	return l.filter(strings.ToUpper(level), "")
+}
+
+// FilterMessage returns the observed entries whose message contains substr.
+func (l *Logger) FilterMessage(substr string) []acacia.Entry {
+	return l.filter("", substr)
+}
+
+// Reset forgets the entries observed so far.
+func (l *Logger) Reset() {
+	l.Sync()
+	l.mtx.Lock()
+	l.entries = nil
+	l.mtx.Unlock()
+}
+
+// AssertLogged fails the test unless an entry at level ("" for any) has a
+// message containing substr.
+func (l *Logger) AssertLogged(level, substr string) {
+	l.t.Helper()
+	if len(l.filter(strings.ToUpper(level), substr)) == 0 {
+		l.t.Errorf("acaciatest: no %s entry containing %q; logged:\n%s", levelName(level), substr, l.dump())
+	}
+}
+
+// AssertNotLogged fails the test if an entry at level ("" for any) has a
+// message containing substr.
+func (l *Logger) AssertNotLogged(level, substr string) {
+	l.t.Helper()
+	if found := l.filter(strings.ToUpper(level), substr); len(found) > 0 {
+		l.t.Errorf("acaciatest: unexpected %s entry containing %q: [%s] %s", levelName(level), substr, found[0].Level, found[0].Message)
+	}
+}
+
+func (l *Logger) filter(level, substr string) []acacia.Entry {
+	var out []acacia.Entry
+	for _, e := range l.ObservedEntries() {
+		if (level == "" || e.Level == level) && strings.Contains(e.Message, substr) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func (l *Logger) dump() string {
+	var b strings.Builder
+	for _, e := range l.ObservedEntries() {
+		b.WriteString("\t[" + e.Level + "] " + e.Message + "\n")
+	}
+	if b.Len() == 0 {
+		return "\t(nothing)\n"
+	}
+	return b.String()
+}
+
+func levelName(level string) string {
+	if level == "" {
+		return "logged"
+	}
+	return strings.ToUpper(level)
+}
@@ -0,0 +1,200 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// appendf formats like fmt.Sprintf directly into dst for the verbs that
+// dominate logging calls: %s, %d, %v, %f (with optional precision, e.g. %.2f)
+// and %%. It reports false as soon as it meets anything else (flags, width,
+// other verbs, argument count mismatch, unsupported types) so the caller can
+// fall back to fmt with identical output.
+func appendf(dst []byte, format string, args []interface{}) ([]byte, bool) {
+	argi := 0
+	for i := 0; i < len(format); {
+		c := format[i]
+		if c != '%' {
+			// copiar el tramo literal hasta el próximo '%'
+			j := i + 1
+			for j < len(format) && format[j] != '%' {
+				j++
+			}
+			dst = append(dst, format[i:j]...)
+			i = j
+			continue
+		}
+
+		i++
+		if i >= len(format) {
+			return dst, false
+		}
+		verb := format[i]
+		prec := -1
+		if verb == '.' {
+			j := i + 1
+			for j < len(format) && format[j] >= '0' && format[j] <= '9' {
+				j++
+			}
+			if j == i+1 || j >= len(format) || format[j] != 'f' {
+				return dst, false
+			}
+			p, err := strconv.Atoi(format[i+1 : j])
+			if err != nil {
+				return dst, false
+			}
+			prec = p
+			verb = 'f'
+			i = j
+		}
+		i++
+
+		if verb == '%' {
+			dst = append(dst, '%')
+			continue
+		}
+		if argi >= len(args) {
+			return dst, false
+		}
+		arg := args[argi]
+		argi++
+		if _, ok := arg.(fmt.Formatter); ok {
+			// Format manda sobre Error y String (p. ej. pkg/errors): se deja a fmt
+			return dst, false
+		}
+
+		var ok bool
+		switch verb {
+		case 's':
+			dst, ok = appendString(dst, arg, 's')
+		case 'd':
+			dst, ok = appendInt(dst, arg)
+		case 'v':
+			if dst, ok = appendString(dst, arg, 'v'); !ok {
+				if dst, ok = appendInt(dst, arg); !ok {
+					dst, ok = appendValue(dst, arg)
+				}
+			}
+		case 'f':
+			if prec < 0 {
+				prec = 6
+			}
+			dst, ok = appendFloat(dst, arg, 'f', prec)
+		}
+		if !ok {
+			return dst, false
+		}
+	}
+	return dst, argi == len(args)
+}
+
+func appendString(dst []byte, arg interface{}, verb byte) ([]byte, bool) {
+	switch v := arg.(type) {
+	case string:
+		return append(dst, v...), true
+	case []byte:
+		// %s imprime el contenido; %v imprime [1 2 3] y se deja a fmt
+		if verb != 's' {
+			return dst, false
+		}
+		return append(dst, v...), true
+	case error:
+		return appendMethod(dst, v.Error)
+	case fmt.Stringer:
+		return appendMethod(dst, v.String)
+	}
+	return dst, false
+}
+
+// appendMethod calls Error/String, falling back to fmt if it panics (fmt
+// prints nil receivers and panics in its own way).
+func appendMethod(dst []byte, fn func() string) (out []byte, ok bool) {
+	defer func() {
+		if recover() != nil {
+			out, ok = dst, false
+		}
+	}()
+	return append(dst, fn()...), true
+}
+
+func appendInt(dst []byte, arg interface{}) ([]byte, bool) {
+	switch v := arg.(type) {
+	case int:
+		return strconv.AppendInt(dst, int64(v), 10), true
+	case int8:
+		return strconv.AppendInt(dst, int64(v), 10), true
+	case int16:
+		return strconv.AppendInt(dst, int64(v), 10), true
+	case int32:
+		return strconv.AppendInt(dst, int64(v), 10), true
+	case int64:
+		return strconv.AppendInt(dst, v, 10), true
+	case uint:
+		return strconv.AppendUint(dst, uint64(v), 10), true
+	case uint8:
+		return strconv.AppendUint(dst, uint64(v), 10), true
+	case uint16:
+		return strconv.AppendUint(dst, uint64(v), 10), true
+	case uint32:
+		return strconv.AppendUint(dst, uint64(v), 10), true
+	case uint64:
+		return strconv.AppendUint(dst, v, 10), true
+	}
+	return dst, false
+}
+
+func appendFloat(dst []byte, arg interface{}, format byte, prec int) ([]byte, bool) {
+	switch v := arg.(type) {
+	case float64:
+		return strconv.AppendFloat(dst, v, format, prec, 64), true
+	case float32:
+		return strconv.AppendFloat(dst, float64(v), format, prec, 32), true
+	}
+	return dst, false
+}
+
+// appendValue covers the remaining %v cases handled without fmt.
+func appendValue(dst []byte, arg interface{}) ([]byte, bool) {
+	switch v := arg.(type) {
+	case bool:
+		return strconv.AppendBool(dst, v), true
+	case float64, float32:
+		// %v de un float es %g con la precisión mínima
+		return appendFloat(dst, v, 'g', -1)
+	}
+	return dst, false
+}
@@ -0,0 +1,145 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// auditFileMode is the permission of log files created in AuditMode.
+const auditFileMode os.FileMode = 0600
+
+// AuditMode is a preset for compliance-grade audit trails, meant for a
+// dedicated logger next to the fast application log:
+//
+//   - every logging call returns only once its record is written and fsynced;
+//   - the writer flushes and fsyncs after every batch (SyncEveryFlush);
+//   - rotation is archive-only: backups are shifted but never deleted, the
+//     backup count given to Rotation is ignored;
+//   - the log file and its backups are created with 0600 permissions;
+//   - records are hash-chained with key (see WithHashChain). A nil key leaves
+//     the chain off.
+//
+// Logging calls cost about one fsync; concurrent calls share it. Write and
+// fsync errors are not returned by the logging calls: check Sync.
+func AuditMode(key []byte) Option {
+	return func(conf *config) {
+		conf.ack = &ackState{wait: make(chan struct{})}
+		conf.syncPolicy = SyncEveryFlush
+		conf.archive = true
+		conf.fileMode = auditFileMode
+		WithHashChain(key)(conf)
+	}
+}
+
+// ackState lets AuditMode producers wait until the writer has flushed their
+// record.
+type ackState struct {
+	mu      sync.Mutex
+	durable uint64        // posición de cola ya escrita y sincronizada
+	wait    chan struct{} // se cierra y se renueva cada vez que durable avanza
+}
+
+// advance marks everything up to queue position deq as written. Writer
+// goroutine only.
+func (a *ackState) advance(deq uint64) {
+	a.mu.Lock()
+	if deq > a.durable {
+		a.durable = deq
+		close(a.wait)
+		a.wait = make(chan struct{})
+	}
+	a.mu.Unlock()
+}
+
+// waitFor blocks until the record at queue position seq has been flushed, the
+// logger closes, or barrierTimeout passes.
+func (_log *Log) waitFor(seq uint64) {
+	timeout := time.NewTimer(barrierTimeout)
+	defer timeout.Stop()
+	for {
+		a := _log.ack
+		a.mu.Lock()
+		durable, wait := a.durable, a.wait
+		a.mu.Unlock()
+		if durable >= seq {
+			return
+		}
+		_log.wakeWriter()
+		select {
+		case <-wait:
+		case <-_log.done:
+			return
+		case <-timeout.C:
+			_log.reportError("audit record %d not acknowledged after %v", seq, barrierTimeout)
+			return
+		}
+	}
+}
+
+// rotationLimit returns how many backups of stem the shift loop must move.
+// With archive-only rotation that is every existing backup, so none is
+// overwritten.
+func (_log *Log) rotationLimit(stem string, maxRot int) int {
+	if !_log.archive {
+		return maxRot
+	}
+	n := 0
+	for {
+		if _, err := os.Stat(fmt.Sprintf("%s.%d", stem, n)); err != nil {
+			return n
+		}
+		n++
+	}
+}
+
+// openLogFile opens a log file for appending. Files created with a mode
+// other than the default (AuditMode) are also narrowed if they already
+// existed.
+func openLogFile(name string, mode os.FileMode) (*os.File, error) {
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_APPEND|os.O_WRONLY, mode)
+	if err != nil {
+		return nil, err
+	}
+	if mode != defaultFileMode {
+		if err := f.Chmod(mode); err != nil {
+			reportInternalError("setting permissions of %s: %v", name, err)
+		}
+	}
+	return f, nil
+}
@@ -0,0 +1,80 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import "strings"
+
+// LogBatch logs many pre-built entries at once, in order, claiming their
+// queue slots in one go instead of one by one (ETL jobs, replay tools). Each
+// entry goes through the same level check, transforms, sampling and
+// redaction as a *Fields call with its Level, Message and Fields; a non-empty
+// ID is kept instead of a generated one. Time and Logger are ignored: entries
+// are stamped when logged, like any other record. Entries with an unknown
+// level are skipped.
+func (_log *Log) LogBatch(entries []Entry) {
+	if len(entries) == 0 {
+		return
+	}
+	evs := make([]logEvent, 0, len(entries))
+	var escalate []string
+	for i := range entries {
+		e := &entries[i]
+		level, msg, fields := strings.ToUpper(e.Level), e.Message, e.Fields
+		if _log.recent != nil && levelRank(level) >= 0 {
+			_log.rememberFields(level, msg, fields)
+		}
+		if !_log.shouldLog(level) {
+			continue
+		}
+		if _log.transforms != nil {
+			level, msg, fields = _log.transform(level, msg, fields)
+			if !_log.shouldLog(level) {
+				continue
+			}
+		}
+		ev, ok := _log.fieldsEvent(level, msg, e.ID, fields)
+		if !ok {
+			continue
+		}
+		evs = append(evs, ev)
+		if level == Level.ERROR && _log.escalation != nil {
+			escalate = append(escalate, msg)
+		}
+	}
+	_log.enqueueBatch(evs)
+	for _, msg := range escalate {
+		_log.escalate(msg)
+	}
+}
@@ -0,0 +1,228 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Format.Binary records are length-prefixed (see the binlog package for the
+// reader):
+//
+//	uvarint  n         bytes that follow
+//	byte     flags     level rank in bits 0-2; bit 7: ts is absolute
+//	varint   ts        unix nanoseconds, or the delta from the previous record
+//	string   msg       (uvarint length + bytes)
+//	string   id
+//	uvarint  nfields   then, per field: string key, byte type, value
+//
+// The first record of every write batch, and of every file after a rotation,
+// carries an absolute timestamp, so any file can be decoded on its own.
+const BinaryAbsoluteTS = 0x80
+
+// Field types of Format.Binary records.
+const (
+	BinaryString   byte = iota // string
+	BinaryInt                  // varint
+	BinaryUint                 // uvarint
+	BinaryFloat                // 8 bytes, IEEE 754 little endian
+	BinaryBool                 // 1 byte
+	BinaryDuration             // varint nanoseconds
+	BinaryTime                 // varint unix nanoseconds
+	BinaryJSON                 // string holding a JSON value
+)
+
+// appendBinaryPayload encodes the record without its length and timestamp:
+// the level byte followed by msg, id and fields. appendBinaryRecord completes
+// it on the writer goroutine.
+func (_log *Log) appendBinaryPayload(dst []byte, level, msg, id string, fields []Field) []byte {
+	dst = append(dst, byte(levelRank(level)))
+	dst = appendBinaryString(dst, msg)
+	dst = appendBinaryString(dst, id)
+	dst = appendUvarint(dst, uint64(len(fields)))
+	for i := range fields {
+		f := &fields[i]
+		dst = appendBinaryString(dst, f.Key)
+		switch f.kind {
+		case fieldString:
+			dst = append(dst, BinaryString)
+			dst = appendBinaryString(dst, f.str)
+		case fieldInt:
+			dst = append(dst, BinaryInt)
+			dst = appendVarint(dst, f.num)
+		case fieldUint:
+			dst = append(dst, BinaryUint)
+			dst = appendUvarint(dst, uint64(f.num))
+		case fieldFloat:
+			dst = append(dst, BinaryFloat)
+			dst = appendUint64LE(dst, uint64(f.num))
+		case fieldBool:
+			dst = append(dst, BinaryBool, byte(f.num))
+		case fieldDuration:
+			dst = append(dst, BinaryDuration)
+			dst = appendVarint(dst, f.num)
+		case fieldTime:
+			dst = append(dst, BinaryTime)
+			dst = appendVarint(dst, f.num)
+		case fieldError:
+			if f.iface == nil {
+				dst = append(dst, BinaryJSON)
+				dst = appendBinaryString(dst, "null")
+			} else {
+				dst = append(dst, BinaryString)
+				dst = appendBinaryString(dst, callString(f.iface, f.iface.(error).Error))
+			}
+		default:
+			v := fieldValue(f.iface)
+			if s, ok := v.(string); ok {
+				dst = append(dst, BinaryString)
+				dst = appendBinaryString(dst, s)
+			} else if encoded, err := _log.encoder.Marshal(v); err == nil {
+				dst = append(dst, BinaryJSON)
+				dst = appendUvarint(dst, uint64(len(encoded)))
+				dst = append(dst, encoded...)
+			} else {
+				dst = append(dst, BinaryString)
+				dst = appendBinaryString(dst, fmt.Sprint(f.iface))
+			}
+		}
+	}
+	return dst
+}
+
+func appendBinaryString(dst []byte, s string) []byte {
+	dst = appendUvarint(dst, uint64(len(s)))
+	return append(dst, s...)
+}
+
+// appendBinaryRecord frames a payload from appendBinaryPayload with its length
+// and timestamp, absolute for the first record of the batch. Writer goroutine
+// only.
+func (_log *Log) appendBinaryRecord(dst []byte, ts int64, payload []byte) []byte {
+	if len(payload) == 0 {
+		return dst
+	}
+	flags, delta := payload[0], ts-_log.binPrev
+	if len(dst) == 0 {
+		flags |= BinaryAbsoluteTS
+		delta = ts
+	}
+	_log.binPrev = ts
+	var hdr [1 + binary.MaxVarintLen64]byte
+	hdr[0] = flags
+	n := 1 + binary.PutVarint(hdr[1:], delta)
+	dst = appendUvarint(dst, uint64(n+len(payload)-1))
+	dst = append(dst, hdr[:n]...)
+	return append(dst, payload[1:]...)
+}
+
+// binaryRecordLen returns the length of the first record in p, or len(p) if
+// it is truncated.
+func binaryRecordLen(p []byte) int {
+	n, k := binary.Uvarint(p)
+	if k <= 0 || n > uint64(len(p)-k) {
+		return len(p)
+	}
+	return k + int(n)
+}
+
+// binaryRecordTS returns the absolute timestamp of rec, given the absolute
+// timestamp of the record before it.
+func binaryRecordTS(rec []byte, prev int64) int64 {
+	_, k := binary.Uvarint(rec)
+	if k <= 0 || k >= len(rec) {
+		return prev
+	}
+	ts, m := binary.Varint(rec[k+1:])
+	if m <= 0 {
+		return prev
+	}
+	if rec[k]&BinaryAbsoluteTS != 0 {
+		return ts
+	}
+	return prev + ts
+}
+
+// appendBinaryAbsolute appends rec to dst with its timestamp rewritten as the
+// absolute ts, for records that start a file.
+func appendBinaryAbsolute(dst, rec []byte, ts int64) []byte {
+	_, k := binary.Uvarint(rec)
+	if k <= 0 || k >= len(rec) {
+		return append(dst, rec...)
+	}
+	_, m := binary.Varint(rec[k+1:])
+	if m <= 0 {
+		return append(dst, rec...)
+	}
+	body := rec[k+1+m:]
+	var hdr [1 + binary.MaxVarintLen64]byte
+	hdr[0] = rec[k] | BinaryAbsoluteTS
+	n := 1 + binary.PutVarint(hdr[1:], ts)
+	dst = appendUvarint(dst, uint64(n+len(body)))
+	dst = append(dst, hdr[:n]...)
+	return append(dst, body...)
+}
+
+// recordEnd returns the length of the first record (line) in p.
+func (_log *Log) recordEnd(p []byte) int {
+	if _log.lineFormat() == Format.Binary {
+		return binaryRecordLen(p)
+	}
+	for i, c := range p {
+		if c == '\n' {
+			return i + 1
+		}
+	}
+	return len(p)
+}
+
+// appendUvarint, appendVarint y appendUint64LE: equivalentes de los
+// binary.Append* de Go 1.19, que go.mod (1.16) no permite usar.
+func appendUvarint(dst []byte, v uint64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	return append(dst, b[:binary.PutUvarint(b[:], v)]...)
+}
+
+func appendVarint(dst []byte, v int64) []byte {
+	var b [binary.MaxVarintLen64]byte
+	return append(dst, b[:binary.PutVarint(b[:], v)]...)
+}
+
+func appendUint64LE(dst []byte, v uint64) []byte {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	return append(dst, b[:]...)
+}
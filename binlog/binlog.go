@@ -0,0 +1,306 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Package binlog reads log files written with acacia.Format.Binary and
+// converts them back to text or JSON lines.
+package binlog
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+// maxRecord bounds the length prefix, so a corrupt file cannot make the
+// reader allocate gigabytes.
+const maxRecord = 64 << 20
+
+// ErrCorrupt is returned (wrapped) when a record cannot be decoded.
+var ErrCorrupt = errors.New("binlog: corrupt record")
+
+var levels = [...]string{acacia.Level.DEBUG, acacia.Level.INFO, acacia.Level.WARN, acacia.Level.ERROR, acacia.Level.CRITICAL}
+
+// Reader decodes the records of a binary log, in order.
+type Reader struct {
+	r    *bufio.Reader
+	prev int64 // timestamp absoluto del registro anterior
+	buf  []byte
+}
+
+// NewReader returns a Reader decoding from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: bufio.NewReader(r)}
+}
+
+// Next returns the next entry. It returns io.EOF after the last record,
+// io.ErrUnexpectedEOF if the last record is truncated (e.g. the process died
+// mid-write) and ErrCorrupt for records that cannot be decoded.
+func (r *Reader) Next() (acacia.Entry, error) {
+	n, err := binary.ReadUvarint(r.r)
+	if err == io.EOF {
+		return acacia.Entry{}, io.EOF
+	}
+	if err != nil {
+		return acacia.Entry{}, io.ErrUnexpectedEOF
+	}
+	if n == 0 || n > maxRecord {
+		return acacia.Entry{}, fmt.Errorf("%w: length %d", ErrCorrupt, n)
+	}
+	if uint64(cap(r.buf)) < n {
+		r.buf = make([]byte, n)
+	}
+	r.buf = r.buf[:n]
+	if _, err := io.ReadFull(r.r, r.buf); err != nil {
+		return acacia.Entry{}, io.ErrUnexpectedEOF
+	}
+	return r.decode(r.buf)
+}
+
+func (r *Reader) decode(rec []byte) (acacia.Entry, error) {
+	d := decoder{b: rec}
+	flags := d.byte()
+	ts := d.varint()
+	if d.err != nil || int(flags&0x07) >= len(levels) {
+		return acacia.Entry{}, fmt.Errorf("%w: header", ErrCorrupt)
+	}
+	if flags&acacia.BinaryAbsoluteTS != 0 {
+		r.prev = ts
+	} else {
+		r.prev += ts
+	}
+	e := acacia.Entry{
+		Time:    time.Unix(0, r.prev),
+		Level:   levels[flags&0x07],
+		Message: d.string(),
+		ID:      d.string(),
+	}
+	nf := d.uvarint()
+	if d.err != nil || nf > uint64(len(rec)) {
+		return acacia.Entry{}, fmt.Errorf("%w: fields", ErrCorrupt)
+	}
+	if nf > 0 {
+		e.Fields = make([]acacia.Field, 0, nf)
+	}
+	for i := uint64(0); i < nf && d.err == nil; i++ {
+		key := d.string()
+		var f acacia.Field
+		switch typ := d.byte(); typ {
+		case acacia.BinaryString:
+			f = acacia.String(key, d.string())
+		case acacia.BinaryInt:
+			f = acacia.Int64(key, d.varint())
+		case acacia.BinaryUint:
+			f = acacia.Uint64(key, d.uvarint())
+		case acacia.BinaryFloat:
+			f = acacia.Float64(key, math.Float64frombits(d.uint64()))
+		case acacia.BinaryBool:
+			f = acacia.Bool(key, d.byte() == 1)
+		case acacia.BinaryDuration:
+			f = acacia.Duration(key, time.Duration(d.varint()))
+		case acacia.BinaryTime:
+			f = acacia.Time(key, time.Unix(0, d.varint()))
+		case acacia.BinaryJSON:
+			var v interface{}
+			dec := json.NewDecoder(bytes.NewReader(d.bytes()))
+			dec.UseNumber()
+			if err := dec.Decode(&v); err != nil && d.err == nil {
+				d.err = err
+			}
+			f = acacia.Any(key, v)
+		default:
+			d.err = fmt.Errorf("field type %d", typ)
+		}
+		e.Fields = append(e.Fields, f)
+	}
+	if d.err != nil {
+		return acacia.Entry{}, fmt.Errorf("%w: %v", ErrCorrupt, d.err)
+	}
+	return e, nil
+}
+
+// decoder reads the primitives of a record, remembering the first error.
+type decoder struct {
+	b   []byte
+	err error
+}
+
+func (d *decoder) fail() {
+	if d.err == nil {
+		d.err = io.ErrUnexpectedEOF
+	}
+	d.b = nil
+}
+
+func (d *decoder) byte() byte {
+	if len(d.b) < 1 {
+		d.fail()
+		return 0
+	}
+	c := d.b[0]
+	d.b = d.b[1:]
+	return c
+}
+
+func (d *decoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.b)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	v, n := binary.Varint(d.b)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *decoder) uint64() uint64 {
+	if len(d.b) < 8 {
+		d.fail()
+		return 0
+	}
+	v := binary.LittleEndian.Uint64(d.b)
+	d.b = d.b[8:]
+	return v
+}
+
+func (d *decoder) bytes() []byte {
+	n := d.uvarint()
+	if n > uint64(len(d.b)) {
+		d.fail()
+		return nil
+	}
+	b := d.b[:n]
+	d.b = d.b[n:]
+	return b
+}
+
+func (d *decoder) string() string {
+	return string(d.bytes())
+}
+
+// textLayout renders entries like acacia's text format.
+var textLayout, _ = acacia.ParseLayout("{ts} [{level}] {msg} {fields}")
+
+// Convert decodes every record of src and writes it to dst as a line in
+// acacia.Format.Text or acacia.Format.JSON. Text lines use TS.Special
+// timestamps and show the record ID as an "id" field.
+func Convert(dst io.Writer, src io.Reader, format string) error {
+	var f acacia.Formatter
+	switch format {
+	case acacia.Format.Text:
+		f = acacia.FormatterFunc(func(dst []byte, e acacia.Entry) []byte {
+			if e.ID != "" {
+				e.Fields = append(e.Fields, acacia.String("id", e.ID))
+			}
+			return textLayout.AppendEntry(dst, e)
+		})
+	case acacia.Format.JSON:
+		f = acacia.FormatterFunc(appendJSON)
+	default:
+		return fmt.Errorf("binlog: unsupported format %q", format)
+	}
+
+	w := bufio.NewWriter(dst)
+	rd := NewReader(src)
+	var line []byte
+	for {
+		e, err := rd.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			_ = w.Flush()
+			return err
+		}
+		line = f.AppendEntry(line[:0], e)
+		if len(line) == 0 || line[len(line)-1] != '\n' {
+			line = append(line, '\n')
+		}
+		if _, err := w.Write(line); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// appendJSON renders e like acacia's JSON format: ts, level, msg, the
+// fields in order, then id.
+func appendJSON(dst []byte, e acacia.Entry) []byte {
+	dst = append(dst, `{"ts":`...)
+	dst = strconv.AppendQuote(dst, e.Time.Format(time.RFC3339Nano))
+	dst = append(dst, `,"level":`...)
+	dst = appendJSONValue(dst, e.Level)
+	dst = append(dst, `,"msg":`...)
+	dst = appendJSONValue(dst, e.Message)
+	for _, f := range e.Fields {
+		dst = append(dst, ',')
+		dst = appendJSONValue(dst, f.Key)
+		dst = append(dst, ':')
+		v := f.Value()
+		if d, ok := v.(time.Duration); ok {
+			v = d.String()
+		}
+		dst = appendJSONValue(dst, v)
+	}
+	if e.ID != "" {
+		dst = append(dst, `,"id":`...)
+		dst = appendJSONValue(dst, e.ID)
+	}
+	return append(dst, '}', '\n')
+}
+
+func appendJSONValue(dst []byte, v interface{}) []byte {
+	b, err := json.Marshal(v)
+	if err != nil {
+		b, _ = json.Marshal(fmt.Sprint(v))
+	}
+	return append(dst, b...)
+}
@@ -0,0 +1,112 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"bytes"
+	"time"
+)
+
+// breaker is a circuit breaker for a secondary output: after threshold
+// consecutive failures it opens and the output is skipped for cooldown, then
+// one write is let through to probe it. Outages are reported through
+// errs.report, on stderr and Errors, when they start and when they end. Not
+// safe for concurrent use: each breaker belongs to the goroutine that writes
+// its output.
+type breaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	tripped   bool
+	dropped   int // líneas descartadas durante el corte
+	errs      *errSink
+}
+
+// WithCircuitBreaker protects the main log from failing secondary outputs
+// (level files, routed files and the mirror file): after failures
+// consecutive errors, an output is left alone for cooldown and its lines are
+// dropped, instead of being retried on every flush. The first write after
+// the cool-down probes it again. The outage is reported on stderr when the
+// breaker opens and, with the number of lines lost, when the output
+// recovers.
+//
+// Without this option level and routed files are retried on every flush and
+// the mirror file pauses for five seconds after each error.
+func WithCircuitBreaker(failures int, cooldown time.Duration) Option {
+	return func(conf *config) {
+		if failures > 0 && cooldown > 0 {
+			conf.breakerFailures = failures
+			conf.breakerCooldown = cooldown
+		}
+	}
+}
+
+func newBreaker(name string, threshold int, cooldown time.Duration, errs *errSink) *breaker {
+	return &breaker{name: name, threshold: threshold, cooldown: cooldown, errs: errs}
+}
+
+// allow reports whether the output may be written now.
+func (b *breaker) allow() bool {
+	return b.openUntil.IsZero() || !time.Now().Before(b.openUntil)
+}
+
+// skip counts the lines of p, dropped while the breaker is open.
+func (b *breaker) skip(p []byte) {
+	b.dropped += bytes.Count(p, []byte{'\n'})
+}
+
+// done records the outcome of a write of p that allow let through.
+func (b *breaker) done(err error, p []byte) {
+	if err == nil {
+		if b.tripped {
+			b.errs.report("%s recovered, %d lines dropped", b.name, b.dropped)
+		}
+		b.failures, b.tripped, b.dropped = 0, false, 0
+		b.openUntil = time.Time{}
+		return
+	}
+	b.skip(p)
+	b.failures++
+	if b.failures < b.threshold {
+		return
+	}
+	b.openUntil = time.Now().Add(b.cooldown)
+	if !b.tripped {
+		b.tripped = true
+		b.errs.report("%s: %d consecutive failures, pausing for %v: %v", b.name, b.failures, b.cooldown, err)
+	}
+}
@@ -0,0 +1,149 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"strings"
+)
+
+var (
+	// ErrCaptureUnsupported is returned by CaptureOutput on platforms where
+	// file descriptors cannot be redirected.
+	ErrCaptureUnsupported = errors.New("acacia: output capture is not supported on this platform")
+	// ErrCaptureLoop is returned by CaptureOutput when the logger itself
+	// writes to stdout or stderr.
+	ErrCaptureLoop = errors.New("acacia: cannot capture the output the logger writes to")
+)
+
+// capturedFD is one redirected descriptor.
+type capturedFD struct {
+	fd    int
+	saved int           // copia del descriptor original, para restaurarlo
+	done  chan struct{} // se cierra cuando el lector llega a EOF
+}
+
+// CaptureOutput redirects the process's stdout and stderr file descriptors
+// (1 and 2) through pipes into the logger, so prints from C libraries and
+// third-party code end up in the log. Each line becomes an INFO entry with a
+// "source" field set to "stdout" or "stderr". Call restore to put the
+// original descriptors back; it waits until every captured line is logged.
+// Acacia's own internal errors, which go to stderr, are captured as well.
+func (_log *Log) CaptureOutput() (restore func() error, err error) {
+	f := _log.getFile()
+	if out, ok := _log.sink.(*os.File); ok {
+		f = out
+	}
+	if f != nil && (f.Fd() == 1 || f.Fd() == 2) {
+		return nil, ErrCaptureLoop
+	}
+	var caps []*capturedFD
+	restoreAll := func() error {
+		var first error
+		for _, c := range caps {
+			if err := c.restore(); err != nil && first == nil {
+				first = err
+			}
+		}
+		return first
+	}
+	for _, src := range []struct {
+		fd   int
+		name string
+	}{{1, "stdout"}, {2, "stderr"}} {
+		c, err := _log.captureFD(src.fd, src.name)
+		if err != nil {
+			_ = restoreAll()
+			return nil, err
+		}
+		caps = append(caps, c)
+	}
+	return restoreAll, nil
+}
+
+// captureFD points fd to the write end of a new pipe whose lines are logged
+// with source name.
+func (_log *Log) captureFD(fd int, name string) (*capturedFD, error) {
+	saved, err := dupFD(fd)
+	if err != nil {
+		return nil, err
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		_ = closeFD(saved)
+		return nil, err
+	}
+	if err := redirectFD(int(w.Fd()), fd); err != nil {
+		_ = r.Close()
+		_ = w.Close()
+		_ = closeFD(saved)
+		return nil, err
+	}
+	// fd es ahora el único extremo de escritura: al restaurarlo el lector ve EOF
+	_ = w.Close()
+
+	c := &capturedFD{fd: fd, saved: saved, done: make(chan struct{})}
+	go func() {
+		defer close(c.done)
+		defer r.Close()
+		br := bufio.NewReader(r)
+		for {
+			line, err := br.ReadString('\n')
+			if line = strings.TrimRight(line, "\r\n"); line != "" {
+				_log.InfoFields(line, String("source", name))
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return c, nil
+}
+
+func (c *capturedFD) restore() error {
+	err := redirectFD(c.saved, c.fd)
+	_ = closeFD(c.saved)
+	if err == nil {
+		<-c.done
+	}
+	return err
+}
+
+// closeFD closes a raw descriptor obtained from dupFD.
+func closeFD(fd int) error {
+	return os.NewFile(uintptr(fd), "").Close()
+}
@@ -0,0 +1,231 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CEFConfig describes the device fields of ArcSight Common Event Format
+// headers. Extensions maps entry keys to CEF extension keys (for example
+// "ip" → "src", "user" → "suser"); unmapped keys are written as they are.
+type CEFConfig struct {
+	Vendor     string
+	Product    string
+	Version    string
+	Extensions map[string]string
+}
+
+// WithCEF configures CEF output and makes Format.CEF the initial format:
+//
+//	CEF:0|Vendor|Product|Version|<event or level>|<msg>|<severity>|rt=… key=value …
+//
+// The event class ID is the "event" field when present, otherwise the level.
+// Severity maps DEBUG..CRITICAL to 1, 3, 5, 7 and 10. Empty device fields
+// default to humanjuan, acacia and the library version.
+func WithCEF(c CEFConfig) Option {
+	return func(conf *config) {
+		if c.Vendor == "" {
+			c.Vendor = "humanjuan"
+		}
+		if c.Product == "" {
+			c.Product = "acacia"
+		}
+		if c.Version == "" {
+			c.Version = version
+		}
+		conf.cef = &c
+	}
+}
+
+// defaultCEF is the configuration used when Format.CEF is selected without
+// WithCEF.
+func defaultCEF() *CEFConfig {
+	conf := &config{}
+	WithCEF(CEFConfig{})(conf)
+	return conf.cef
+}
+
+var cefSeverity = [...]string{"1", "3", "5", "7", "10"}
+
+// formatCEF renders a structured entry as a CEF line. Extension keys are
+// sorted so output is stable.
+func (_log *Log) formatCEF(level string, fields map[string]interface{}) []byte {
+	msg, _ := fieldValue(fields["msg"]).(string)
+	event, _ := fieldValue(fields["event"]).(string)
+	buf := _log.appendCEFHeader(getBuf(), level, event, msg)
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		if k != "msg" && k != "event" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		buf = _log.appendCEFKey(buf, k)
+		switch v := fieldValue(fields[k]).(type) {
+		case string:
+			buf = appendCEFValue(buf, v)
+		default:
+			if out, ok := appendInt(buf, v); ok {
+				buf = out
+			} else if out, ok := appendValue(buf, v); ok {
+				buf = out
+			} else if encoded, err := _log.encoder.Marshal(v); err == nil {
+				buf = appendCEFValue(buf, string(encoded))
+			}
+		}
+	}
+	return append(buf, '\n')
+}
+
+// appendCEFEntry is the typed-fields counterpart of formatCEF.
+func (_log *Log) appendCEFEntry(dst []byte, level, msg, id string, fields []Field) []byte {
+	event := ""
+	for i := range fields {
+		if fields[i].Key == "event" && fields[i].kind == fieldString {
+			event = fields[i].str
+		}
+	}
+	dst = _log.appendCEFHeader(dst, level, event, msg)
+	for i := range fields {
+		f := &fields[i]
+		if f.Key == "event" && f.kind == fieldString {
+			continue
+		}
+		dst = _log.appendCEFKey(dst, f.Key)
+		switch f.kind {
+		case fieldString:
+			dst = appendCEFValue(dst, f.str)
+		case fieldError:
+			if f.iface != nil {
+				dst = appendCEFValue(dst, callString(f.iface, f.iface.(error).Error))
+			}
+		case fieldAny:
+			if s, ok := fieldValue(f.iface).(string); ok {
+				dst = appendCEFValue(dst, s)
+			} else if encoded, err := _log.encoder.Marshal(fieldValue(f.iface)); err == nil {
+				dst = appendCEFValue(dst, string(encoded))
+			}
+		default:
+			// números, bools, duraciones y fechas no llevan caracteres a escapar
+			dst = appendFieldText(dst, f)
+		}
+	}
+	if id != "" {
+		dst = _log.appendCEFKey(dst, "id")
+		dst = appendCEFValue(dst, id)
+	}
+	return append(dst, '\n')
+}
+
+func (_log *Log) appendCEFHeader(dst []byte, level, event, msg string) []byte {
+	c := _log.cef
+	if event == "" {
+		event = level
+	}
+	dst = append(dst, "CEF:0|"...)
+	for _, part := range [...]string{c.Vendor, c.Product, c.Version, event, msg} {
+		dst = appendCEFHeaderValue(dst, part)
+		dst = append(dst, '|')
+	}
+	if rank := levelRank(level); rank >= 0 {
+		dst = append(dst, cefSeverity[rank]...)
+	} else {
+		dst = append(dst, "Unknown"...)
+	}
+	dst = append(dst, "|rt="...)
+	return strconv.AppendInt(dst, _log.clock().UnixNano()/int64(time.Millisecond), 10)
+}
+
+func (_log *Log) appendCEFKey(dst []byte, key string) []byte {
+	if mapped, ok := _log.cef.Extensions[key]; ok {
+		key = mapped
+	}
+	dst = append(dst, ' ')
+	// las claves de extensión solo admiten alfanuméricos
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '_' {
+			dst = append(dst, c)
+		} else {
+			dst = append(dst, '_')
+		}
+	}
+	return append(dst, '=')
+}
+
+// appendCEFHeaderValue escapes '\' and '|' and flattens newlines, as the CEF
+// spec requires for header fields.
+func appendCEFHeaderValue(dst []byte, s string) []byte {
+	if !strings.ContainsAny(s, "\\|\r\n") {
+		return append(dst, s...)
+	}
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\', '|':
+			dst = append(dst, '\\', c)
+		case '\r', '\n':
+			dst = append(dst, ' ')
+		default:
+			dst = append(dst, c)
+		}
+	}
+	return dst
+}
+
+// appendCEFValue escapes '\', '=' and newlines in extension values.
+func appendCEFValue(dst []byte, s string) []byte {
+	if !strings.ContainsAny(s, "\\=\r\n") {
+		return append(dst, s...)
+	}
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\', '=':
+			dst = append(dst, '\\', c)
+		case '\n':
+			dst = append(dst, '\\', 'n')
+		case '\r':
+			dst = append(dst, '\\', 'r')
+		default:
+			dst = append(dst, c)
+		}
+	}
+	return dst
+}
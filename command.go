@@ -0,0 +1,123 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// maxCommandLine bounds the partial line a command writer keeps; longer
+// lines are logged in pieces of this size.
+const maxCommandLine = 64 * 1024
+
+// commandWriter logs each complete line written to it.
+type commandWriter struct {
+	lg     *Log
+	level  string
+	prefix string
+	source string
+	mtx    sync.Mutex
+	buf    []byte // línea parcial pendiente del siguiente Write
+}
+
+// CommandWriters returns writers for the Stdout and Stderr of an exec.Cmd
+// that log each line of the child's output as "prefix: line" with a
+// "source" field set to "stdout" or "stderr". Stdout lines are INFO entries
+// and stderr lines ERROR entries:
+//
+//	cmd := exec.Command("pg_dump", "billing")
+//	stdout, stderr := log.CommandWriters("pg_dump")
+//	cmd.Stdout, cmd.Stderr = stdout, stderr
+//	err := cmd.Run()
+//	stdout.Close()
+//	stderr.Close()
+//
+// Output is split on '\n' and empty lines are skipped. Close logs a last
+// line that lacks its newline; call it once the command has finished.
+func (_log *Log) CommandWriters(prefix string) (stdout, stderr io.WriteCloser) {
+	return &commandWriter{lg: _log, level: Level.INFO, prefix: prefix, source: "stdout"},
+		&commandWriter{lg: _log, level: Level.ERROR, prefix: prefix, source: "stderr"}
+}
+
+func (w *commandWriter) Write(p []byte) (int, error) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	n := len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			w.buf = append(w.buf, p...)
+			for len(w.buf) >= maxCommandLine {
+				w.logLine(w.buf[:maxCommandLine])
+				w.buf = w.buf[maxCommandLine:]
+			}
+			break
+		}
+		if len(w.buf) > 0 {
+			w.buf = append(w.buf, p[:i]...)
+			w.logLine(w.buf)
+			w.buf = w.buf[:0]
+		} else {
+			w.logLine(p[:i])
+		}
+		p = p[i+1:]
+	}
+	return n, nil
+}
+
+// Close logs the pending partial line, if any.
+func (w *commandWriter) Close() error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if len(w.buf) > 0 {
+		w.logLine(w.buf)
+		w.buf = nil
+	}
+	return nil
+}
+
+func (w *commandWriter) logLine(line []byte) {
+	line = bytes.TrimRight(line, "\r")
+	if len(line) == 0 {
+		return
+	}
+	msg := string(line)
+	if w.prefix != "" {
+		msg = w.prefix + ": " + msg
+	}
+	w.lg.logFields(w.level, msg, []Field{String("source", w.source)})
+}
@@ -0,0 +1,295 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ErrConfigFormat is returned by StartFromConfig for files that are not JSON.
+// YAML and TOML would need third-party parsers, and Acacia has no
+// dependencies.
+var ErrConfigFormat = errors.New("acacia: unsupported config format, only JSON is supported")
+
+// Config is the declarative form of a set of loggers, as read by
+// StartFromConfig.
+type Config struct {
+	Loggers []LoggerConfig `json:"loggers"`
+}
+
+// LoggerConfig describes one logger. File, Path and Level are the arguments
+// of Start; the other fields map to the option or method of the same name.
+// TimestampFormat is a Go layout or the name of one of TS ("RFC3339").
+// Name is the registry name (see Get) and defaults to File; Default also
+// makes it the package-level logger (see SetDefault).
+type LoggerConfig struct {
+	Name            string            `json:"name"`
+	File            string            `json:"file"`
+	Path            string            `json:"path"`
+	Level           string            `json:"level"`
+	Format          string            `json:"format"`
+	TimestampFormat string            `json:"timestamp_format"`
+	UTC             bool              `json:"utc"`
+	Default         bool              `json:"default"`
+	Rotation        RotationConfig    `json:"rotation"`
+	BufferSize      int               `json:"buffer_size"`
+	BatchSize       int               `json:"batch_size"`
+	FlushInterval   ConfigDuration    `json:"flush_interval"`
+	Sampling        *SamplingConfig   `json:"sampling"`
+	LevelFiles      []LevelFileConfig `json:"level_files"`
+	Mirror          string            `json:"mirror"`
+}
+
+// RotationConfig holds the arguments of Rotation and DailyRotation.
+type RotationConfig struct {
+	SizeMB  int  `json:"size_mb"`
+	Backups int  `json:"backups"`
+	Daily   bool `json:"daily"`
+}
+
+// SamplingConfig holds the arguments of WithSampling.
+type SamplingConfig struct {
+	Window     ConfigDuration `json:"window"`
+	First      int            `json:"first"`
+	Thereafter int            `json:"thereafter"`
+}
+
+// LevelFileConfig holds the arguments of WithLevelFile.
+type LevelFileConfig struct {
+	File    string `json:"file"`
+	Level   string `json:"level"`
+	SizeMB  int    `json:"size_mb"`
+	Backups int    `json:"backups"`
+}
+
+// ConfigDuration is a time.Duration written in JSON as a string such as
+// "250ms" or as a number of nanoseconds.
+type ConfigDuration time.Duration
+
+func (d *ConfigDuration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		var n int64
+		if err := json.Unmarshal(b, &n); err != nil {
+			return fmt.Errorf("acacia: invalid duration %s", b)
+		}
+		*d = ConfigDuration(n)
+		return nil
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("acacia: invalid duration %q", s)
+	}
+	*d = ConfigDuration(v)
+	return nil
+}
+
+func (d ConfigDuration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// StartFromConfig starts every logger described in the JSON file at path,
+// registers each one under its name and returns them by name, so deployments
+// change logging behavior without recompiling:
+//
+//	{
+//	  "loggers": [
+//	    {"name": "app", "file": "app.log", "path": "./logs", "level": "info",
+//	     "format": "json", "default": true,
+//	     "rotation": {"size_mb": 10, "backups": 5, "daily": true},
+//	     "level_files": [{"file": "app.error.log", "level": "error", "size_mb": 10, "backups": 5}],
+//	     "sampling": {"window": "1s", "first": 100, "thereafter": 10}}
+//	  ]
+//	}
+//
+// Unknown keys, levels and formats are errors. If any logger cannot be
+// started, the ones already started are closed and nothing is registered.
+func StartFromConfig(path string) (map[string]*Log, error) {
+	conf, err := readConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	logs, def, err := conf.start()
+	if err != nil {
+		return nil, err
+	}
+	for name, lg := range logs {
+		Register(name, lg)
+	}
+	if def != nil {
+		SetDefault(def)
+	}
+	return logs, nil
+}
+
+// start starts every logger of conf with the extra opts and returns them by
+// name, with the one marked Default. On error the ones already started are
+// closed.
+func (conf *Config) start(opts ...Option) (map[string]*Log, *Log, error) {
+	logs := make(map[string]*Log, len(conf.Loggers))
+	closeAll := func() {
+		for _, lg := range logs {
+			lg.Close()
+		}
+	}
+	var def *Log
+	for i := range conf.Loggers {
+		lc := &conf.Loggers[i]
+		if _, dup := logs[lc.Name]; dup {
+			closeAll()
+			return nil, nil, fmt.Errorf("acacia: duplicate logger name %q", lc.Name)
+		}
+		lg, err := lc.start(opts...)
+		if err != nil {
+			closeAll()
+			return nil, nil, fmt.Errorf("acacia: logger %q: %w", lc.Name, err)
+		}
+		logs[lc.Name] = lg
+		if lc.Default {
+			def = lg
+		}
+	}
+	return logs, def, nil
+}
+
+// readConfig reads and decodes the JSON file at path, filling in default
+// logger names.
+func readConfig(path string) (*Config, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json", "":
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrConfigFormat, path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var conf Config
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&conf); err != nil {
+		return nil, fmt.Errorf("acacia: parsing %s: %w", path, err)
+	}
+	if len(conf.Loggers) == 0 {
+		return nil, fmt.Errorf("acacia: %s defines no loggers", path)
+	}
+	for i := range conf.Loggers {
+		if conf.Loggers[i].Name == "" {
+			conf.Loggers[i].Name = conf.Loggers[i].File
+		}
+	}
+	return &conf, nil
+}
+
+// start validates lc and starts its logger, with extra after the options
+// from lc.
+func (lc *LoggerConfig) start(extra ...Option) (*Log, error) {
+	level := strings.ToUpper(lc.Level)
+	if level == "" {
+		level = Level.INFO
+	}
+	if !verifyLevel(level) {
+		return nil, fmt.Errorf("unknown level %q", lc.Level)
+	}
+	format := strings.ToUpper(lc.Format)
+	switch format {
+	case "", Format.Text, Format.JSON, Format.Logfmt, Format.CEF, Format.Syslog, Format.Pretty, Format.Binary:
+	default:
+		return nil, fmt.Errorf("unknown format %q", lc.Format)
+	}
+
+	var opts []Option
+	if lc.BufferSize > 0 {
+		opts = append(opts, WithBufferSize(lc.BufferSize))
+	}
+	if lc.BatchSize > 0 {
+		opts = append(opts, WithBatchSize(lc.BatchSize))
+	}
+	if lc.FlushInterval > 0 {
+		opts = append(opts, WithFlushInterval(time.Duration(lc.FlushInterval)))
+	}
+	if s := lc.Sampling; s != nil {
+		opts = append(opts, WithSampling(time.Duration(s.Window), s.First, s.Thereafter))
+	}
+	for _, lf := range lc.LevelFiles {
+		if levelRank(strings.ToUpper(lf.Level)) < 0 {
+			return nil, fmt.Errorf("unknown level %q for %s", lf.Level, lf.File)
+		}
+		opts = append(opts, WithLevelFile(lf.File, strings.ToUpper(lf.Level), lf.SizeMB, lf.Backups))
+	}
+	if lc.Mirror != "" {
+		opts = append(opts, WithMirrorFile(lc.Mirror))
+	}
+
+	opts = append(opts, extra...)
+
+	lg, err := Start(lc.File, lc.Path, level, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if format != "" {
+		lg.OutputFormat(format)
+	}
+	if lc.TimestampFormat != "" {
+		lg.TimestampFormat(timestampLayout(lc.TimestampFormat))
+	}
+	if lc.UTC {
+		lg.UseUTC(true)
+	}
+	if lc.Rotation.SizeMB > 0 {
+		lg.Rotation(lc.Rotation.SizeMB, lc.Rotation.Backups)
+	}
+	if lc.Rotation.Daily {
+		lg.DailyRotation(true)
+	}
+	return lg, nil
+}
+
+// timestampLayout resolves the name of a TS layout, or returns s unchanged.
+func timestampLayout(s string) string {
+	v := reflect.ValueOf(TS)
+	if f := v.FieldByName(s); f.IsValid() && f.Kind() == reflect.String {
+		return f.String()
+	}
+	return s
+}
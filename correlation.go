@@ -0,0 +1,109 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import "context"
+
+// RequestIDKey is the field name under which the *Ctx methods write the
+// request ID carried by the context.
+const RequestIDKey = "request_id"
+
+// requestIDKey es la clave privada del ID en el contexto.
+type requestIDKey struct{}
+
+// NewRequestID returns a new ULID to correlate the entries of one request
+// or job. It is the same as NewULID.
+func NewRequestID() string {
+	return NewULID()
+}
+
+// ContextWithRequestID returns a copy of ctx carrying id. Pass the context
+// down the call chain and log with the *Ctx methods to tag every entry of
+// the request with it.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, or "" when there is none,
+// e.g. to forward it in an outgoing header.
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// DebugCtx logs msg with typed fields at DEBUG level, plus the request ID
+// of ctx, if any, as RequestIDKey.
+func (_log *Log) DebugCtx(ctx context.Context, msg string, fields ...Field) {
+	_log.logFields(Level.DEBUG, msg, withRequestID(ctx, fields))
+}
+
+// InfoCtx logs msg with typed fields at INFO level, plus the request ID of
+// ctx, if any, as RequestIDKey.
+func (_log *Log) InfoCtx(ctx context.Context, msg string, fields ...Field) {
+	_log.logFields(Level.INFO, msg, withRequestID(ctx, fields))
+}
+
+// WarnCtx logs msg with typed fields at WARN level, plus the request ID of
+// ctx, if any, as RequestIDKey.
+func (_log *Log) WarnCtx(ctx context.Context, msg string, fields ...Field) {
+	_log.logFields(Level.WARN, msg, withRequestID(ctx, fields))
+}
+
+// ErrorCtx logs msg with typed fields at ERROR level, plus the request ID
+// of ctx, if any, as RequestIDKey.
+func (_log *Log) ErrorCtx(ctx context.Context, msg string, fields ...Field) {
+	_log.logFields(Level.ERROR, msg, withRequestID(ctx, fields))
+}
+
+// CriticalCtx logs msg with typed fields at CRITICAL level, plus the
+// request ID of ctx, if any, as RequestIDKey.
+func (_log *Log) CriticalCtx(ctx context.Context, msg string, fields ...Field) {
+	_log.logFields(Level.CRITICAL, msg, withRequestID(ctx, fields))
+}
+
+// withRequestID antepone el ID del contexto a los campos sin modificar el
+// slice del llamador.
+func withRequestID(ctx context.Context, fields []Field) []Field {
+	id := RequestID(ctx)
+	if id == "" {
+		return fields
+	}
+	out := make([]Field, 0, len(fields)+1)
+	out = append(out, String(RequestIDKey, id))
+	return append(out, fields...)
+}
@@ -0,0 +1,234 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"bytes"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// dedup collapses consecutive identical records, like syslogd. Writer
+// goroutine only, with mtx held.
+type dedup struct {
+	maxDelay time.Duration
+	key      uint32 // hash de last, solo como filtro rápido
+	last     []byte // identidad del último registro escrito
+	scratch  []byte
+	level    uint8
+	repeats  int
+	since    time.Time // primera repetición suprimida
+}
+
+// WithDuplicateSuppression writes a record repeated back to back only once,
+// followed by "last message repeated N times" when a different record
+// arrives, on Sync/Close, or after maxDelay at most. Records are identical
+// when everything but their timestamp matches. Text lines get the summary as
+// a plain message; structured formats also carry a "repeated" field.
+func WithDuplicateSuppression(maxDelay time.Duration) Option {
+	return func(conf *config) {
+		if maxDelay > 0 {
+			conf.dedup = &dedup{maxDelay: maxDelay}
+		}
+	}
+}
+
+// suppressRepeat drops ev if it repeats the previous record, and otherwise
+// writes the pending summary, if any, before ev.
+func (_log *Log) suppressRepeat(ts []byte, ev *logEvent) bool {
+	d := _log.dedup
+	ident := d.identity(_log, ev)
+	var key uint32
+	if ident != nil {
+		key = nonZero(hashBytes(ident))
+	}
+	if key != 0 && key == d.key && bytes.Equal(ident, d.last) {
+		if d.repeats == 0 {
+			d.since = time.Now()
+		}
+		d.repeats++
+		if ev.kind == eventRaw || ev.kind == eventBinary {
+			putBuf(ev.msgBytes)
+		}
+		return true
+	}
+	_log.appendRepeats(ts)
+	d.key, d.level = key, ev.level
+	d.last = append(d.last[:0], ident...)
+	return false
+}
+
+// flushRepeats writes the pending summary once it is older than maxDelay, or
+// right away with force. The next copy of the record is then written in full.
+func (_log *Log) flushRepeats(force bool) {
+	_log.mtx.Lock()
+	defer _log.mtx.Unlock()
+	d := _log.dedup
+	if d.repeats == 0 || (!force && time.Since(d.since) < d.maxDelay) {
+		return
+	}
+	var ts []byte
+	if cachedTS := _log.cachedTime.Load(); cachedTS != nil {
+		ts = cachedTS.([]byte)
+	}
+	_log.appendRepeats(ts)
+	d.key, d.last = 0, d.last[:0]
+}
+
+func (_log *Log) appendRepeats(ts []byte) {
+	d := _log.dedup
+	if d.repeats == 0 {
+		return
+	}
+	start := len(_log.buffer)
+	msg := "last message repeated " + strconv.Itoa(d.repeats) + " times"
+	if _log.lineFormat() == Format.Binary {
+		raw := _log.encodeFields(string(levelBytesFor(d.level)), msg, "", []Field{Int("repeated", d.repeats)}, "")
+		_log.buffer = _log.appendBinaryRecord(_log.buffer, _log.now().UnixNano(), raw)
+		putBuf(raw)
+		if _log.levelFiles != nil {
+			_log.copyToLevelFiles(start, d.level, true)
+		}
+		d.repeats = 0
+		return
+	}
+	if _log.structured() {
+		raw := _log.encodeFields(string(levelBytesFor(d.level)), msg, "", []Field{Int("repeated", d.repeats)}, "")
+		_log.buffer = append(_log.buffer, raw...)
+		putBuf(raw)
+	} else {
+		ev := logEvent{msgStr: msg, level: d.level, kind: eventString}
+		if _log.preciseTS {
+			ev.ts = _log.clock().UnixNano()
+		}
+		_log.buffer = appendEvent(_log.buffer, ts, _log.timestampLayout(), atomic.LoadInt32(&_log.utc) == 1, _log.levelTags, &ev)
+	}
+	_log.sealLine(start)
+	if _log.levelFiles != nil {
+		_log.copyToLevelFiles(start, d.level, false)
+	}
+	d.repeats = 0
+}
+
+// identity returns the bytes that identify the record in ev without its
+// timestamp, or nil if it cannot be compared. The result is only valid until
+// the next call.
+func (d *dedup) identity(_log *Log, ev *logEvent) []byte {
+	switch ev.kind {
+	case eventString:
+		d.scratch = append(append(d.scratch[:0], ev.kind, ev.level), ev.msgStr...)
+	case eventBytes:
+		d.scratch = append(append(d.scratch[:0], ev.kind, ev.level), ev.msgBytes...)
+	case eventMap:
+		// los valores ya están resueltos: se comparan por contenido
+		encoded, err := _log.encoder.Marshal(ev.fields)
+		if err != nil {
+			return nil
+		}
+		d.scratch = append(append(d.scratch[:0], ev.kind, ev.level), encoded...)
+	default:
+		if ev.dupLen == 0 {
+			return nil
+		}
+		n := len(ev.msgBytes)
+		return ev.msgBytes[n : n+int(ev.dupLen)]
+	}
+	return d.scratch
+}
+
+// withIdentity stores ident after line, in its spare capacity, where
+// identity finds it through ev.dupLen.
+func withIdentity(line, ident []byte) ([]byte, uint32) {
+	n := len(line)
+	line = append(line, ident...)
+	return line[:n], uint32(len(ident))
+}
+
+// lineIdentity stores a text line from its level tag on, leaving the
+// timestamp out.
+func lineIdentity(line []byte) ([]byte, uint32) {
+	i := bytes.Index(line, []byte(" ["))
+	if i < 0 {
+		i = 0
+	}
+	return withIdentity(line, line[i:])
+}
+
+// fieldsIdentity stores level, message and fields of a typed-fields entry
+// after its encoded line. Any values are compared by their JSON encoding, not
+// by address.
+func (_log *Log) fieldsIdentity(line []byte, level, msg string, fields []Field) ([]byte, uint32) {
+	n := len(line)
+	line = append(line, level...)
+	line = append(line, ' ')
+	line = append(line, msg...)
+	for i := range fields {
+		line = append(line, ' ')
+		line = append(line, fields[i].Key...)
+		line = append(line, '=')
+		if fields[i].kind == fieldAny {
+			line = _log.appendEncoded(line, fieldValue(fields[i].iface))
+			continue
+		}
+		line = appendFieldText(line, &fields[i])
+	}
+	return line[:n], uint32(len(line) - n)
+}
+
+// mapIdentity stores level and fields of a map entry after its encoded line,
+// once Lazy, Stringer and pointer values are resolved.
+func (_log *Log) mapIdentity(line []byte, level string, fields map[string]interface{}) ([]byte, uint32) {
+	n := len(line)
+	line = append(line, level...)
+	line = append(line, ' ')
+	line = _log.appendEncoded(line, _log.resolveFields(fields, true))
+	return line[:n], uint32(len(line) - n)
+}
+
+func (_log *Log) appendEncoded(dst []byte, v interface{}) []byte {
+	encoded, err := _log.encoder.Marshal(v)
+	if err != nil {
+		return append(dst, "!Marshal error: "+err.Error()...)
+	}
+	return append(dst, encoded...)
+}
+
+func nonZero(h uint32) uint32 {
+	if h == 0 {
+		return 1
+	}
+	return h
+}
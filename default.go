@@ -0,0 +1,123 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import "sync/atomic"
+
+// defaultLog holds the *Log used by the package-level functions.
+var defaultLog atomic.Value
+
+// SetDefault makes lg the logger behind the package-level functions (Info,
+// WarnFields, ...), so libraries and small programs can log without threading
+// the *Log around. Passing nil turns them back into no-ops.
+func SetDefault(lg *Log) {
+	defaultLog.Store(lg)
+}
+
+// Default returns the logger set with SetDefault, or nil.
+func Default() *Log {
+	lg, _ := defaultLog.Load().(*Log)
+	return lg
+}
+
+// Info logs on the default logger; it does nothing without one.
+func Info(data interface{}, args ...interface{}) {
+	if lg := Default(); lg != nil {
+		lg.Info(data, args...)
+	}
+}
+
+// Warn logs on the default logger; it does nothing without one.
+func Warn(data interface{}, args ...interface{}) {
+	if lg := Default(); lg != nil {
+		lg.Warn(data, args...)
+	}
+}
+
+// Error logs on the default logger; it does nothing without one.
+func Error(data interface{}, args ...interface{}) {
+	if lg := Default(); lg != nil {
+		lg.Error(data, args...)
+	}
+}
+
+// Critical logs on the default logger; it does nothing without one.
+func Critical(data interface{}, args ...interface{}) {
+	if lg := Default(); lg != nil {
+		lg.Critical(data, args...)
+	}
+}
+
+// Debug logs on the default logger; it does nothing without one.
+func Debug(data interface{}, args ...interface{}) {
+	if lg := Default(); lg != nil {
+		lg.Debug(data, args...)
+	}
+}
+
+// InfoFields logs typed fields on the default logger; it does nothing without one.
+func InfoFields(msg string, fields ...Field) {
+	if lg := Default(); lg != nil {
+		lg.InfoFields(msg, fields...)
+	}
+}
+
+// WarnFields logs typed fields on the default logger; it does nothing without one.
+func WarnFields(msg string, fields ...Field) {
+	if lg := Default(); lg != nil {
+		lg.WarnFields(msg, fields...)
+	}
+}
+
+// ErrorFields logs typed fields on the default logger; it does nothing without one.
+func ErrorFields(msg string, fields ...Field) {
+	if lg := Default(); lg != nil {
+		lg.ErrorFields(msg, fields...)
+	}
+}
+
+// CriticalFields logs typed fields on the default logger; it does nothing without one.
+func CriticalFields(msg string, fields ...Field) {
+	if lg := Default(); lg != nil {
+		lg.CriticalFields(msg, fields...)
+	}
+}
+
+// DebugFields logs typed fields on the default logger; it does nothing without one.
+func DebugFields(msg string, fields ...Field) {
+	if lg := Default(); lg != nil {
+		lg.DebugFields(msg, fields...)
+	}
+}
@@ -0,0 +1,222 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// WithDiagnosticsFile sets the file where diagnostic snapshots are appended.
+// By default it is the log file path plus ".diag".
+func WithDiagnosticsFile(path string) Option {
+	return func(conf *config) {
+		if path != "" {
+			conf.diagPath = path
+		}
+	}
+}
+
+// writerState is what DumpState shows of the writer goroutine. The writer
+// publishes it, with mtx held, after every drain and every flush, so
+// DumpState reads it without waiting for the writer or racing with it.
+type writerState struct {
+	buffer, bufferCap     int64
+	writeBuf, writeBufCap int64
+	currentSize, maxSize  int64
+	maxRotation           int64
+	daily                 int32
+	lastDay               atomic.Value // string
+	day                   string       // último lastDay publicado, solo writer
+}
+
+// publishState copia al estado publicado lo que ve el writer. Con mtx.
+func (_log *Log) publishState() {
+	st := &_log.state
+	atomic.StoreInt64(&st.buffer, int64(len(_log.buffer)))
+	atomic.StoreInt64(&st.bufferCap, int64(cap(_log.buffer)))
+	atomic.StoreInt64(&st.writeBuf, int64(len(_log.writeBuf)))
+	atomic.StoreInt64(&st.writeBufCap, int64(cap(_log.writeBuf)))
+	atomic.StoreInt64(&st.currentSize, _log.currentSize)
+	atomic.StoreInt64(&st.maxSize, _log.maxSize)
+	atomic.StoreInt64(&st.maxRotation, int64(_log.maxRotation))
+	daily := int32(0)
+	if _log.daily {
+		daily = 1
+	}
+	atomic.StoreInt32(&st.daily, daily)
+	if _log.lastDay != st.day {
+		st.day = _log.lastDay
+		st.lastDay.Store(_log.lastDay)
+	}
+}
+
+// DumpState writes a human-readable snapshot of the logger internals to w:
+// queue occupancy, buffer sizes, rotation state, pool stats and the stacks of
+// the logger goroutines. Buffer and rotation figures are the ones the writer
+// goroutine published at its last drain or flush. DumpState takes no lock
+// the writer holds, so it can be used to troubleshoot a stalled logger.
+func (_log *Log) DumpState(w io.Writer) error {
+	var b bytes.Buffer
+	now := time.Now()
+
+	fmt.Fprintf(&b, "=== Acacia v%s diagnostics at %s ===\n", version, now.Format(time.RFC3339Nano))
+	fmt.Fprintf(&b, "logger: name=%s path=%s level=%s format=%s status=%t\n",
+		_log.name, _log.path, _log.LogLevel(), _log.lineFormat(), _log.status)
+
+	enq := _log.queue.enqueued()
+	deq := _log.queue.dequeued()
+	fmt.Fprintf(&b, "queue: %d/%d enqueued=%d dequeued=%d dropped=%d control=%d/%d\n",
+		_log.queue.len(), _log.queue.cap(), enq, deq,
+		atomic.LoadUint64(&_log.dropped), len(_log.control), cap(_log.control))
+
+	st := &_log.state
+	fmt.Fprintf(&b, "buffers: buffer=%d/%d writeBuf=%d/%d\n",
+		atomic.LoadInt64(&st.buffer), atomic.LoadInt64(&st.bufferCap),
+		atomic.LoadInt64(&st.writeBuf), atomic.LoadInt64(&st.writeBufCap))
+	lastDay, _ := st.lastDay.Load().(string)
+	fmt.Fprintf(&b, "rotation: currentSize=%d maxSize=%d maxRotation=%d daily=%t lastDay=%s\n",
+		atomic.LoadInt64(&st.currentSize), atomic.LoadInt64(&st.maxSize), atomic.LoadInt64(&st.maxRotation),
+		atomic.LoadInt32(&st.daily) == 1, lastDay)
+
+	if last := atomic.LoadInt64(&_log.lastFlush); last > 0 {
+		fmt.Fprintf(&b, "flush: every=%s last=%s ago\n", _log.flushEvery, now.Sub(time.Unix(0, last)))
+	} else {
+		fmt.Fprintf(&b, "flush: every=%s last=never\n", _log.flushEvery)
+	}
+
+	fmt.Fprintf(&b, "pools (new allocations): 512B=%d 2KB=%d 4KB=%d 8KB=%d\n",
+		atomic.LoadUint64(&poolNews[0]), atomic.LoadUint64(&poolNews[1]),
+		atomic.LoadUint64(&poolNews[2]), atomic.LoadUint64(&poolNews[3]))
+
+	b.WriteString("goroutines:\n")
+	b.Write(_log.goroutineStacks())
+	b.WriteString("\n")
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// WriteDiagnostics appends a DumpState snapshot to the diagnostics file.
+func (_log *Log) WriteDiagnostics() error {
+	f, err := os.OpenFile(_log.diagPath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if err := _log.DumpState(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// DiagnosticsOnSignal writes a diagnostics snapshot every time the process
+// receives one of the given signals (e.g. syscall.SIGUSR1) until Close.
+func (_log *Log) DiagnosticsOnSignal(sigs ...os.Signal) {
+	if len(sigs) == 0 {
+		return
+	}
+	ch := make(chan os.Signal, 1)
+
+	_log.diagMtx.Lock()
+	if _log.diagSignals != nil {
+		signal.Stop(_log.diagSignals)
+	}
+	_log.diagSignals = ch
+	_log.diagMtx.Unlock()
+
+	signal.Notify(ch, sigs...)
+	go func() {
+		for {
+			select {
+			case _, ok := <-ch:
+				if !ok {
+					return
+				}
+				if err := _log.WriteDiagnostics(); err != nil {
+					_log.reportError("writing diagnostics: %v", err)
+				}
+			case <-_log.done:
+				return
+			}
+		}
+	}()
+}
+
+func (_log *Log) stopDiagnosticsSignal() {
+	_log.diagMtx.Lock()
+	if _log.diagSignals != nil {
+		signal.Stop(_log.diagSignals)
+		_log.diagSignals = nil
+	}
+	_log.diagMtx.Unlock()
+}
+
+// goroutineStacks returns the stacks of the goroutines owned by this logger
+// (writer and timestamp updater).
+func (_log *Log) goroutineStacks() []byte {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+
+	self := []byte(fmt.Sprintf("(%p", _log))
+	var out bytes.Buffer
+	for _, g := range bytes.Split(buf, []byte("\n\n")) {
+		if !bytes.Contains(g, []byte(".(*Log).start")) {
+			continue
+		}
+		if !bytes.Contains(g, self) {
+			continue
+		}
+		out.Write(g)
+		out.WriteString("\n\n")
+	}
+	if out.Len() == 0 {
+		out.WriteString("(no logger goroutines found)\n")
+	}
+	return out.Bytes()
+}
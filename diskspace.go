@@ -0,0 +1,165 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"os"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultDiskCheckInterval is how often WithDiskSpaceMonitor checks free
+// space when no interval is given.
+const DefaultDiskCheckInterval = 30 * time.Second
+
+// diskMonitor holds the settings of WithDiskSpaceMonitor. With WithWriters
+// every file runs its own check and shares this state, so the low-space
+// condition is reported once.
+type diskMonitor struct {
+	minFree int64
+	every   time.Duration
+	purge   bool
+	low     int32    // 1: ya se avisó, atómico
+	stripes []string // WithWriters: archivos vivos de todas las franjas
+}
+
+// WithDiskSpaceMonitor checks the free space of the log volume every
+// interval (DefaultDiskCheckInterval if 0). When it drops below minFree
+// bytes, the condition is reported once, on stderr and Errors, until space
+// is back. With purge, the oldest rotated backups of the main log
+// (app.log.N, app-YYYY-MM-DD.log[.N]) are also deleted, one at a time, until
+// minFree is available again or none is left; live files are never
+// touched, and neither are the backups of AuditMode, which are never
+// deleted. Purging runs on the writer goroutine, between flushes, so it
+// cannot race with rotation.
+//
+// Free space is read with statfs on Linux, macOS, FreeBSD and DragonFly;
+// elsewhere the option has no effect. minFree <= 0 is ignored.
+func WithDiskSpaceMonitor(minFree int64, interval time.Duration, purge bool) Option {
+	return func(conf *config) {
+		if minFree <= 0 {
+			return
+		}
+		if interval <= 0 {
+			interval = DefaultDiskCheckInterval
+		}
+		conf.disk = &diskMonitor{minFree: minFree, every: interval, purge: purge}
+	}
+}
+
+// monitorDisk comprueba el espacio libre hasta que el logger se cierra.
+func (_log *Log) monitorDisk(d *diskMonitor) {
+	defer _log.wg.Done()
+	ticker := time.NewTicker(d.every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-_log.done:
+			return
+		case <-ticker.C:
+		}
+		free, err := diskFree(_log.path)
+		if err != nil || free >= d.minFree {
+			atomic.StoreInt32(&d.low, 0)
+			continue
+		}
+		if atomic.CompareAndSwapInt32(&d.low, 0, 1) {
+			_log.reportError("low disk space on %s: %d bytes free, below %d", _log.path, free, d.minFree)
+		}
+		if d.purge && !_log.archive && _log.onWriter(func() { _log.purgeBackups(d) }) {
+			if free, err := diskFree(_log.path); err == nil && free >= d.minFree {
+				atomic.StoreInt32(&d.low, 0)
+			}
+		}
+	}
+}
+
+// onWriter ejecuta fn en la goroutine writer, como barrier, pero deja de
+// esperar en cuanto el logger se cierra para no retrasar Close.
+func (_log *Log) onWriter(fn func()) bool {
+	ack := make(chan struct{})
+	select {
+	case _log.control <- controlReq{target: _log.queue.enqueued(), ack: ack, run: fn}:
+	case <-_log.done:
+		return false
+	}
+	select {
+	case <-ack:
+		return true
+	case <-_log.done:
+		return false
+	}
+}
+
+// purgeBackups borra los backups más antiguos hasta que haya minFree libres.
+// Writer goroutine only.
+func (_log *Log) purgeBackups(d *diskMonitor) {
+	files, err := _log.backupFiles()
+	if err != nil {
+		_log.reportError("listing backups to purge: %v", err)
+		return
+	}
+	live := make(map[string]bool, len(d.stripes)+1)
+	for _, path := range d.stripes {
+		live[path] = true
+	}
+	if f := _log.getFile(); f != nil {
+		live[f.Name()] = true
+	}
+	type backup struct {
+		path string
+		mod  time.Time
+	}
+	var backups []backup
+	for _, path := range files {
+		info, err := os.Stat(path)
+		if err != nil || live[path] {
+			continue
+		}
+		backups = append(backups, backup{path, info.ModTime()})
+	}
+	sort.Slice(backups, func(i, j int) bool { return backups[i].mod.Before(backups[j].mod) })
+	for _, b := range backups {
+		if free, err := diskFree(_log.path); err != nil || free >= d.minFree {
+			return
+		}
+		if err := os.Remove(b.path); err != nil {
+			_log.reportError("purging backup %s: %v", b.path, err)
+			continue
+		}
+		_log.reportError("low disk space: purged backup %s", b.path)
+	}
+}
@@ -0,0 +1,138 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"fmt"
+	"sync"
+)
+
+// ErrorsBuffer is the capacity of the channel returned by Errors.
+const ErrorsBuffer = 64
+
+// InternalError is a failure of the logger itself, delivered by Errors: a
+// write, rotation or fsync error, a destination that went down, lines
+// dropped by a circuit breaker or a full spool.
+type InternalError struct {
+	Logger string // name of the logger
+	Msg    string // what failed, as printed on stderr
+	Err    error  // underlying error, if any
+}
+
+func (e *InternalError) Error() string {
+	return "acacia: " + e.Logger + ": " + e.Msg
+}
+
+func (e *InternalError) Unwrap() error { return e.Err }
+
+// Errors returns a channel carrying the logger's internal failures as
+// *InternalError, so applications can watch for trouble instead of
+// scraping stderr, where they are still printed. The channel holds
+// ErrorsBuffer errors; while it is full new ones are discarded, so a slow
+// or absent reader never blocks the logger. Write errors are sent once per
+// failure run: the first one after each Sync or Flush, which return it too.
+// The channel is closed by Close.
+func (_log *Log) Errors() <-chan error {
+	return _log.errs.ch
+}
+
+// errSink reparte los errores internos de un logger: stderr y el canal.
+type errSink struct {
+	name   string
+	owner  *Log // quien lo cierra (WithWriters lo comparte con los archivos)
+	mu     sync.Mutex
+	ch     chan error
+	closed bool
+}
+
+func newErrSink(name string) *errSink {
+	return &errSink{name: name, ch: make(chan error, ErrorsBuffer)}
+}
+
+// report prints an internal error on stderr and sends it to Errors. The
+// error is the last argument if it is one. A nil sink only prints.
+func (s *errSink) report(format string, args ...interface{}) {
+	reportInternalError(format, args...)
+	if s == nil {
+		return
+	}
+	e := &InternalError{Logger: s.name, Msg: fmt.Sprintf(format, args...)}
+	if n := len(args); n > 0 {
+		if err, ok := args[n-1].(error); ok {
+			e.Err = err
+		}
+	}
+	s.send(e)
+}
+
+// send entrega err sin bloquear; se pierde si el canal está lleno o cerrado.
+func (s *errSink) send(err error) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if !s.closed {
+		select {
+		case s.ch <- err:
+		default:
+		}
+	}
+	s.mu.Unlock()
+}
+
+func (s *errSink) close() {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.ch)
+	}
+	s.mu.Unlock()
+}
+
+// reportError is reportInternalError for this logger: it also reaches
+// Errors.
+func (_log *Log) reportError(format string, args ...interface{}) {
+	_log.errs.report(format, args...)
+}
+
+// keepWriteErr guarda el primer error de escritura hasta el próximo Sync o
+// Flush y lo envía a Errors. Writer goroutine only.
+func (_log *Log) keepWriteErr(err error) {
+	if _log.writeErr != nil {
+		return
+	}
+	_log.writeErr = err
+	_log.errs.send(&InternalError{Logger: _log.name, Msg: "write error: " + err.Error(), Err: err})
+}
@@ -0,0 +1,147 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// maxEscalationKeys bounds the number of distinct ERROR messages tracked.
+const maxEscalationKeys = 1024
+
+// EscalationFunc is invoked when an ERROR is escalated to CRITICAL.
+type EscalationFunc func(msg string, count int, window time.Duration)
+
+// escalation tracks repeated ERROR messages within a time window.
+type escalation struct {
+	mtx       sync.Mutex
+	threshold int
+	window    time.Duration
+	callback  EscalationFunc
+	seen      map[string]*escalationState
+}
+
+type escalationState struct {
+	start time.Time
+	count int
+	fired bool
+}
+
+// WithErrorEscalation emits a single CRITICAL summary when the same ERROR
+// message fires more than threshold times within window. callback is
+// optional and is called with the message and the count at escalation time.
+func WithErrorEscalation(threshold int, window time.Duration, callback EscalationFunc) Option {
+	return func(conf *config) {
+		if threshold > 0 && window > 0 {
+			conf.escalation = &escalation{
+				threshold: threshold,
+				window:    window,
+				callback:  callback,
+				seen:      make(map[string]*escalationState),
+			}
+		}
+	}
+}
+
+// hit records an occurrence of msg and reports whether it must be escalated
+// along with the count observed in the current window.
+func (e *escalation) hit(msg string, now time.Time) (bool, int) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+
+	st, ok := e.seen[msg]
+	if !ok || now.Sub(st.start) > e.window {
+		if !ok && len(e.seen) >= maxEscalationKeys {
+			e.prune(now)
+		}
+		st = &escalationState{start: now}
+		e.seen[msg] = st
+	}
+	st.count++
+	if st.count > e.threshold && !st.fired {
+		st.fired = true
+		return true, st.count
+	}
+	return false, st.count
+}
+
+// prune drops expired windows; if everything is still live it starts over.
+func (e *escalation) prune(now time.Time) {
+	for k, st := range e.seen {
+		if now.Sub(st.start) > e.window {
+			delete(e.seen, k)
+		}
+	}
+	if len(e.seen) >= maxEscalationKeys {
+		e.seen = make(map[string]*escalationState)
+	}
+}
+
+func (_log *Log) escalate(msg string) {
+	esc := _log.escalation
+	fire, count := esc.hit(msg, time.Now())
+	if !fire {
+		return
+	}
+
+	if _log.structured() {
+		_log.logfString(Level.CRITICAL, map[string]interface{}{
+			"msg":       "repeated ERROR escalated",
+			"error":     msg,
+			"count":     count,
+			"window_ms": esc.window.Milliseconds(),
+		})
+	} else {
+		_log.logfString(Level.CRITICAL, fmt.Sprintf("repeated ERROR escalated: %q fired %d times within %s", msg, count, esc.window))
+	}
+	if esc.callback != nil {
+		esc.callback(msg, count, esc.window)
+	}
+}
+
+// escalationKey identifies "the same ERROR": the format string when one is
+// used, so the same call site with different arguments counts together.
+func escalationKey(data interface{}) string {
+	switch v := data.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
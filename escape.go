@@ -0,0 +1,145 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// WithControlCharEscaping escapes control characters inside text-mode
+// messages, so a user-supplied string cannot forge log lines or corrupt the
+// file: \n and \r become the two characters `\n` and `\r`, other C0 and C1
+// controls and DEL become \xNN or \u00NN. Tabs are kept, and so is a single
+// trailing newline, which only ends the line. Field values are already
+// quoted; structured formats escape on their own.
+func WithControlCharEscaping() Option {
+	return func(conf *config) {
+		conf.escapeControl = true
+	}
+}
+
+// escapeMessage escapes the message carried by data and args, formatting it
+// first when needed. Only plain text lines are affected.
+func (_log *Log) escapeMessage(data interface{}, args []interface{}) (interface{}, []interface{}) {
+	if s, ok := data.(string); ok && len(args) == 0 {
+		return escapeControl(s), nil
+	}
+	return escapeControl(_log.formatMessageString(data, args...)), nil
+}
+
+// controlIndex returns the index of the first control character of s to
+// escape, or -1.
+func controlIndex(s string) int {
+	if strings.HasSuffix(s, "\n") {
+		s = s[:len(s)-1]
+	}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c < ' ' && c != '\t' || c == 0x7f {
+			return i
+		}
+		// C1 (U+0080–U+009F) en UTF-8: 0xC2 0x80–0x9F
+		if c == 0xc2 && i+1 < len(s) && s[i+1] >= 0x80 && s[i+1] <= 0x9f {
+			return i
+		}
+	}
+	return -1
+}
+
+// escapeControl returns s with its control characters escaped, or s itself
+// when there is none.
+func escapeControl(s string) string {
+	i := controlIndex(s)
+	if i < 0 {
+		return s
+	}
+	end := ""
+	if strings.HasSuffix(s, "\n") {
+		s, end = s[:len(s)-1], "\n"
+	}
+	return string(appendEscaped(make([]byte, 0, len(s)+16), s, i)) + end
+}
+
+// escapeControlBytes is escapeControl for byte messages; p is returned as is,
+// without copying, when there is nothing to escape.
+func escapeControlBytes(p []byte) []byte {
+	if !hasControl(p) {
+		return p
+	}
+	return []byte(escapeControl(string(p)))
+}
+
+// hasControl is controlIndex(string(p)) >= 0 without the conversion.
+func hasControl(p []byte) bool {
+	if n := len(p); n > 0 && p[n-1] == '\n' {
+		p = p[:n-1]
+	}
+	for i, c := range p {
+		if c < ' ' && c != '\t' || c == 0x7f || c == 0xc2 && i+1 < len(p) && p[i+1] >= 0x80 && p[i+1] <= 0x9f {
+			return true
+		}
+	}
+	return false
+}
+
+// appendEscaped appends s to dst escaping control characters from index i on.
+func appendEscaped(dst []byte, s string, i int) []byte {
+	dst = append(dst, s[:i]...)
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == '\n':
+			dst = append(dst, `\n`...)
+		case c == '\r':
+			dst = append(dst, `\r`...)
+		case c < ' ' && c != '\t' || c == 0x7f:
+			dst = append(dst, '\\', 'x', hexDigits[c>>4], hexDigits[c&0xf])
+		case c >= utf8.RuneSelf:
+			r, size := utf8.DecodeRuneInString(s[i:])
+			if r >= 0x80 && r <= 0x9f {
+				dst = append(dst, '\\', 'u', '0', '0', hexDigits[r>>4], hexDigits[r&0xf])
+			} else {
+				dst = append(dst, s[i:i+size]...)
+			}
+			i += size
+			continue
+		default:
+			dst = append(dst, c)
+		}
+		i++
+	}
+	return dst
+}
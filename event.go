@@ -0,0 +1,166 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Event is an entry under construction for the chained API:
+//
+//	log.InfoEvent().Str("user", u).Int("n", 3).Msg("login")
+//
+// Events come from a pool and go back to it on Msg, Msgf or Send, so an
+// Event must not be used after that. Building an event does not allocate
+// (Any excepted), not even the field slice that the *Fields methods take.
+// When the level is disabled the *Event methods return nil and the chain
+// does nothing.
+type Event struct {
+	log    *Log
+	level  string
+	fields []Field
+}
+
+var eventPool = sync.Pool{New: func() interface{} { return &Event{fields: make([]Field, 0, 16)} }}
+
+// DebugEvent starts a DEBUG entry, or returns nil if DEBUG is disabled.
+func (_log *Log) DebugEvent() *Event { return _log.newEvent(Level.DEBUG) }
+
+// InfoEvent starts an INFO entry, or returns nil if INFO is disabled.
+func (_log *Log) InfoEvent() *Event { return _log.newEvent(Level.INFO) }
+
+// WarnEvent starts a WARN entry, or returns nil if WARN is disabled.
+func (_log *Log) WarnEvent() *Event { return _log.newEvent(Level.WARN) }
+
+// ErrorEvent starts an ERROR entry, or returns nil if ERROR is disabled.
+func (_log *Log) ErrorEvent() *Event { return _log.newEvent(Level.ERROR) }
+
+// CriticalEvent starts a CRITICAL entry, or returns nil if CRITICAL is
+// disabled.
+func (_log *Log) CriticalEvent() *Event { return _log.newEvent(Level.CRITICAL) }
+
+func (_log *Log) newEvent(level string) *Event {
+	// con WithRecentBuffer se recuerda aunque el nivel esté desactivado
+	if _log.recent == nil && !_log.shouldLog(level) {
+		return nil
+	}
+	e := eventPool.Get().(*Event)
+	e.log, e.level = _log, level
+	return e
+}
+
+// Str adds a string field.
+func (e *Event) Str(key, value string) *Event { return e.add(String(key, value)) }
+
+// Int adds an int field.
+func (e *Event) Int(key string, value int) *Event { return e.add(Int(key, value)) }
+
+// Int64 adds an int64 field.
+func (e *Event) Int64(key string, value int64) *Event { return e.add(Int64(key, value)) }
+
+// Uint64 adds a uint64 field.
+func (e *Event) Uint64(key string, value uint64) *Event { return e.add(Uint64(key, value)) }
+
+// Float64 adds a float64 field.
+func (e *Event) Float64(key string, value float64) *Event { return e.add(Float64(key, value)) }
+
+// Bool adds a bool field.
+func (e *Event) Bool(key string, value bool) *Event { return e.add(Bool(key, value)) }
+
+// Dur adds a time.Duration field.
+func (e *Event) Dur(key string, value time.Duration) *Event { return e.add(Duration(key, value)) }
+
+// Time adds a time.Time field.
+func (e *Event) Time(key string, value time.Time) *Event { return e.add(Time(key, value)) }
+
+// Err adds an "error" field (see Err).
+func (e *Event) Err(err error) *Event { return e.add(Err(err)) }
+
+// Any adds a field for an arbitrary value (see Any).
+func (e *Event) Any(key string, value interface{}) *Event { return e.add(Any(key, value)) }
+
+// Fields adds already built fields.
+func (e *Event) Fields(fields ...Field) *Event {
+	if e == nil {
+		return nil
+	}
+	e.fields = append(e.fields, fields...)
+	return e
+}
+
+func (e *Event) add(f Field) *Event {
+	if e == nil {
+		return nil
+	}
+	e.fields = append(e.fields, f)
+	return e
+}
+
+// Msg writes the entry with msg and releases the event.
+func (e *Event) Msg(msg string) {
+	if e == nil {
+		return
+	}
+	e.log.logFields(e.level, msg, e.fields)
+	e.release()
+}
+
+// Msgf writes the entry with a fmt.Sprintf message and releases the event.
+func (e *Event) Msgf(format string, args ...interface{}) {
+	if e == nil {
+		return
+	}
+	e.Msg(fmt.Sprintf(format, args...))
+}
+
+// Send writes the entry with an empty message and releases the event.
+func (e *Event) Send() {
+	e.Msg("")
+}
+
+func (e *Event) release() {
+	// no retener valores del llamador en el pool
+	for i := range e.fields {
+		e.fields[i] = Field{}
+	}
+	if cap(e.fields) > 64 {
+		// eventos con muchos campos no se quedan en el pool
+		return
+	}
+	e.log, e.fields = nil, e.fields[:0]
+	eventPool.Put(e)
+}
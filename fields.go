@@ -0,0 +1,477 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+	"unicode/utf8"
+)
+
+type fieldKind uint8
+
+const (
+	fieldString fieldKind = iota
+	fieldInt
+	fieldUint
+	fieldFloat
+	fieldBool
+	fieldDuration
+	fieldTime
+	fieldError
+	fieldAny
+)
+
+// Field is a typed key/value pair for the *Fields logging methods. Building
+// fields does not allocate, and entries are encoded without maps or
+// reflection (except for Any).
+type Field struct {
+	Key   string
+	kind  fieldKind
+	num   int64
+	str   string
+	iface interface{}
+}
+
+// String returns a string field.
+func String(key, value string) Field { return Field{Key: key, kind: fieldString, str: value} }
+
+// Int returns an int field.
+func Int(key string, value int) Field { return Field{Key: key, kind: fieldInt, num: int64(value)} }
+
+// Int64 returns an int64 field.
+func Int64(key string, value int64) Field { return Field{Key: key, kind: fieldInt, num: value} }
+
+// Uint64 returns a uint64 field.
+func Uint64(key string, value uint64) Field {
+	return Field{Key: key, kind: fieldUint, num: int64(value)}
+}
+
+// Float64 returns a float64 field. NaN and infinities are encoded as strings.
+func Float64(key string, value float64) Field {
+	return Field{Key: key, kind: fieldFloat, num: int64(math.Float64bits(value))}
+}
+
+// Bool returns a bool field.
+func Bool(key string, value bool) Field {
+	var n int64
+	if value {
+		n = 1
+	}
+	return Field{Key: key, kind: fieldBool, num: n}
+}
+
+// Duration returns a field rendered with time.Duration.String ("1.5s").
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, kind: fieldDuration, num: int64(value)}
+}
+
+// Time returns a field rendered as RFC 3339 with nanoseconds.
+func Time(key string, value time.Time) Field {
+	return Field{Key: key, kind: fieldTime, num: value.UnixNano(), iface: value.Location()}
+}
+
+// Err returns an "error" field holding err.Error(), or null for a nil error.
+func Err(err error) Field { return Field{Key: "error", kind: fieldError, iface: err} }
+
+// Any returns a field for an arbitrary value, encoded like values of
+// structured maps (Marshaler, TextMarshaler, error, Stringer, then the
+// Encoder). It is the only field type that may allocate.
+func Any(key string, value interface{}) Field { return Field{Key: key, kind: fieldAny, iface: value} }
+
+// DebugFields logs msg with typed fields at DEBUG level.
+func (_log *Log) DebugFields(msg string, fields ...Field) {
+	_log.logFields(Level.DEBUG, msg, fields)
+}
+
+// InfoFields logs msg with typed fields at INFO level.
+func (_log *Log) InfoFields(msg string, fields ...Field) {
+	_log.logFields(Level.INFO, msg, fields)
+}
+
+// WarnFields logs msg with typed fields at WARN level.
+func (_log *Log) WarnFields(msg string, fields ...Field) {
+	_log.logFields(Level.WARN, msg, fields)
+}
+
+// ErrorFields logs msg with typed fields at ERROR level.
+func (_log *Log) ErrorFields(msg string, fields ...Field) {
+	_log.logFields(Level.ERROR, msg, fields)
+}
+
+// CriticalFields logs msg with typed fields at CRITICAL level.
+func (_log *Log) CriticalFields(msg string, fields ...Field) {
+	_log.logFields(Level.CRITICAL, msg, fields)
+}
+
+// logFields runs the transforms, if any, and writes the entry.
+func (_log *Log) logFields(level string, msg string, fields []Field) {
+	if _log.recent != nil {
+		_log.rememberFields(level, msg, fields)
+	}
+	_log.transformFields(level, msg, fields)
+}
+
+// transformFields is logFields for entries the recent buffer already has.
+func (_log *Log) transformFields(level string, msg string, fields []Field) {
+	if !_log.shouldLog(level) {
+		return
+	}
+	if _log.transforms != nil {
+		level, msg, fields = _log.transform(level, msg, fields)
+		if !_log.shouldLog(level) {
+			return
+		}
+	}
+	_log.writeFields(level, msg, fields)
+}
+
+// writeFields builds the complete line on the producer: JSON or logfmt
+// entries, or the text line followed by key=value pairs.
+func (_log *Log) writeFields(level string, msg string, fields []Field) {
+	ev, ok := _log.fieldsEvent(level, msg, "", fields)
+	if !ok {
+		return
+	}
+	_log.enqueue(ev)
+	if level == Level.ERROR && _log.escalation != nil {
+		_log.escalate(msg)
+	}
+}
+
+// fieldsEvent encodes an entry into a queue event, or returns false if the
+// sampler drops it. An empty id gets a generated one.
+func (_log *Log) fieldsEvent(level, msg, id string, fields []Field) (logEvent, bool) {
+	if _log.maxMessageSize > 0 {
+		msg = _log.truncateMessage(msg)
+	}
+	if _log.escapeControl && _log.lineFormat() == Format.Text {
+		msg = escapeControl(msg)
+	}
+	if _log.sampler != nil && !_log.sample(level, hashString(msg)) {
+		return logEvent{}, false
+	}
+	if level == Level.CRITICAL && _log.critMirror != nil {
+		_log.critMirror.mirror(msg)
+	}
+
+	if _log.redact != nil {
+		fields = _log.redact.redactFields(fields)
+	}
+	stack := _log.stackFor(level)
+	if _log.lineFormat() != Format.Text {
+		fields = _log.appendExtraFields(fields, stack)
+	}
+
+	if id == "" {
+		id = _log.nextID()
+	}
+	buf := _log.encodeFields(level, msg, id, fields, stack)
+	var dupLen uint32
+	if _log.dedup != nil {
+		if _log.lineFormat() == Format.Text {
+			buf, dupLen = lineIdentity(buf)
+		} else {
+			buf, dupLen = _log.fieldsIdentity(buf, level, msg, fields)
+		}
+	}
+	kind := eventRaw
+	if _log.lineFormat() == Format.Binary {
+		kind = eventBinary
+	}
+	return logEvent{msgBytes: buf, ts: _log.eventTime(), level: uint8(levelRank(level)), kind: kind, dupLen: dupLen, entry: _log.filterEntry(level, msg, id, fields), route: _log.fieldRoute(fields)}, true
+}
+
+// encodeFields renders an entry in the current format into a pooled buffer.
+func (_log *Log) encodeFields(level, msg, id string, fields []Field, stack string) []byte {
+	var buf []byte
+	switch _log.lineFormat() {
+	case Format.JSON:
+		buf = _log.appendJSONEntry(getBufCap(64+len(msg)+32*len(fields)), level, msg, id, fields)
+	case Format.Logfmt:
+		buf = _log.appendLogfmtEntry(getBufCap(64+len(msg)+32*len(fields)), level, msg, id, fields)
+	case Format.CEF:
+		buf = _log.appendCEFEntry(getBufCap(96+len(msg)+32*len(fields)), level, msg, id, fields)
+	case Format.Syslog:
+		buf = _log.appendSyslogEntry(getBufCap(128+len(msg)+32*len(fields)), level, msg, id, fields)
+	case Format.Pretty:
+		buf = _log.appendPrettyEntry(getBufCap(96+len(msg)+32*len(fields)), level, msg, id, fields)
+	case Format.Custom:
+		buf = _log.appendCustomEntry(getBufCap(64+len(msg)+32*len(fields)), level, msg, id, fields)
+	case Format.Binary:
+		buf = _log.appendBinaryPayload(getBufCap(16+len(msg)+24*len(fields)), level, msg, id, fields)
+	default:
+		buf = _log.lineHeader(len(msg)+32*len(fields), level, id)
+		buf = append(buf, msg...)
+		for i := range fields {
+			buf = append(buf, internKey(fields[i].Key).text...)
+			buf = appendFieldText(buf, &fields[i])
+		}
+		if stack != "" {
+			buf = append(buf, appendStackText("", stack)...)
+		}
+		buf = append(buf, '\n')
+	}
+	return buf
+}
+
+// appendExtraFields returns fields followed by the ones the logger adds on its
+// own (metadata, stack, goroutine). The caller's slice is never modified.
+func (_log *Log) appendExtraFields(fields []Field, stack string) []Field {
+	once := _log.takeOnceFields()
+	if stack == "" && !_log.goroutineID && len(_log.metaFields) == 0 && once == nil {
+		return fields
+	}
+	out := make([]Field, 0, len(fields)+len(_log.metaFields)+len(once)+2)
+	out = append(out, fields...)
+	out = append(out, _log.metaFields...)
+	out = append(out, once...)
+	if stack != "" {
+		out = append(out, String("stack", stack))
+	}
+	if _log.goroutineID {
+		out = append(out, Int64("goroutine", goroutineID()))
+	}
+	return out
+}
+
+// appendJSONEntry encodes {"ts":…,"level":…,"msg":…,fields…,"id":…} plus a
+// newline into dst, without maps or reflection.
+func (_log *Log) appendJSONEntry(dst []byte, level, msg, id string, fields []Field) []byte {
+	s := _log.schema
+	if _log.noTimestamp {
+		dst = append(dst, '{')
+		dst = append(dst, s.levelPrefix[1:]...)
+	} else {
+		dst = append(dst, s.tsPrefix...)
+		dst = _log.appendJSONTime(dst)
+		dst = append(dst, s.levelPrefix...)
+	}
+	dst = append(dst, s.levelName(level)...)
+	if n, ok := _log.severity(level); ok {
+		dst = append(dst, `","`...)
+		dst = append(dst, severityKey(s)...)
+		dst = append(dst, `":`...)
+		dst = strconv.AppendInt(dst, int64(n), 10)
+		dst = append(dst, s.msgPrefix[1:]...)
+	} else {
+		dst = append(dst, s.msgPrefix...)
+	}
+	dst = appendJSONString(dst, msg)
+	for i := range fields {
+		dst = append(dst, internKey(fields[i].Key).json...)
+		dst = _log.appendFieldJSON(dst, &fields[i])
+	}
+	if id != "" {
+		dst = append(dst, `,"id":`...)
+		dst = appendJSONString(dst, id)
+	}
+	return append(dst, '}', '\n')
+}
+
+// appendJSONTime appends the "ts" value the same way formatStructuredLog does.
+func (_log *Log) appendJSONTime(dst []byte) []byte {
+	if _log.epochUnit != 0 {
+		return strconv.AppendInt(dst, _log.clock().UnixNano()/int64(_log.epochUnit), 10)
+	}
+	if cachedTS := _log.cachedTime.Load(); cachedTS != nil && !_log.preciseTS {
+		return appendJSONBytes(dst, cachedTS.([]byte))
+	}
+	dst = append(dst, '"')
+	dst = _log.now().AppendFormat(dst, _log.timestampLayout())
+	return append(dst, '"')
+}
+
+func (_log *Log) appendFieldJSON(dst []byte, f *Field) []byte {
+	switch f.kind {
+	case fieldString:
+		return appendJSONString(dst, f.str)
+	case fieldInt:
+		return strconv.AppendInt(dst, f.num, 10)
+	case fieldUint:
+		return strconv.AppendUint(dst, uint64(f.num), 10)
+	case fieldFloat:
+		v := math.Float64frombits(uint64(f.num))
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			dst = append(dst, '"')
+			dst = strconv.AppendFloat(dst, v, 'g', -1, 64)
+			return append(dst, '"')
+		}
+		return appendJSONFloat(dst, v)
+	case fieldBool:
+		return strconv.AppendBool(dst, f.num == 1)
+	case fieldDuration, fieldTime:
+		dst = append(dst, '"')
+		dst = appendFieldTime(dst, f)
+		return append(dst, '"')
+	case fieldError:
+		if f.iface == nil {
+			return append(dst, "null"...)
+		}
+		return appendJSONString(dst, callString(f.iface, f.iface.(error).Error))
+	}
+	encoded, err := _log.encoder.Marshal(fieldValue(f.iface))
+	if err != nil {
+		return appendJSONString(dst, "!Marshal error: "+err.Error())
+	}
+	return append(dst, encoded...)
+}
+
+// appendFieldText renders a field value for text lines, quoting strings that
+// contain spaces, quotes or control characters.
+func appendFieldText(dst []byte, f *Field) []byte {
+	switch f.kind {
+	case fieldString:
+		return appendTextValue(dst, f.str)
+	case fieldInt:
+		return strconv.AppendInt(dst, f.num, 10)
+	case fieldUint:
+		return strconv.AppendUint(dst, uint64(f.num), 10)
+	case fieldFloat:
+		return strconv.AppendFloat(dst, math.Float64frombits(uint64(f.num)), 'g', -1, 64)
+	case fieldBool:
+		return strconv.AppendBool(dst, f.num == 1)
+	case fieldDuration, fieldTime:
+		return appendFieldTime(dst, f)
+	case fieldError:
+		if f.iface == nil {
+			return append(dst, "<nil>"...)
+		}
+		return appendTextValue(dst, callString(f.iface, f.iface.(error).Error))
+	}
+	if s, ok := fieldValue(f.iface).(string); ok {
+		return appendTextValue(dst, s)
+	}
+	return appendTextValue(dst, fmt.Sprint(f.iface))
+}
+
+func appendFieldTime(dst []byte, f *Field) []byte {
+	if f.kind == fieldDuration {
+		return append(dst, time.Duration(f.num).String()...)
+	}
+	t := time.Unix(0, f.num)
+	if loc, ok := f.iface.(*time.Location); ok && loc != nil {
+		t = t.In(loc)
+	}
+	return t.AppendFormat(dst, time.RFC3339Nano)
+}
+
+func appendTextValue(dst []byte, s string) []byte {
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c <= ' ' || c == '"' || c == '=' || c == 0x7f {
+			return strconv.AppendQuote(dst, s)
+		}
+	}
+	if s == "" {
+		return append(dst, `""`...)
+	}
+	return append(dst, s...)
+}
+
+// appendJSONFloat follows encoding/json: exponent notation only for very
+// small or very large magnitudes.
+func appendJSONFloat(dst []byte, v float64) []byte {
+	format := byte('f')
+	if abs := math.Abs(v); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	return strconv.AppendFloat(dst, v, format, -1, 64)
+}
+
+const hexDigits = "0123456789abcdef"
+
+// appendJSONString appends s as a JSON string, escaping like encoding/json
+// (minus HTML escaping) and replacing invalid UTF-8 with U+FFFD.
+func appendJSONString(dst []byte, s string) []byte {
+	dst = append(dst, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' {
+				i++
+				continue
+			}
+			dst = append(dst, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				dst = append(dst, '\\', c)
+			case '\n':
+				dst = append(dst, '\\', 'n')
+			case '\r':
+				dst = append(dst, '\\', 'r')
+			case '\t':
+				dst = append(dst, '\\', 't')
+			default:
+				dst = append(dst, '\\', 'u', '0', '0', hexDigits[c>>4], hexDigits[c&0xf])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, "\ufffd"...)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			dst = append(dst, s[start:i]...)
+			dst = append(dst, '\\', 'u', '2', '0', '2', hexDigits[r&0xf])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	dst = append(dst, s[start:]...)
+	return append(dst, '"')
+}
+
+func appendJSONBytes(dst []byte, b []byte) []byte {
+	// el timestamp cacheado no lleva caracteres a escapar salvo en layouts raros
+	for _, c := range b {
+		if c < 0x20 || c == '"' || c == '\\' || c >= utf8.RuneSelf {
+			return appendJSONString(dst, string(b))
+		}
+	}
+	dst = append(dst, '"')
+	dst = append(dst, b...)
+	return append(dst, '"')
+}
@@ -0,0 +1,105 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import "os"
+
+// FileHook returns text to write into a log file when it is created or
+// closed by rotation: file is the file being written, other the file it
+// follows (for headers; "" for the first one) or continues in (for footers).
+// A trailing newline is added when missing; "" writes nothing.
+type FileHook func(file, other string) string
+
+// WithFileHeader writes fn's text at the top of every new log file: the
+// initial one when Start creates it (or, with WithLazyOpen, when it is
+// opened empty) and each one opened by rotation, before the WithHeader
+// banner. Hooks run on the writer goroutine, and have no effect with
+// WithHashChain or Format.Binary, whose files must contain records only.
+func WithFileHeader(fn FileHook) Option {
+	return func(conf *config) {
+		conf.fileHeader = fn
+	}
+}
+
+// WithFileFooter writes fn's text at the end of each file closed by
+// rotation, e.g. "continued in app.log". file is the name the closed file
+// gets (app.log.0, app-2025-11-18.log). Same restrictions as WithFileHeader.
+func WithFileFooter(fn FileHook) Option {
+	return func(conf *config) {
+		conf.fileFooter = fn
+	}
+}
+
+// fileHooksAllowed reports whether hook text may be mixed with records.
+func (_log *Log) fileHooksAllowed() bool {
+	return _log.chain == nil && _log.lineFormat() != Format.Binary
+}
+
+// appendHookText appends the text of a hook, newline-terminated.
+func appendHookText(dst []byte, text string) []byte {
+	if text == "" {
+		return dst
+	}
+	dst = append(dst, text...)
+	if text[len(text)-1] != '\n' {
+		dst = append(dst, '\n')
+	}
+	return dst
+}
+
+// writeFileHeader writes the header hook into f when it is empty, so that
+// appending to an existing file adds nothing.
+func (_log *Log) writeFileHeader(f *os.File, previous string) {
+	if _log.fileHeader == nil || !_log.fileHooksAllowed() {
+		return
+	}
+	if info, err := f.Stat(); err != nil || info.Size() > 0 {
+		return
+	}
+	buf := appendHookText(getBuf(), _log.fileHeader(f.Name(), previous))
+	_log.writeOut(f, buf)
+	putBuf(buf)
+}
+
+// writeFileFooter writes the footer hook into f, which rotation is about to
+// rename to file, while next takes its place.
+func (_log *Log) writeFileFooter(f *os.File, file, next string) {
+	if _log.fileFooter == nil || f == nil || !_log.fileHooksAllowed() {
+		return
+	}
+	buf := appendHookText(getBuf(), _log.fileFooter(file, next))
+	_log.writeOut(f, buf)
+	putBuf(buf)
+}
@@ -0,0 +1,116 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// FilterFunc decides whether an entry is written: returning false drops it.
+type FilterFunc func(e Entry) bool
+
+// WithFilter registers a filter that can drop entries centrally, for example
+// health-check request logs. Filters run on the writer goroutine, in the order
+// they were added, so they must not block; an entry is written only if every
+// filter returns true. With WithWriters each file has its own writer, but
+// filters still run for one entry at a time. Dropped entries are counted by
+// Filtered.
+func WithFilter(f FilterFunc) Option {
+	return func(conf *config) {
+		if f != nil {
+			conf.filters = append(conf.filters, f)
+		}
+	}
+}
+
+// Filtered returns the number of entries dropped by filters.
+func (_log *Log) Filtered() uint64 {
+	n := atomic.LoadUint64(&_log.filtered)
+	for _, shard := range _log.shards {
+		n += shard.Filtered()
+	}
+	return n
+}
+
+// filterEntry returns the Entry filters will see for a record built on the
+// producer, or nil when there are no filters. fields is copied: the writer
+// reads it after the logging call returned.
+func (_log *Log) filterEntry(level, msg, id string, fields []Field) *Entry {
+	if _log.filters == nil {
+		return nil
+	}
+	if fields != nil {
+		fields = append([]Field(nil), fields...)
+	}
+	return &Entry{
+		Time:    _log.now(),
+		Level:   level,
+		Logger:  _log.name,
+		Message: msg,
+		ID:      id,
+		Fields:  fields,
+	}
+}
+
+// passesFilters runs the filters over ev. Writer goroutine only.
+func (_log *Log) passesFilters(ev *logEvent) bool {
+	e := ev.entry
+	if e == nil {
+		// eventos del fast path: la entrada se arma aquí, no en el productor
+		e = &Entry{Level: string(levelBytesFor(ev.level)), Logger: _log.name}
+		if ev.ts != 0 {
+			e.Time = time.Unix(0, ev.ts)
+		} else {
+			e.Time = _log.now()
+		}
+		if ev.kind == eventString {
+			e.Message = ev.msgStr
+		} else {
+			e.Message = string(ev.msgBytes)
+		}
+	}
+	if _log.filterMtx != nil {
+		_log.filterMtx.Lock()
+		defer _log.filterMtx.Unlock()
+	}
+	for _, f := range _log.filters {
+		if !f(*e) {
+			atomic.AddUint64(&_log.filtered, 1)
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,395 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrLiveFile is returned by ForgetFile when dst is the file a running
+// logger of this process is writing to.
+var ErrLiveFile = errors.New("acacia: file is being written by a running logger")
+
+// openLogs holds the running loggers, so ForgetFile can tell a live file.
+var openLogs = struct {
+	sync.Mutex
+	logs map[*Log]struct{}
+}{logs: make(map[*Log]struct{})}
+
+// ForgetRule selects the records a right-to-be-forgotten rewrite acts on:
+// those with a field Key equal to Value, as a JSON member ("user_id":"42"
+// or "user_id":42) or a key=value pair of text and logfmt lines.
+type ForgetRule struct {
+	Key   string
+	Value string
+	// Mask keeps matching records with the value replaced by MaskText
+	// instead of removing them. Mentions of the value outside the field
+	// (in the message text) are left as they are.
+	Mask     bool
+	MaskText string // DefaultRedactMask if empty
+}
+
+// ForgetReport describes a Forget rewrite, for the record of the request.
+type ForgetReport struct {
+	Source  string    `json:"source,omitempty"`
+	Output  string    `json:"output,omitempty"`
+	Key     string    `json:"key"`
+	Records int       `json:"records"` // records read
+	Removed int       `json:"removed"`
+	Masked  int       `json:"masked"` // field values replaced
+	Lines   []int     `json:"lines"`  // first line of each affected record, 1-based
+	Done    time.Time `json:"done"`
+}
+
+// Forget copies the log records read from r to w, removing (or masking,
+// see ForgetRule.Mask) the ones whose field rule.Key has rule.Value. A
+// record is a line plus its continuation lines (multi-line messages, stack
+// traces), which go with it. Lines are otherwise copied byte for byte.
+func Forget(r io.Reader, w io.Writer, rule ForgetRule) (*ForgetReport, error) {
+	if rule.Key == "" {
+		return nil, fmt.Errorf("forget rule needs a field key")
+	}
+	if rule.MaskText == "" {
+		rule.MaskText = DefaultRedactMask
+	}
+	rep := &ForgetReport{Key: rule.Key, Lines: []int{}}
+	parser := NewReader(nil, Query{})
+	in := bufio.NewReader(r)
+	out := bufio.NewWriter(w)
+
+	var record []byte
+	first, lineNo := 0, 0
+	emit := func() error {
+		if len(record) == 0 {
+			return nil
+		}
+		rep.Records++
+		fixed, n := forgetRecord(record, &rule)
+		switch {
+		case n == 0:
+		case !rule.Mask:
+			rep.Removed++
+			rep.Lines = append(rep.Lines, first)
+			record = record[:0]
+			return nil
+		default:
+			rep.Masked += n
+			rep.Lines = append(rep.Lines, first)
+			record = fixed
+		}
+		_, err := out.Write(record)
+		record = record[:0]
+		return err
+	}
+	for {
+		line, err := in.ReadBytes('\n')
+		if len(line) > 0 {
+			lineNo++
+			if forgetRecordStart(parser, line) || len(record) == 0 {
+				if err := emit(); err != nil {
+					return rep, err
+				}
+				first = lineNo
+			}
+			record = append(record, line...)
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return rep, err
+		}
+	}
+	if err := emit(); err != nil {
+		return rep, err
+	}
+	rep.Done = time.Now()
+	return rep, out.Flush()
+}
+
+// ForgetFile writes to dst a copy of the log file src without the records
+// matched by rule (see Forget). dst may be src itself: the copy is written
+// next to it and renamed into place, so readers see one file or the other.
+// Files ending in .gz (WithGzipOutput) are read and written compressed.
+// The report is returned even when nothing matched.
+//
+// A running logger would keep writing to the file renamed over, and lose
+// those records, so dst cannot be the live file of a logger of this process
+// (ErrLiveFile). Clean backups, or move the live file aside and call Reopen
+// first, as logrotate does; another process writing to dst must be handled
+// the same way.
+func ForgetFile(src, dst string, rule ForgetRule) (*ForgetReport, error) {
+	if lg := liveLogFor(dst); lg != nil {
+		return nil, fmt.Errorf("%w: %s (logger %s)", ErrLiveFile, dst, lg.name)
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return nil, err
+	}
+	var r io.Reader = in
+	if strings.HasSuffix(src, gzipExt) {
+		zr, err := gzip.NewReader(in)
+		if err != nil {
+			return nil, err
+		}
+		r = zr
+	}
+
+	tmp := fmt.Sprintf("%s.tmp-%d", dst, time.Now().UnixNano())
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_EXCL|os.O_WRONLY, info.Mode().Perm())
+	if err != nil {
+		return nil, err
+	}
+	var w io.Writer = f
+	var zw *gzip.Writer
+	if strings.HasSuffix(dst, gzipExt) {
+		zw = gzip.NewWriter(f)
+		w = zw
+	}
+	rep, err := Forget(r, w, rule)
+	if err == nil && zw != nil {
+		err = zw.Close()
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, dst)
+	}
+	if err != nil {
+		_ = os.Remove(tmp)
+		return rep, err
+	}
+	rep.Source, rep.Output = filepath.Clean(src), filepath.Clean(dst)
+	return rep, nil
+}
+
+// trackOpenLog registra _log mientras corre; untrackOpenLog lo quita al cerrar.
+func (_log *Log) trackOpenLog() {
+	openLogs.Lock()
+	openLogs.logs[_log] = struct{}{}
+	openLogs.Unlock()
+}
+
+func (_log *Log) untrackOpenLog() {
+	openLogs.Lock()
+	delete(openLogs.logs, _log)
+	openLogs.Unlock()
+}
+
+// liveLogFor returns the running logger whose current file is path, or nil.
+func liveLogFor(path string) *Log {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil
+	}
+	openLogs.Lock()
+	defer openLogs.Unlock()
+	for lg := range openLogs.logs {
+		if f := lg.getFile(); f != nil {
+			if fi, err := f.Stat(); err == nil && os.SameFile(info, fi) {
+				return lg
+			}
+		}
+	}
+	return nil
+}
+
+// forgetRecordStart reports whether line starts a record: a text or JSON
+// line the Reader parses, or a logfmt line.
+func forgetRecordStart(parser *Reader, line []byte) bool {
+	if bytes.HasPrefix(line, []byte("ts=")) || bytes.HasPrefix(line, []byte("level=")) {
+		return true
+	}
+	_, ok := parser.parseLine(bytes.TrimRight(line, "\r\n"))
+	return ok
+}
+
+// forgetRecord busca el campo de la regla en el registro; devuelve cuántas
+// veces aparece y, con Mask, el registro con el valor enmascarado.
+func forgetRecord(record []byte, rule *ForgetRule) ([]byte, int) {
+	if record[0] == '{' {
+		return forgetJSON(record, rule)
+	}
+	return forgetPairs(record, rule)
+}
+
+// forgetJSON trata los miembros "key": valor de una línea JSON.
+func forgetJSON(record []byte, rule *ForgetRule) ([]byte, int) {
+	name, _ := json.Marshal(rule.Key)
+	var out []byte
+	n, last := 0, 0
+	for off := 0; ; {
+		i := bytes.Index(record[off:], name)
+		if i < 0 {
+			break
+		}
+		i += off
+		off = i + len(name)
+		if p := bytes.TrimRight(record[:i], " \t"); len(p) == 0 || (p[len(p)-1] != '{' && p[len(p)-1] != ',') {
+			continue
+		}
+		colon := off
+		for colon < len(record) && (record[colon] == ' ' || record[colon] == '\t') {
+			colon++
+		}
+		if colon == len(record) || record[colon] != ':' {
+			continue
+		}
+		dec := json.NewDecoder(bytes.NewReader(record[colon+1:]))
+		dec.UseNumber()
+		var v interface{}
+		if dec.Decode(&v) != nil {
+			continue
+		}
+		end := colon + 1 + int(dec.InputOffset())
+		if !forgetValueMatches(v, rule.Value) {
+			off = end
+			continue
+		}
+		n++
+		if rule.Mask {
+			mask, _ := json.Marshal(rule.MaskText)
+			out = append(out, record[last:colon+1]...)
+			out = append(out, mask...)
+			last = end
+		}
+		off = end
+	}
+	if n == 0 || !rule.Mask {
+		return record, n
+	}
+	return append(out, record[last:]...), n
+}
+
+func forgetValueMatches(v interface{}, want string) bool {
+	switch v := v.(type) {
+	case string:
+		return v == want
+	case json.Number:
+		return v.String() == want
+	case bool:
+		return strconv.FormatBool(v) == want
+	}
+	return false
+}
+
+// forgetPairs trata los pares key=valor de líneas de texto y logfmt; los
+// valores pueden ir entre comillas.
+func forgetPairs(record []byte, rule *ForgetRule) ([]byte, int) {
+	name := []byte(rule.Key + "=")
+	var out []byte
+	n, last := 0, 0
+	for off := 0; ; {
+		i := bytes.Index(record[off:], name)
+		if i < 0 {
+			break
+		}
+		i += off
+		off = i + len(name)
+		if i > 0 && record[i-1] != ' ' && record[i-1] != '\t' {
+			continue
+		}
+		start, end := off, off
+		var value string
+		if end < len(record) && record[end] == '"' {
+			q := quotedEnd(record[end:])
+			if q < 0 {
+				continue
+			}
+			end += q
+			v, err := strconv.Unquote(string(record[start:end]))
+			if err != nil {
+				continue
+			}
+			value = v
+		} else {
+			for end < len(record) && record[end] != ' ' && record[end] != '\t' && record[end] != '\n' && record[end] != '\r' {
+				end++
+			}
+			value = string(record[start:end])
+		}
+		off = end
+		if value != rule.Value {
+			continue
+		}
+		n++
+		if rule.Mask {
+			out = append(out, record[last:start]...)
+			out = appendTextValue(out, rule.MaskText)
+			last = end
+		}
+	}
+	if n == 0 || !rule.Mask {
+		return record, n
+	}
+	return append(out, record[last:]...), n
+}
+
+// quotedEnd devuelve la longitud del literal entre comillas al inicio de b,
+// o -1 si no se cierra.
+func quotedEnd(b []byte) int {
+	for i := 1; i < len(b); i++ {
+		switch b[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		case '\n':
+			return -1
+		}
+	}
+	return -1
+}
@@ -0,0 +1,148 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Entry is a log record as handed to a Formatter. Logger is the log file
+// name given to Start. Fields holds the typed fields of *Fields calls, or the
+// keys of structured maps and structs (sorted, without msg and id).
+type Entry struct {
+	Time    time.Time
+	Level   string
+	Logger  string
+	Message string
+	ID      string
+	Fields  []Field
+}
+
+// Formatter lays out entries. AppendEntry appends one line for e to dst and
+// returns the extended slice; a trailing newline is added when missing. It
+// runs on the logging goroutine and must be safe for concurrent use.
+type Formatter interface {
+	AppendEntry(dst []byte, e Entry) []byte
+}
+
+// FormatterFunc adapts a function to the Formatter interface.
+type FormatterFunc func(dst []byte, e Entry) []byte
+
+// AppendEntry calls f(dst, e).
+func (f FormatterFunc) AppendEntry(dst []byte, e Entry) []byte { return f(dst, e) }
+
+// WithFormatter hands every record to f (CSV, pipe-delimited, localized
+// labels…) and makes Format.Custom the initial format. Lines still go through
+// the batching writer, rotation and sync policy.
+func WithFormatter(f Formatter) Option {
+	return func(conf *config) {
+		if f != nil {
+			conf.formatter = f
+		}
+	}
+}
+
+// Value returns the field value as a Go value: string, int64, uint64,
+// float64, bool, time.Duration, time.Time, error or, for Any, the original
+// value.
+func (f Field) Value() interface{} {
+	switch f.kind {
+	case fieldString:
+		return f.str
+	case fieldInt:
+		return f.num
+	case fieldUint:
+		return uint64(f.num)
+	case fieldFloat:
+		return math.Float64frombits(uint64(f.num))
+	case fieldBool:
+		return f.num == 1
+	case fieldDuration:
+		return time.Duration(f.num)
+	case fieldTime:
+		t := time.Unix(0, f.num)
+		if loc, ok := f.iface.(*time.Location); ok && loc != nil {
+			t = t.In(loc)
+		}
+		return t
+	}
+	if l, ok := f.iface.(*LazyValue); ok {
+		return l.Value()
+	}
+	return f.iface
+}
+
+// formatCustom turns a structured map into an Entry for the Formatter.
+func (_log *Log) formatCustom(level string, fields map[string]interface{}) []byte {
+	msg, id, typed := mapEntryParts(fields)
+	return _log.appendCustomEntry(getBufCap(64+len(msg)+32*len(typed)), level, msg, id, typed)
+}
+
+// mapEntryParts splits a structured map into message, ID and the remaining
+// keys as sorted fields.
+func mapEntryParts(fields map[string]interface{}) (string, string, []Field) {
+	msg, _ := fieldValue(fields["msg"]).(string)
+	id, _ := fields["id"].(string)
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		if k != "msg" && k != "id" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	typed := make([]Field, len(keys))
+	for i, k := range keys {
+		typed[i] = Any(k, fields[k])
+	}
+	return msg, id, typed
+}
+
+// appendCustomEntry runs the Formatter over a pooled buffer.
+func (_log *Log) appendCustomEntry(dst []byte, level, msg, id string, fields []Field) []byte {
+	dst = _log.formatter.AppendEntry(dst, Entry{
+		Time:    _log.now(),
+		Level:   level,
+		Logger:  _log.name,
+		Message: msg,
+		ID:      id,
+		Fields:  fields,
+	})
+	if len(dst) == 0 || dst[len(dst)-1] != '\n' {
+		dst = append(dst, '\n')
+	}
+	return dst
+}
@@ -0,0 +1,94 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import "strings"
+
+// jsonSchema names the members of JSON entries and the level values.
+type jsonSchema struct {
+	ts, level, msg string
+	levels         [5]string // por levelRank
+	// prefijos precodificados para appendJSONEntry
+	tsPrefix, levelPrefix, msgPrefix string
+}
+
+func newJSONSchema(ts, level, msg string, levels [5]string) *jsonSchema {
+	return &jsonSchema{
+		ts: ts, level: level, msg: msg, levels: levels,
+		tsPrefix:    `{"` + ts + `":`,
+		levelPrefix: `,"` + level + `":"`,
+		msgPrefix:   `","` + msg + `":`,
+	}
+}
+
+var defaultJSONSchema = newJSONSchema("ts", "level", "msg",
+	[5]string{Level.DEBUG, Level.INFO, Level.WARN, Level.ERROR, Level.CRITICAL})
+
+// gkeJSONSchema follows the Cloud Logging structured payload: the GKE logging
+// agent maps severity, timestamp and message onto the LogEntry.
+var gkeJSONSchema = newJSONSchema("timestamp", "severity", "message",
+	[5]string{"DEBUG", "INFO", "WARNING", "ERROR", "CRITICAL"})
+
+// WithGKE makes JSON the initial format, with the member names and severity
+// values Google Cloud Logging expects, so the GKE logging agent parses
+// entries with no extra configuration:
+//
+//	{"timestamp":"2025-11-25T22:21:45.123456789Z","severity":"WARNING","message":"disk almost full","pct":97}
+//
+// Timestamps use TS.RFC3339Nano (see UseUTC); WARN is written as WARNING.
+func WithGKE() Option {
+	return func(conf *config) {
+		conf.jsonSchema = gkeJSONSchema
+	}
+}
+
+// acaciaLevel is the inverse of levelName.
+func (s *jsonSchema) acaciaLevel(name string) string {
+	name = strings.ToUpper(name)
+	for rank, l := range s.levels {
+		if l == name {
+			return string(levelBytesFor(uint8(rank)))
+		}
+	}
+	return name
+}
+
+// levelName returns the value written for level.
+func (s *jsonSchema) levelName(level string) string {
+	if rank := levelRank(level); rank >= 0 {
+		return s.levels[rank]
+	}
+	return level
+}
@@ -0,0 +1,74 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"runtime"
+	"strconv"
+)
+
+// WithGoroutineID records the ID of the logging goroutine: a "goroutine"
+// field in structured formats and a "[g<id>]" tag after the level in text
+// lines. Go has no API for it, so it is parsed from runtime.Stack (about a
+// microsecond per record); meant for debugging concurrency issues.
+func WithGoroutineID() Option {
+	return func(conf *config) {
+		conf.goroutineID = true
+	}
+}
+
+// goroutineID parses the current goroutine's ID from the first line of its
+// stack ("goroutine 18 [running]:"). It returns 0 if the format is unknown.
+func goroutineID() int64 {
+	var b [64]byte
+	s := b[:runtime.Stack(b[:], false)]
+	const prefix = "goroutine "
+	if len(s) <= len(prefix) || string(s[:len(prefix)]) != prefix {
+		return 0
+	}
+	s = s[len(prefix):]
+	end := 0
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	id, _ := strconv.ParseInt(string(s[:end]), 10, 64)
+	return id
+}
+
+func appendGoroutineTag(dst []byte) []byte {
+	dst = append(dst, '[', 'g')
+	dst = strconv.AppendInt(dst, goroutineID(), 10)
+	return append(dst, ']', ' ')
+}
@@ -0,0 +1,124 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// gzipExt is the suffix of log files written by WithGzipOutput.
+const gzipExt = ".gz"
+
+// WithGzipOutput writes the log file compressed, as app.log.gz, through a
+// streaming gzip writer, for when disk budget matters more than grep
+// convenience (zcat, zgrep and gzip -dc read it). The stream is flushed at
+// most once per flush interval and on every Sync, Flush and Reopen, so a
+// crash loses only what came after the last flush point: the file then
+// lacks its trailer, which gzip reports after printing the data. Each file
+// is completed on rotation and Close; appending to an existing file adds a
+// new gzip member, which readers concatenate.
+//
+// Rotation sizes count bytes before compression. Backups are named
+// app.log.gz.0 and app-2025-11-18.log.gz. The option is ignored by
+// StartWriter and with AuditMode and WithHashChain, which need to read
+// back the file, and it turns off WithPreallocate, WithPartialLineRecovery
+// and vectored writes.
+func WithGzipOutput() Option {
+	return func(conf *config) {
+		conf.gzipOut = true
+	}
+}
+
+// gzipName devuelve el nombre del archivo vivo con WithGzipOutput.
+func gzipName(cfg *config, logName string) string {
+	if cfg.ack != nil || cfg.chainKey != nil {
+		cfg.gzipOut = false
+	}
+	if !cfg.gzipOut || strings.HasSuffix(logName, gzipExt) {
+		return logName
+	}
+	return logName + gzipExt
+}
+
+// splitLogExt splits a log file name into its stem and extension, keeping a
+// ".gz" suffix with the extension before it: app.log.gz → app, .log.gz.
+func splitLogExt(name string) (stem, ext string) {
+	inner := strings.TrimSuffix(name, gzipExt)
+	ext = filepath.Ext(inner) + name[len(inner):]
+	return name[:len(name)-len(ext)], ext
+}
+
+// startGzip starts the gzip stream of a newly set log file, completing the
+// one of the previous file, which is still open.
+func (_log *Log) startGzip(f *os.File) {
+	if err := _log.closeGzip(); err != nil {
+		_log.reportError("completing compressed file: %v", err)
+	}
+	if f == nil {
+		return
+	}
+	if _log.gz == nil {
+		_log.gz = gzip.NewWriter(f)
+	} else {
+		_log.gz.Reset(f)
+	}
+	_log.gzFile = f
+}
+
+// closeGzip escribe el final del stream del archivo actual.
+func (_log *Log) closeGzip() error {
+	if _log.gzFile == nil {
+		return nil
+	}
+	_log.gzFile, _log.gzDirty = nil, false
+	return _log.gz.Close()
+}
+
+// gzipFlushPoint pasa al archivo lo comprimido hasta ahora, como mucho una
+// vez por intervalo de flush salvo con force. Writer goroutine only.
+func (_log *Log) gzipFlushPoint(force bool) {
+	if !_log.gzDirty || !force && time.Since(_log.gzFlushed) < _log.flushEvery {
+		return
+	}
+	if err := _log.gz.Flush(); err != nil {
+		_log.keepWriteErr(err)
+	}
+	_log.gzDirty = false
+	_log.gzFlushed = time.Now()
+}
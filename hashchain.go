@@ -0,0 +1,209 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+)
+
+// ErrChainBroken is returned by VerifyHashChain when a record was modified,
+// removed, reordered or inserted.
+var ErrChainBroken = errors.New("acacia: hash chain broken")
+
+const (
+	chainTextMark = " hmac="
+	chainJSONMark = `,"hmac":"`
+	chainHexLen   = sha256.Size * 2
+)
+
+// hashChain seals records with HMAC-SHA256(key, previous MAC || record).
+// Writer goroutine only.
+type hashChain struct {
+	mac  hash.Hash
+	prev []byte
+	sum  []byte
+	hex  [chainHexLen]byte
+}
+
+// WithHashChain makes the log tamper-evident: every record ends with an
+// HMAC-SHA256 over the previous record's MAC and its own content, as a
+// trailing ` hmac=<hex>` in text lines or an "hmac" member in JSON objects.
+// Editing, deleting, inserting or reordering records breaks the chain, which
+// VerifyHashChain detects. The chain continues across rotations and restarts
+// (it resumes from the last MAC found in the file).
+func WithHashChain(key []byte) Option {
+	return func(conf *config) {
+		if len(key) > 0 {
+			conf.chainKey = append([]byte(nil), key...)
+		}
+	}
+}
+
+func newHashChain(key []byte) *hashChain {
+	return &hashChain{mac: hmac.New(sha256.New, key)}
+}
+
+// resume continues the chain from the last sealed record of f, if any.
+func (c *hashChain) resume(f *os.File) {
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 {
+		return
+	}
+	const tail = 4096
+	off := info.Size() - tail
+	if off < 0 {
+		off = 0
+	}
+	buf := make([]byte, info.Size()-off)
+	rf, err := os.Open(f.Name())
+	if err != nil {
+		return
+	}
+	defer rf.Close()
+	if _, err := rf.ReadAt(buf, off); err != nil && err != io.EOF {
+		return
+	}
+	lines := bytes.Split(bytes.TrimRight(buf, "\n"), []byte{'\n'})
+	for i := len(lines) - 1; i >= 0; i-- {
+		if _, mac, ok := splitChainMark(lines[i]); ok {
+			c.prev = mac
+			return
+		}
+	}
+}
+
+// seal appends the MAC of the record that starts at start in buf.
+func (c *hashChain) seal(buf []byte, start int) []byte {
+	end := len(buf)
+	if end > start && buf[end-1] == '\n' {
+		end--
+	}
+	if end == start {
+		return buf
+	}
+	record := buf[start:end]
+	c.mac.Reset()
+	c.mac.Write(c.prev)
+	c.mac.Write(record)
+	c.sum = c.mac.Sum(c.sum[:0])
+	c.prev = append(c.prev[:0], c.sum...)
+	hex.Encode(c.hex[:], c.sum)
+
+	if record[0] == '{' && record[len(record)-1] == '}' {
+		buf = append(buf[:end-1], chainJSONMark...)
+		buf = append(buf, c.hex[:]...)
+		return append(buf, '"', '}', '\n')
+	}
+	buf = append(buf[:end], chainTextMark...)
+	buf = append(buf, c.hex[:]...)
+	return append(buf, '\n')
+}
+
+// splitChainMark returns the original record and the MAC of a sealed line.
+func splitChainMark(line []byte) ([]byte, []byte, bool) {
+	n := len(line)
+	if n >= len(chainJSONMark)+chainHexLen+2 && line[n-1] == '}' && line[n-2] == '"' {
+		at := n - 2 - chainHexLen - len(chainJSONMark)
+		if string(line[at:at+len(chainJSONMark)]) == chainJSONMark {
+			mac, err := hex.DecodeString(string(line[n-2-chainHexLen : n-2]))
+			if err == nil {
+				record := append(append([]byte(nil), line[:at]...), '}')
+				return record, mac, true
+			}
+		}
+	}
+	if n >= len(chainTextMark)+chainHexLen {
+		at := n - chainHexLen - len(chainTextMark)
+		if string(line[at:at+len(chainTextMark)]) == chainTextMark {
+			mac, err := hex.DecodeString(string(line[n-chainHexLen:]))
+			if err == nil {
+				return line[:at], mac, true
+			}
+		}
+	}
+	return nil, nil, false
+}
+
+// VerifyHashChain checks the records written with WithHashChain(key) that
+// are read from r. prev is the MAC the chain starts from: nil for the first
+// file a logger ever wrote, or the value returned for the previous file when
+// verifying rotated files oldest first. It returns the MAC of the last
+// record, or an error wrapping ErrChainBroken with the offending line.
+func VerifyHashChain(r io.Reader, key, prev []byte) ([]byte, error) {
+	c := newHashChain(key)
+	c.prev = append([]byte(nil), prev...)
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	var pending []byte // líneas de un registro multilínea aún sin MAC
+	line := 0
+	for sc.Scan() {
+		line++
+		record, mac, ok := splitChainMark(sc.Bytes())
+		if !ok {
+			pending = append(pending, sc.Bytes()...)
+			pending = append(pending, '\n')
+			continue
+		}
+		if len(pending) > 0 {
+			record = append(pending, record...)
+			pending = nil
+		}
+		c.mac.Reset()
+		c.mac.Write(c.prev)
+		c.mac.Write(record)
+		c.sum = c.mac.Sum(c.sum[:0])
+		if !hmac.Equal(c.sum, mac) {
+			return nil, fmt.Errorf("%w at line %d", ErrChainBroken, line)
+		}
+		c.prev = append(c.prev[:0], mac...)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(pending) > 0 {
+		return nil, fmt.Errorf("%w: unsealed data after line %d", ErrChainBroken, line-bytes.Count(pending, []byte{'\n'}))
+	}
+	return c.prev, nil
+}
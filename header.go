@@ -0,0 +1,179 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// HeaderConfig configures the banner written by WithHeader.
+type HeaderConfig struct {
+	App     string // application name, optional
+	Version string // application version, optional
+	// Format builds the banner text; HeaderInfo.String if nil.
+	Format func(HeaderInfo) string
+	// OnRotate writes the banner again at the top of every file that
+	// rotation opens. It has no effect with WithHashChain or Format.Binary.
+	OnRotate bool
+}
+
+// HeaderInfo is what a banner can show.
+type HeaderInfo struct {
+	App, Version  string
+	PID           int
+	Host          string
+	LoggerVersion string    // Acacia version
+	Started       time.Time // when the logger started
+	Rotated       bool      // the banner opens a rotated file
+}
+
+// String returns the default banner:
+//
+//	=== billing 1.4.2 | host web-1 | pid 4242 | Logger Version: 2.2.0 | started 2025-11-18T10:00:00Z ===
+func (h HeaderInfo) String() string {
+	var b strings.Builder
+	b.WriteString("===")
+	if h.App != "" {
+		b.WriteString(" " + h.App)
+	}
+	if h.Version != "" {
+		b.WriteString(" " + h.Version)
+	}
+	if h.App != "" || h.Version != "" {
+		b.WriteString(" |")
+	}
+	if h.Host != "" {
+		b.WriteString(" host " + h.Host + " |")
+	}
+	b.WriteString(" pid " + strconv.Itoa(h.PID))
+	b.WriteString(" | Logger Version: " + h.LoggerVersion)
+	b.WriteString(" | started " + h.Started.Format(time.RFC3339))
+	if h.Rotated {
+		b.WriteString(" | rotated")
+	}
+	b.WriteString(" ===")
+	return b.String()
+}
+
+// WithHeader writes a startup banner as the first record of each run. It is
+// queued with the first record, or by Sync, Flush or Close if nothing was
+// logged (with WithLazyOpen, only with the first record), and encoded in the
+// format in effect then, so StructuredJSON or OutputFormat right after Start
+// apply to it. Text lines get the banner as is; structured formats get an
+// INFO entry with the banner as message and "app", "version", "pid" and
+// "logger_version" fields.
+func WithHeader(c HeaderConfig) Option {
+	return func(conf *config) {
+		conf.header = &c
+	}
+}
+
+// headerInfo fills in the banner data for this logger.
+func (_log *Log) headerInfo(rotated bool) HeaderInfo {
+	h := HeaderInfo{
+		App:           _log.header.App,
+		Version:       _log.header.Version,
+		PID:           os.Getpid(),
+		LoggerVersion: version,
+		Started:       _log.headerStart,
+		Rotated:       rotated,
+	}
+	h.Host, _ = os.Hostname()
+	return h
+}
+
+// headerRecord encodes the banner in the current format into a pooled buffer.
+func (_log *Log) headerRecord(rotated bool) []byte {
+	h := _log.headerInfo(rotated)
+	text := h.String()
+	if _log.header.Format != nil {
+		text = _log.header.Format(h)
+	}
+	if _log.lineFormat() == Format.Text {
+		buf := append(getBufCap(len(text)+1), text...)
+		return append(buf, '\n')
+	}
+	fields := []Field{Int("pid", h.PID), String("logger_version", h.LoggerVersion)}
+	if h.App != "" {
+		fields = append(fields, String("app", h.App))
+	}
+	if h.Version != "" {
+		fields = append(fields, String("version", h.Version))
+	}
+	return _log.encodeFields(Level.INFO, text, "", fields, "")
+}
+
+// enqueueHeader queues the startup banner. The writer encodes it when it
+// gets there, so a format chosen right after Start applies to it too.
+func (_log *Log) enqueueHeader() {
+	_log.enqueue(logEvent{level: uint8(levelRank(Level.INFO)), kind: eventHeader})
+}
+
+// takeHeader enqueues the banner before the first record, once.
+func (_log *Log) takeHeader() {
+	if atomic.CompareAndSwapInt32(&_log.headerPending, 1, 0) {
+		_log.enqueueHeader()
+	}
+}
+
+// writeRotationHeader writes the WithFileHeader text and the banner at the
+// top of a file just opened by rotation and returns their size. Writer
+// goroutine only.
+func (_log *Log) writeRotationHeader() int64 {
+	f := _log.getFile()
+	if f == nil || !_log.fileHooksAllowed() {
+		return 0
+	}
+	rec := getBuf()
+	defer func() { putBuf(rec) }()
+	if _log.fileHeader != nil {
+		rec = appendHookText(rec, _log.fileHeader(f.Name(), _log.rotatedTo))
+	}
+	if _log.header != nil && _log.header.OnRotate {
+		banner := _log.headerRecord(true)
+		rec = append(rec, banner...)
+		putBuf(banner)
+	}
+	if len(rec) == 0 || _log.maxSize > 0 && int64(len(rec)) >= _log.maxSize/2 {
+		// una cabecera que llena el archivo haría rotar sin fin
+		return 0
+	}
+	_log.writeOut(f, rec)
+	return int64(len(rec))
+}
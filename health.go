@@ -0,0 +1,126 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultHealthThreshold is the queue occupancy ratio above which Healthy
+// reports the logger as saturated.
+const DefaultHealthThreshold = 0.9
+
+var (
+	ErrLoggerClosed   = errors.New("acacia: logger is closed")
+	ErrWriterStopped  = errors.New("acacia: writer goroutine is not running")
+	ErrWriterStalled  = errors.New("acacia: writer goroutine is stalled")
+	ErrQueueSaturated = errors.New("acacia: queue is saturated")
+)
+
+// WithHealthThreshold sets the queue occupancy ratio (0 < ratio <= 1) above
+// which Healthy reports ErrQueueSaturated.
+func WithHealthThreshold(ratio float64) Option {
+	return func(conf *config) {
+		if ratio > 0 && ratio <= 1 {
+			conf.healthThreshold = ratio
+		}
+	}
+}
+
+// Healthy reports whether the logger can accept and persist records: the
+// file is writable, the writer goroutine is alive and flushing, and the
+// queue is not saturated. It is cheap enough for readiness probes.
+func (_log *Log) Healthy() error {
+	select {
+	case <-_log.done:
+		return ErrLoggerClosed
+	default:
+	}
+	if _log.shards != nil {
+		return _log.eachShard((*Log).Healthy)
+	}
+
+	if atomic.LoadInt32(&_log.writerAlive) == 0 {
+		return ErrWriterStopped
+	}
+
+	// El ticker del writer hace flush en cada intervalo, así que un último
+	// flush muy antiguo significa que el writer está bloqueado.
+	stallAfter := 20 * _log.flushEvery
+	if stallAfter < 5*time.Second {
+		stallAfter = 5 * time.Second
+	}
+	if last := atomic.LoadInt64(&_log.lastFlush); last > 0 {
+		if since := time.Since(time.Unix(0, last)); since > stallAfter {
+			return fmt.Errorf("%w: no flush for %s", ErrWriterStalled, since)
+		}
+	}
+
+	if ratio := queueRatio(_log.queue.len(), _log.queue.cap()); ratio > _log.healthThreshold {
+		return fmt.Errorf("%w: queue at %.0f%%", ErrQueueSaturated, ratio*100)
+	}
+
+	if _log.sink != nil {
+		return nil
+	}
+	if _log.getFile() == nil {
+		if !_log.lazyOpen {
+			return fmt.Errorf("acacia: no open log file")
+		}
+		// todavía no se escribió nada: basta con que el directorio exista
+		if _, err := os.Stat(_log.path); err != nil {
+			return fmt.Errorf("acacia: log directory not available: %w", err)
+		}
+		return nil
+	}
+	fullPath := filepath.Join(_log.path, _log.name)
+	f, err := os.OpenFile(fullPath, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		return fmt.Errorf("acacia: log file not writable: %w", err)
+	}
+	return f.Close()
+}
+
+func queueRatio(n, c int) float64 {
+	if c == 0 {
+		return 0
+	}
+	return float64(n) / float64(c)
+}
@@ -0,0 +1,90 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// DebugHex logs data at DEBUG level for protocol debugging. Text lines get
+// label, the length and an aligned hex+ASCII dump (as hex.Dump) on indented
+// continuation lines:
+//
+//	... [DEBUG] handshake (5 bytes)
+//		00000000  68 65 6c 6c 6f                                    |hello|
+//
+// Other formats get label as the message with "len" and "base64" fields.
+func (_log *Log) DebugHex(label string, data []byte) {
+	if _log.lineFormat() != Format.Text || _log.transforms != nil {
+		_log.logFields(Level.DEBUG, label, []Field{
+			Int("len", len(data)),
+			String("base64", base64.StdEncoding.EncodeToString(data)),
+		})
+		return
+	}
+	if _log.recent != nil {
+		_log.rememberFields(Level.DEBUG, label, []Field{Int("len", len(data))})
+	}
+	if !_log.shouldLog(Level.DEBUG) {
+		return
+	}
+	if _log.sampler != nil && !_log.sample(Level.DEBUG, hashString(label)) {
+		return
+	}
+	msg := hexMessage(label, data)
+	id := _log.nextID()
+	raw := _log.setFormatBytesFromString(msg, Level.DEBUG, id)
+	_log.enqueueLine(raw, Level.DEBUG, _log.filterEntry(Level.DEBUG, msg, id, nil))
+}
+
+// hexMessage builds "label (n bytes)" followed by the dump, one tab-indented
+// line per 16 bytes.
+func hexMessage(label string, data []byte) string {
+	dump := strings.TrimSuffix(hex.Dump(data), "\n")
+	var b strings.Builder
+	b.Grow(len(label) + 16 + len(dump) + len(dump)/64)
+	b.WriteString(label)
+	b.WriteString(" (")
+	b.WriteString(strconv.Itoa(len(data)))
+	b.WriteString(" bytes)")
+	if dump != "" {
+		b.WriteString("\n\t")
+		b.WriteString(strings.ReplaceAll(dump, "\n", "\n\t"))
+	}
+	return b.String()
+}
@@ -0,0 +1,175 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Package httpdump turns *http.Request and *http.Response values into
+// acacia fields, for logging HTTP traffic with the *Fields methods:
+//
+//	log.InfoFields("outgoing request", httpdump.Request(req, httpdump.Options{MaxBody: 4096})...)
+package httpdump
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+// sensitiveHeaders are always masked.
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// Options select what goes into the fields.
+type Options struct {
+	// MaxBody is how many body bytes to include; 0 leaves bodies out. The body
+	// stays readable: what is read for the dump is put back in front of it.
+	MaxBody int
+	// Redact lists more header names to mask (case-insensitive), on top of
+	// Authorization, Proxy-Authorization, Cookie and Set-Cookie.
+	Redact []string
+	// Mask replaces masked values; acacia.DefaultRedactMask if empty.
+	Mask string
+}
+
+// Request returns the fields of r: method, url, proto, host, one
+// "header.<Name>" field per header (values joined with ", ", sorted by name)
+// and, with MaxBody, "body" plus "body_truncated" when it was longer.
+func Request(r *http.Request, opts Options) []acacia.Field {
+	if r == nil {
+		return nil
+	}
+	fields := make([]acacia.Field, 0, 6+len(r.Header))
+	fields = append(fields,
+		acacia.String("method", r.Method),
+		acacia.String("url", r.URL.String()),
+		acacia.String("proto", r.Proto),
+	)
+	if r.Host != "" {
+		fields = append(fields, acacia.String("host", r.Host))
+	}
+	fields = appendHeaders(fields, r.Header, &opts)
+	if opts.MaxBody > 0 && r.Body != nil && r.Body != http.NoBody {
+		var body []byte
+		body, r.Body = peekBody(r.Body, opts.MaxBody)
+		fields = appendBody(fields, body, opts.MaxBody)
+	}
+	return fields
+}
+
+// Response returns the fields of resp: status, status_code, proto, the
+// method and url of its request when known, headers and body as in Request.
+func Response(resp *http.Response, opts Options) []acacia.Field {
+	if resp == nil {
+		return nil
+	}
+	fields := make([]acacia.Field, 0, 7+len(resp.Header))
+	fields = append(fields,
+		acacia.Int("status_code", resp.StatusCode),
+		acacia.String("status", resp.Status),
+		acacia.String("proto", resp.Proto),
+	)
+	if req := resp.Request; req != nil {
+		fields = append(fields, acacia.String("method", req.Method), acacia.String("url", req.URL.String()))
+	}
+	fields = appendHeaders(fields, resp.Header, &opts)
+	if opts.MaxBody > 0 && resp.Body != nil && resp.Body != http.NoBody {
+		var body []byte
+		body, resp.Body = peekBody(resp.Body, opts.MaxBody)
+		fields = appendBody(fields, body, opts.MaxBody)
+	}
+	return fields
+}
+
+func appendHeaders(fields []acacia.Field, h http.Header, opts *Options) []acacia.Field {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	mask := opts.Mask
+	if mask == "" {
+		mask = acacia.DefaultRedactMask
+	}
+	for _, name := range names {
+		value := strings.Join(h[name], ", ")
+		if sensitive(name, opts.Redact) {
+			value = mask
+		}
+		fields = append(fields, acacia.String("header."+name, value))
+	}
+	return fields
+}
+
+func sensitive(name string, extra []string) bool {
+	for _, s := range sensitiveHeaders {
+		if strings.EqualFold(s, name) {
+			return true
+		}
+	}
+	for _, s := range extra {
+		if strings.EqualFold(s, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// appendBody adds up to max bytes of body; body holds one byte more when it
+// was longer.
+func appendBody(fields []acacia.Field, body []byte, max int) []acacia.Field {
+	if len(body) > max {
+		return append(fields, acacia.String("body", string(body[:max])), acacia.Bool("body_truncated", true))
+	}
+	return append(fields, acacia.String("body", string(body)))
+}
+
+// peekBody reads up to max+1 bytes of rc and returns them with a body that
+// still yields all of rc's content.
+func peekBody(rc io.ReadCloser, max int) ([]byte, io.ReadCloser) {
+	body, err := io.ReadAll(io.LimitReader(rc, int64(max)+1))
+	restored := struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), rc), rc}
+	if err != nil {
+		// el error de lectura se entrega a quien lea el cuerpo después
+		restored.Reader = io.MultiReader(bytes.NewReader(body), errReader{err})
+	}
+	return body, restored
+}
+
+type errReader struct{ err error }
+
+func (e errReader) Read([]byte) (int, error) { return 0, e.err }
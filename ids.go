@@ -0,0 +1,247 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// IDGenerator returns a new unique identifier for a log record.
+// It is called on the logging goroutine and must be safe for concurrent use.
+type IDGenerator func() string
+
+// WithIDGenerator attaches an ID produced by gen to every record: as an
+// "id" field in structured mode and as a "[id]" prefix of the message in
+// plain-text mode. See NewULIDGenerator, NewUUIDv7Generator and
+// NewSnowflakeGenerator for the built-in generators.
+func WithIDGenerator(gen IDGenerator) Option {
+	return func(conf *config) {
+		conf.idGen = gen
+	}
+}
+
+// NewID returns a fresh ID from the configured generator, or "" when none is
+// configured. Useful to tag a whole request or job with the same handle.
+func (_log *Log) NewID() string {
+	return _log.nextID()
+}
+
+func (_log *Log) nextID() string {
+	if _log.idGen == nil {
+		return ""
+	}
+	return _log.idGen()
+}
+
+func appendID(dst []byte, id string) []byte {
+	if id == "" {
+		return dst
+	}
+	dst = append(dst, '[')
+	dst = append(dst, id...)
+	return append(dst, ']', ' ')
+}
+
+// idRand is a math/rand source seeded from crypto/rand; ID generators only
+// need uniqueness, not unpredictability, and this keeps them cheap.
+var (
+	idRandMtx sync.Mutex
+	idRand    = rand.New(rand.NewSource(cryptoSeed()))
+)
+
+func cryptoSeed() int64 {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		return time.Now().UnixNano()
+	}
+	return int64(binary.LittleEndian.Uint64(b[:]))
+}
+
+///////////////////////////////////////
+//             U L I D               //
+///////////////////////////////////////
+
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+type ulidGen struct {
+	mtx     sync.Mutex
+	lastMs  uint64
+	entropy [10]byte
+}
+
+// NewULIDGenerator returns a generator of monotonic ULIDs (26 chars, Crockford
+// base32, lexicographically sortable by time).
+func NewULIDGenerator() IDGenerator {
+	g := &ulidGen{}
+	return g.next
+}
+
+var defaultULID = &ulidGen{}
+
+// NewULID returns a new monotonic ULID.
+func NewULID() string {
+	return defaultULID.next()
+}
+
+func (g *ulidGen) next() string {
+	ms := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+
+	g.mtx.Lock()
+	if ms <= g.lastMs {
+		// mismo milisegundo (o reloj hacia atrás): incrementar entropía
+		ms = g.lastMs
+		for i := len(g.entropy) - 1; i >= 0; i-- {
+			g.entropy[i]++
+			if g.entropy[i] != 0 {
+				break
+			}
+		}
+	} else {
+		g.lastMs = ms
+		idRandMtx.Lock()
+		idRand.Read(g.entropy[:])
+		idRandMtx.Unlock()
+	}
+	var raw [16]byte
+	raw[0] = byte(ms >> 40)
+	raw[1] = byte(ms >> 32)
+	raw[2] = byte(ms >> 24)
+	raw[3] = byte(ms >> 16)
+	raw[4] = byte(ms >> 8)
+	raw[5] = byte(ms)
+	copy(raw[6:], g.entropy[:])
+	g.mtx.Unlock()
+
+	return encodeULID(raw)
+}
+
+// encodeULID encodes 128 bits as 26 Crockford base32 characters.
+func encodeULID(id [16]byte) string {
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+///////////////////////////////////////
+//          U U I D   v 7            //
+///////////////////////////////////////
+
+// NewUUIDv7Generator returns a generator of RFC 9562 version 7 UUIDs.
+func NewUUIDv7Generator() IDGenerator {
+	return newUUIDv7
+}
+
+func newUUIDv7() string {
+	var u [16]byte
+	ms := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+	u[0] = byte(ms >> 40)
+	u[1] = byte(ms >> 32)
+	u[2] = byte(ms >> 24)
+	u[3] = byte(ms >> 16)
+	u[4] = byte(ms >> 8)
+	u[5] = byte(ms)
+	idRandMtx.Lock()
+	idRand.Read(u[6:])
+	idRandMtx.Unlock()
+	u[6] = u[6]&0x0f | 0x70 // version 7
+	u[8] = u[8]&0x3f | 0x80 // variant RFC 9562
+
+	var out [36]byte
+	hex.Encode(out[0:8], u[0:4])
+	out[8] = '-'
+	hex.Encode(out[9:13], u[4:6])
+	out[13] = '-'
+	hex.Encode(out[14:18], u[6:8])
+	out[18] = '-'
+	hex.Encode(out[19:23], u[8:10])
+	out[23] = '-'
+	hex.Encode(out[24:], u[10:])
+	return string(out[:])
+}
+
+///////////////////////////////////////
+//         S N O W F L A K E         //
+///////////////////////////////////////
+
+// snowflakeEpoch is 2020-01-01T00:00:00Z in milliseconds.
+const snowflakeEpoch = 1577836800000
+
+type snowflakeGen struct {
+	mtx    sync.Mutex
+	node   int64
+	lastMs int64
+	seq    int64
+}
+
+// NewSnowflakeGenerator returns a generator of 64-bit snowflake IDs
+// (41 bits of milliseconds, 10 bits of node, 12 bits of sequence) rendered in
+// decimal. node is masked to 10 bits.
+func NewSnowflakeGenerator(node int64) IDGenerator {
+	g := &snowflakeGen{node: node & 0x3ff}
+	return g.next
+}
+
+func (g *snowflakeGen) next() string {
+	g.mtx.Lock()
+	ms := time.Now().UnixNano()/int64(time.Millisecond) - snowflakeEpoch
+	if ms < g.lastMs {
+		ms = g.lastMs
+	}
+	if ms == g.lastMs {
+		g.seq = (g.seq + 1) & 0xfff
+		if g.seq == 0 {
+			// secuencia agotada en este milisegundo: tomar prestado el siguiente
+			ms++
+		}
+	} else {
+		g.seq = 0
+	}
+	g.lastMs = ms
+	id := ms<<22 | g.node<<12 | g.seq
+	g.mtx.Unlock()
+	return strconv.FormatInt(id, 10)
+}
@@ -0,0 +1,76 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"reflect"
+	"sync/atomic"
+	"unsafe"
+)
+
+// keyCacheSize is the number of encoded keys kept; a power of two.
+const keyCacheSize = 1024
+
+// encodedKey holds the pre-encoded forms of a field key.
+type encodedKey struct {
+	key  string // retiene los bytes de la clave: su dirección no se reutiliza
+	json []byte // ,"key":
+	text []byte // " key=" con los caracteres inválidos de logfmt reemplazados
+}
+
+// keyCache interns field keys by the address of their bytes. Keys are nearly
+// always string literals, so the same key keeps coming back with the same
+// address and is found without hashing or escaping its contents again;
+// the entry keeps those bytes alive, so a matching address always means the
+// same key. Dynamic keys only cost a miss. Slots are replaced atomically
+// and entries never change, so lookups take no lock.
+var keyCache [keyCacheSize]unsafe.Pointer // *encodedKey
+
+// internKey returns the encoded forms of key.
+func internKey(key string) *encodedKey {
+	hdr := (*reflect.StringHeader)(unsafe.Pointer(&key))
+	slot := &keyCache[(hdr.Data>>3^uintptr(hdr.Len))&(keyCacheSize-1)]
+	if e := (*encodedKey)(atomic.LoadPointer(slot)); e != nil {
+		ehdr := (*reflect.StringHeader)(unsafe.Pointer(&e.key))
+		if ehdr.Data == hdr.Data && ehdr.Len == hdr.Len {
+			return e
+		}
+	}
+	e := &encodedKey{key: key}
+	e.json = append(appendJSONString(append(make([]byte, 0, len(key)+4), ','), key), ':')
+	e.text = append(appendLogfmtKey(append(make([]byte, 0, len(key)+2), ' '), key), '=')
+	atomic.StorePointer(slot, unsafe.Pointer(e))
+	return e
+}
@@ -0,0 +1,103 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"os"
+	"strings"
+)
+
+// serviceAccountNamespace is mounted in every pod that has a service account.
+const serviceAccountNamespace = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// WithKubernetesMetadata stamps every structured entry with the pod that
+// wrote it, so cluster logs are identifiable without a collector adding
+// metadata. Values are read once from the variables the downward API
+// conventionally sets:
+//
+//	env:
+//	- name: POD_NAME
+//	  valueFrom: {fieldRef: {fieldPath: metadata.name}}
+//	- name: POD_NAMESPACE
+//	  valueFrom: {fieldRef: {fieldPath: metadata.namespace}}
+//	- name: NODE_NAME
+//	  valueFrom: {fieldRef: {fieldPath: spec.nodeName}}
+//
+// and written as "k8s.pod", "k8s.namespace" and "k8s.node". NAMESPACE is
+// accepted as well; inside a pod, the namespace falls back to the service
+// account mount and the pod name to the hostname. POD_IP and CONTAINER_NAME,
+// when set, add "k8s.pod_ip" and "k8s.container". Missing values are
+// omitted, so outside a cluster the option adds nothing.
+func WithKubernetesMetadata() Option {
+	return withMetaFields(kubernetesFields())
+}
+
+// kubernetesFields resuelve los metadatos del pod.
+func kubernetesFields() []Field {
+	inPod := os.Getenv("KUBERNETES_SERVICE_HOST") != ""
+	pod := os.Getenv("POD_NAME")
+	if pod == "" && inPod {
+		pod, _ = os.Hostname()
+	}
+	namespace := firstEnv("POD_NAMESPACE", "NAMESPACE")
+	if namespace == "" && inPod {
+		if b, err := os.ReadFile(serviceAccountNamespace); err == nil {
+			namespace = strings.TrimSpace(string(b))
+		}
+	}
+	var fields []Field
+	for _, kv := range [...][2]string{
+		{"k8s.pod", pod},
+		{"k8s.namespace", namespace},
+		{"k8s.node", os.Getenv("NODE_NAME")},
+		{"k8s.pod_ip", os.Getenv("POD_IP")},
+		{"k8s.container", os.Getenv("CONTAINER_NAME")},
+	} {
+		if kv[1] != "" {
+			fields = append(fields, String(kv[0], kv[1]))
+		}
+	}
+	return fields
+}
+
+// firstEnv devuelve el primer valor no vacío entre las variables dadas.
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
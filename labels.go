@@ -0,0 +1,103 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+// levelTags holds the "[LEVEL] " tag text lines start with, by levelRank.
+type levelTags [5][]byte
+
+var defaultLevelTags = newLevelTags(nil, false)
+
+// WithLevelLabels replaces the level names written in text lines, e.g.
+// {Level.WARN: "WARNING"} or localized labels. Keys are acacia levels;
+// unknown keys and empty labels are ignored. Levels are still set and
+// filtered by their acacia names, and structured formats keep their own
+// level values (see WithGKE). Reader and Tail only recognize the default
+// labels.
+func WithLevelLabels(labels map[string]string) Option {
+	return func(conf *config) {
+		for level, label := range labels {
+			if levelRank(level) >= 0 && label != "" {
+				if conf.levelLabels == nil {
+					conf.levelLabels = make(map[string]string, len(labels))
+				}
+				conf.levelLabels[level] = label
+			}
+		}
+	}
+}
+
+// WithLevelPadding pads the level tag of text lines to the width of the
+// longest label, so messages start on the same column:
+//
+//	2025-11-25 22:21:45 [INFO]     service started
+//	2025-11-25 22:21:45 [CRITICAL] disk full
+func WithLevelPadding() Option {
+	return func(conf *config) {
+		conf.levelPadding = true
+	}
+}
+
+// newLevelTags arma las etiquetas con los nombres dados y, si pad, con
+// espacios hasta el ancho de la más larga.
+func newLevelTags(labels map[string]string, pad bool) *levelTags {
+	var tags levelTags
+	width := 0
+	for rank := range tags {
+		label := string(levelBytesFor(uint8(rank)))
+		if l, ok := labels[label]; ok {
+			label = l
+		}
+		tags[rank] = append(append([]byte{'['}, label...), ']', ' ')
+		if len(tags[rank]) > width {
+			width = len(tags[rank])
+		}
+	}
+	if pad {
+		for rank := range tags {
+			for len(tags[rank]) < width {
+				tags[rank] = append(tags[rank], ' ')
+			}
+		}
+	}
+	return &tags
+}
+
+// tag returns the tag of rank; unknown ranks get the INFO one.
+func (t *levelTags) tag(rank uint8) []byte {
+	if int(rank) >= len(t) {
+		return t[1]
+	}
+	return t[rank]
+}
@@ -0,0 +1,156 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	layoutLiteral uint8 = iota
+	layoutTS
+	layoutLevel
+	layoutLogger
+	layoutMsg
+	layoutID
+	layoutFields
+)
+
+var layoutNames = map[string]uint8{
+	"ts":     layoutTS,
+	"level":  layoutLevel,
+	"logger": layoutLogger,
+	"msg":    layoutMsg,
+	"id":     layoutID,
+	"fields": layoutFields,
+}
+
+type layoutStep struct {
+	kind uint8
+	text string // literal, o layout de tiempo para {ts}
+}
+
+// layout is a Formatter compiled from a layout string.
+type layout struct {
+	steps []layoutStep
+}
+
+// ParseLayout compiles a layout string into a Formatter. Placeholders are
+// {ts}, {level}, {logger}, {msg}, {id} and {fields} (key=value pairs); any
+// other text is copied as is. {ts} uses TS.Special unless a time layout is
+// given after a colon, as in {ts:15:04:05.000}. Trailing spaces left by empty
+// placeholders are trimmed.
+//
+//	acacia.ParseLayout("{ts} [{level}] {logger} {msg} {fields}")
+func ParseLayout(s string) (Formatter, error) {
+	l := &layout{}
+	for s != "" {
+		open := strings.IndexByte(s, '{')
+		if open < 0 {
+			l.steps = append(l.steps, layoutStep{kind: layoutLiteral, text: s})
+			break
+		}
+		if open > 0 {
+			l.steps = append(l.steps, layoutStep{kind: layoutLiteral, text: s[:open]})
+		}
+		end := strings.IndexByte(s[open:], '}')
+		if end < 0 {
+			return nil, fmt.Errorf("acacia: unclosed placeholder in layout at %q", s[open:])
+		}
+		name, arg := s[open+1:open+end], ""
+		if i := strings.IndexByte(name, ':'); i >= 0 {
+			name, arg = name[:i], name[i+1:]
+		}
+		kind, ok := layoutNames[name]
+		if !ok {
+			return nil, fmt.Errorf("acacia: unknown layout placeholder {%s}", name)
+		}
+		if arg != "" && kind != layoutTS {
+			return nil, fmt.Errorf("acacia: layout placeholder {%s} takes no argument", name)
+		}
+		if kind == layoutTS && arg == "" {
+			arg = TS.Special
+		}
+		l.steps = append(l.steps, layoutStep{kind: kind, text: arg})
+		s = s[open+end+1:]
+	}
+	return l, nil
+}
+
+// WithLayout formats records with ParseLayout(layout). An invalid layout is
+// reported on stderr and leaves the default format in place.
+func WithLayout(s string) Option {
+	f, err := ParseLayout(s)
+	if err != nil {
+		reportInternalError("invalid layout %q: %v", s, err)
+		return func(*config) {}
+	}
+	return WithFormatter(f)
+}
+
+// AppendEntry implements Formatter.
+func (l *layout) AppendEntry(dst []byte, e Entry) []byte {
+	start := len(dst)
+	for _, step := range l.steps {
+		switch step.kind {
+		case layoutLiteral:
+			dst = append(dst, step.text...)
+		case layoutTS:
+			dst = e.Time.AppendFormat(dst, step.text)
+		case layoutLevel:
+			dst = append(dst, e.Level...)
+		case layoutLogger:
+			dst = append(dst, e.Logger...)
+		case layoutMsg:
+			dst = append(dst, e.Message...)
+		case layoutID:
+			dst = append(dst, e.ID...)
+		case layoutFields:
+			for i := range e.Fields {
+				if i > 0 {
+					dst = append(dst, ' ')
+				}
+				dst = appendLogfmtKey(dst, e.Fields[i].Key)
+				dst = append(dst, '=')
+				dst = appendFieldText(dst, &e.Fields[i])
+			}
+		}
+	}
+	for len(dst) > start && dst[len(dst)-1] == ' ' {
+		dst = dst[:len(dst)-1]
+	}
+	return dst
+}
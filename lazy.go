@@ -0,0 +1,105 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// LazyValue is a value computed only when an entry that uses it is actually
+// encoded, see Lazy.
+type LazyValue struct {
+	fn   func() interface{}
+	once sync.Once
+	v    interface{}
+}
+
+// Lazy defers fn until the entry passes the level check and is formatted, so
+// expensive dumps cost nothing when their level is disabled:
+//
+//	log.Debug("state: %v", acacia.Lazy(func() interface{} { return cache.Dump() }))
+//	log.DebugFields("state", acacia.Any("cache", acacia.Lazy(cache.Snapshot)))
+//
+// It works as a format argument, as a map or struct value and in Any fields.
+// fn runs at most once, on the logging goroutine.
+func Lazy(fn func() interface{}) *LazyValue {
+	return &LazyValue{fn: fn}
+}
+
+// Value runs fn, the first time only, and returns its result.
+func (l *LazyValue) Value() interface{} {
+	l.once.Do(func() {
+		if l.fn != nil {
+			l.v = l.fn()
+		}
+	})
+	return l.v
+}
+
+// Format formats the computed value with the same verb and flags.
+func (l *LazyValue) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, formatDirective(f, verb), l.Value())
+}
+
+// String returns the computed value as %v would print it.
+func (l *LazyValue) String() string {
+	return fmt.Sprint(l.Value())
+}
+
+// MarshalJSON encodes the computed value.
+func (l *LazyValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.Value())
+}
+
+// formatDirective rebuilds the directive (%-08.3f) that fmt passed to Format.
+func formatDirective(f fmt.State, verb rune) string {
+	b := []byte{'%'}
+	for _, flag := range "+-# 0" {
+		if f.Flag(int(flag)) {
+			b = append(b, byte(flag))
+		}
+	}
+	if w, ok := f.Width(); ok {
+		b = strconv.AppendInt(b, int64(w), 10)
+	}
+	if p, ok := f.Precision(); ok {
+		b = append(b, '.')
+		b = strconv.AppendInt(b, int64(p), 10)
+	}
+	return string(append(b, string(verb)...))
+}
@@ -0,0 +1,265 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// levelFile receives a copy of every line at or above min, in its own file
+// with its own size rotation.
+type levelFile struct {
+	name        string
+	min         uint8
+	maxSize     int64
+	maxRotation int
+	file        *os.File // solo writer
+	size        int64    // solo writer
+	buf         []byte   // líneas pendientes, protegido por Log.mtx
+	out         []byte   // lote en escritura, solo writer
+	used        uint64   // último flush que lo escribió (archivos enrutados)
+	brk         *breaker // WithCircuitBreaker, solo writer
+	errs        *errSink
+}
+
+// WithLevelFile also writes every record at level or above to name, in the
+// logger's directory, so errors stay in a small, scannable file next to the
+// full log:
+//
+//	acacia.WithLevelFile("app.error.log", acacia.Level.ERROR, 10, 5)
+//
+// The file rotates by size on its own, as Rotation(sizeMB, backup) does for
+// the main file (sizeMB <= 0: no rotation); daily rotation only applies to
+// the main file. The file is created with its first record and is fsynced
+// by Sync and Close. The option can be repeated
+// for several files.
+func WithLevelFile(name, level string, sizeMB, backup int) Option {
+	return func(conf *config) {
+		rank := levelRank(level)
+		if name == "" || rank < 0 {
+			return
+		}
+		if backup < 1 {
+			backup = 1
+		}
+		lf := &levelFile{name: name, min: uint8(rank), maxRotation: backup}
+		if sizeMB > 0 {
+			lf.maxSize = int64(sizeMB) * 1024 * 1024
+		}
+		conf.levelFiles = append(conf.levelFiles, lf)
+	}
+}
+
+// copyToLevelFiles queues the line that starts at start in the batch buffer
+// for every level file that wants level. Binary records are copied with an
+// absolute timestamp, since the record before them may not be copied.
+// Writer goroutine only, with mtx held.
+func (_log *Log) copyToLevelFiles(start int, level uint8, binary bool) {
+	for _, lf := range _log.levelFiles {
+		if level < lf.min {
+			continue
+		}
+		if binary {
+			lf.buf = appendBinaryAbsolute(lf.buf, _log.buffer[start:], _log.binPrev)
+		} else {
+			lf.buf = append(lf.buf, _log.buffer[start:]...)
+		}
+	}
+}
+
+// open opens (or creates) the file in dir and resets the size accounting.
+func (lf *levelFile) open(dir string) error {
+	f, err := os.OpenFile(filepath.Join(dir, lf.name), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	lf.size = 0
+	if info, err := f.Stat(); err == nil {
+		lf.size = info.Size()
+	}
+	lf.file = f
+	return nil
+}
+
+// writeSideFile writes p to a level or routed file, through its circuit
+// breaker with WithCircuitBreaker. Writer goroutine only.
+func (_log *Log) writeSideFile(lf *levelFile, p []byte) {
+	if _log.breakerFailures == 0 {
+		if err := lf.write(_log.path, p, _log.recordEnd); err != nil {
+			_log.reportError("%v", err)
+		}
+		return
+	}
+	if lf.brk == nil {
+		lf.brk = newBreaker("level file "+lf.name, _log.breakerFailures, _log.breakerCooldown, _log.errs)
+	}
+	if !lf.brk.allow() {
+		lf.brk.skip(p)
+		return
+	}
+	err := lf.write(_log.path, p, _log.recordEnd)
+	if err != nil && lf.file != nil {
+		// reabrir en el próximo intento, por si el descriptor quedó inservible
+		_ = lf.file.Close()
+		lf.file = nil
+	}
+	lf.brk.done(err, p)
+}
+
+// write appends p, rotating before any line that would exceed maxSize.
+// Writer goroutine only.
+func (lf *levelFile) write(dir string, p []byte, recordEnd func([]byte) int) error {
+	if lf.file == nil {
+		if err := lf.open(dir); err != nil {
+			return fmt.Errorf("opening level file %s: %w", lf.name, err)
+		}
+	}
+	if lf.maxSize <= 0 {
+		n, err := lf.file.Write(p)
+		lf.size += int64(n)
+		if err != nil {
+			return fmt.Errorf("writing level file %s: %w", lf.name, err)
+		}
+		return nil
+	}
+	for len(p) > 0 {
+		line := p[:recordEnd(p)]
+		if lf.size > 0 && lf.size+int64(len(line)) > lf.maxSize {
+			if err := lf.rotate(); err != nil {
+				return fmt.Errorf("rotating level file %s: %w", lf.name, err)
+			}
+		}
+		n, err := lf.file.Write(line)
+		lf.size += int64(n)
+		if err != nil {
+			return fmt.Errorf("writing level file %s: %w", lf.name, err)
+		}
+		p = p[len(line):]
+	}
+	return nil
+}
+
+// rotate shifts name.N -> name.(N+1), moves the current file to name.0 and
+// opens a fresh one, like logRotate does for the main file.
+func (lf *levelFile) rotate() error {
+	base := lf.file.Name()
+	for i := lf.maxRotation - 1; i >= 0; i-- {
+		src := fmt.Sprintf("%s.%d", base, i)
+		if _, err := os.Stat(src); err == nil {
+			if err := os.Rename(src, fmt.Sprintf("%s.%d", base, i+1)); err != nil {
+				lf.errs.report("rotating file %s: %v", src, err)
+			}
+		}
+	}
+	if err := os.Rename(base, base+".0"); err != nil {
+		lf.errs.report("renaming level file for size rotation: %v", err)
+	}
+	old := lf.file
+	lf.file = nil
+	if err := old.Close(); err != nil {
+		lf.errs.report("closing level file after size rotation: %v", err)
+	}
+	return lf.open(filepath.Dir(base))
+}
+
+// flushLevelFiles writes the lines queued for every level file. Writer
+// goroutine only; the buffers were swapped under mtx by flush.
+func (_log *Log) flushLevelFiles() {
+	for _, lf := range _log.levelFiles {
+		if len(lf.out) > 0 {
+			_log.writeSideFile(lf, lf.out)
+			lf.out = lf.out[:0]
+		}
+	}
+}
+
+// syncLevelFiles fsyncs every open level and routed file and returns the
+// first error.
+func (_log *Log) syncLevelFiles() error {
+	var first error
+	for _, lf := range _log.sideFiles() {
+		if lf.file == nil {
+			continue
+		}
+		if err := syncFile(lf.file); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// reopenLevelFiles closes every level and routed file so the next write
+// opens its path again (see Reopen). Writer goroutine only.
+func (_log *Log) reopenLevelFiles() {
+	for _, lf := range _log.sideFiles() {
+		if lf.file == nil {
+			continue
+		}
+		if err := syncFile(lf.file); err != nil {
+			_log.reportError("fsync level file before reopen: %v", err)
+		}
+		if err := lf.file.Close(); err != nil {
+			_log.reportError("closing level file on reopen: %v", err)
+		}
+		lf.file = nil
+	}
+}
+
+// closeLevelFiles syncs and closes every level and routed file and returns
+// the first error. Called by Close once the writer has stopped.
+func (_log *Log) closeLevelFiles() error {
+	var first error
+	for _, lf := range _log.sideFiles() {
+		if lf.file == nil {
+			continue
+		}
+		if err := syncFile(lf.file); err != nil {
+			_log.reportError("final level file sync error: %v", err)
+			if first == nil {
+				first = err
+			}
+		}
+		if err := lf.file.Close(); err != nil {
+			_log.reportError("final level file close error: %v", err)
+			if first == nil {
+				first = err
+			}
+		}
+		lf.file = nil
+	}
+	return first
+}
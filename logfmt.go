@@ -0,0 +1,160 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// formatLogfmt renders a structured entry as
+// `ts=… level=info msg="…" key=value … id=…`. Keys other than msg and id are
+// sorted, like encoding/json does for maps.
+func (_log *Log) formatLogfmt(level string, fields map[string]interface{}) []byte {
+	buf := getBuf()
+	buf = _log.appendLogfmtHead(buf, level)
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		if k != "msg" && k != "id" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	if msg, ok := fields["msg"]; ok {
+		buf = _log.appendLogfmtPair(buf, "msg", msg)
+	}
+	for _, k := range keys {
+		buf = _log.appendLogfmtPair(buf, k, fields[k])
+	}
+	if id, ok := fields["id"]; ok {
+		buf = _log.appendLogfmtPair(buf, "id", id)
+	}
+	return append(buf, '\n')
+}
+
+// appendLogfmtEntry is the typed-fields counterpart of formatLogfmt.
+func (_log *Log) appendLogfmtEntry(dst []byte, level, msg, id string, fields []Field) []byte {
+	dst = _log.appendLogfmtHead(dst, level)
+	dst = append(dst, " msg="...)
+	dst = appendTextValue(dst, msg)
+	for i := range fields {
+		dst = append(dst, internKey(fields[i].Key).text...)
+		dst = appendFieldText(dst, &fields[i])
+	}
+	if id != "" {
+		dst = append(dst, " id="...)
+		dst = appendTextValue(dst, id)
+	}
+	return append(dst, '\n')
+}
+
+func (_log *Log) appendLogfmtHead(dst []byte, level string) []byte {
+	if _log.noTimestamp {
+		dst = append(dst, "level="...)
+		return _log.appendLogfmtSeverity(appendLowerLevel(dst, level), level)
+	}
+	dst = append(dst, "ts="...)
+	if cachedTS := _log.cachedTime.Load(); cachedTS != nil && !_log.preciseTS {
+		dst = appendTextBytes(dst, cachedTS.([]byte))
+	} else {
+		dst = appendTextValue(dst, _log.now().Format(_log.timestampLayout()))
+	}
+	dst = append(dst, " level="...)
+	return _log.appendLogfmtSeverity(appendLowerLevel(dst, level), level)
+}
+
+func appendLowerLevel(dst []byte, level string) []byte {
+	for i := 0; i < len(level); i++ {
+		dst = append(dst, level[i]|0x20) // niveles ASCII en minúsculas
+	}
+	return dst
+}
+
+func (_log *Log) appendLogfmtPair(dst []byte, key string, value interface{}) []byte {
+	dst = append(dst, internKey(key).text...)
+	switch v := fieldValue(value).(type) {
+	case nil:
+		return append(dst, "null"...)
+	case string:
+		return appendTextValue(dst, v)
+	case bool:
+		return strconv.AppendBool(dst, v)
+	}
+	if out, ok := appendInt(dst, value); ok {
+		return out
+	}
+	if out, ok := appendValue(dst, value); ok {
+		return out
+	}
+	// valores compuestos: su JSON como string
+	if encoded, err := _log.encoder.Marshal(fieldValue(value)); err == nil {
+		return appendTextValue(dst, string(encoded))
+	}
+	return appendTextValue(dst, fmt.Sprint(value))
+}
+
+// appendTextBytes is appendTextValue for a byte slice, without converting it
+// to a string unless it needs quoting.
+func appendTextBytes(dst []byte, b []byte) []byte {
+	for _, c := range b {
+		if c <= ' ' || c == '"' || c == '=' || c == 0x7f {
+			return strconv.AppendQuote(dst, string(b))
+		}
+	}
+	if len(b) == 0 {
+		return append(dst, `""`...)
+	}
+	return append(dst, b...)
+}
+
+// appendLogfmtKey writes key replacing the characters logfmt keys cannot hold.
+func appendLogfmtKey(dst []byte, key string) []byte {
+	if key == "" {
+		return append(dst, '_')
+	}
+	if strings.IndexFunc(key, func(r rune) bool { return r <= ' ' || r == '=' || r == '"' || r == 0x7f }) == -1 {
+		return append(dst, key...)
+	}
+	for _, r := range key {
+		if r <= ' ' || r == '=' || r == '"' || r == 0x7f {
+			r = '_'
+		}
+		dst = append(dst, string(r)...)
+	}
+	return dst
+}
@@ -0,0 +1,131 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import "sync"
+
+// Manager starts and owns the loggers of one configuration, e.g. an access
+// log, an application log and an audit log, so a program wires them up and
+// shuts them down in one place:
+//
+//	m, err := acacia.StartManager("acacia.json")
+//	if err != nil {
+//		return err
+//	}
+//	defer m.Close()
+//	m.Get("access").Info("GET /health 200")
+//
+// Like StartFromConfig, it registers them under their names (so Get,
+// ReloadConfig and WatchConfig work with them) and sets the Default one.
+type Manager struct {
+	names     []string // orden de la configuración
+	logs      map[string]*Log
+	closeOnce sync.Once
+	closeErr  error
+}
+
+// StartManager reads the JSON file at path, as StartFromConfig does, and
+// starts a Manager for its loggers.
+func StartManager(path string) (*Manager, error) {
+	conf, err := readConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return startManager(conf)
+}
+
+// NewManager starts a Manager for the loggers in conf. Loggers without a
+// Name are named after their File.
+func NewManager(conf Config) (*Manager, error) {
+	conf.Loggers = append([]LoggerConfig(nil), conf.Loggers...)
+	for i := range conf.Loggers {
+		if conf.Loggers[i].Name == "" {
+			conf.Loggers[i].Name = conf.Loggers[i].File
+		}
+	}
+	return startManager(&conf)
+}
+
+func startManager(conf *Config) (*Manager, error) {
+	logs, def, err := conf.start()
+	if err != nil {
+		return nil, err
+	}
+	m := &Manager{logs: logs}
+	for _, lc := range conf.Loggers {
+		m.names = append(m.names, lc.Name)
+		Register(lc.Name, logs[lc.Name])
+	}
+	if def != nil {
+		SetDefault(def)
+	}
+	managers.Lock()
+	managers.live[m] = struct{}{}
+	managers.Unlock()
+	return m, nil
+}
+
+// Get returns the logger named name, or nil.
+func (m *Manager) Get(name string) *Log {
+	return m.logs[name]
+}
+
+// Names returns the names of the loggers, in configuration order.
+func (m *Manager) Names() []string {
+	return append([]string(nil), m.names...)
+}
+
+// Close closes every logger, in configuration order, and removes those still
+// registered under their names from the registry and as Default. It returns
+// the first error; every logger is closed regardless. Calls after the first
+// return the same error.
+func (m *Manager) Close() error {
+	m.closeOnce.Do(func() {
+		managers.Lock()
+		delete(managers.live, m)
+		managers.Unlock()
+		for _, name := range m.names {
+			lg := m.logs[name]
+			if err := lg.Close(); err != nil && m.closeErr == nil {
+				m.closeErr = err
+			}
+			unregister(name, lg)
+			if Default() == lg {
+				SetDefault(nil)
+			}
+		}
+	})
+	return m.closeErr
+}
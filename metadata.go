@@ -0,0 +1,75 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"os"
+	"sync/atomic"
+)
+
+// WithProcessMetadata stamps every structured entry with "host" and "pid",
+// plus "service" and "version" when they are not empty. Values are resolved
+// once here, so there is no per-entry cost beyond encoding them. Keys set by
+// the entry itself take precedence in map entries. Text lines are unchanged.
+func WithProcessMetadata(service, version string) Option {
+	var fields []Field
+	if host, err := os.Hostname(); err == nil {
+		fields = append(fields, String("host", host))
+	}
+	fields = append(fields, Int("pid", os.Getpid()))
+	if service != "" {
+		fields = append(fields, String("service", service))
+	}
+	if version != "" {
+		fields = append(fields, String("version", version))
+	}
+	return withMetaFields(fields)
+}
+
+// withMetaFields adds fixed fields to every structured entry.
+func withMetaFields(fields []Field) Option {
+	return func(conf *config) {
+		conf.metaFields = append(conf.metaFields, fields...)
+	}
+}
+
+// takeOnceFields returns the first-entry-only fields to the first caller and
+// nil afterwards.
+func (_log *Log) takeOnceFields() []Field {
+	if atomic.LoadInt32(&_log.oncePending) == 0 || !atomic.CompareAndSwapInt32(&_log.oncePending, 1, 0) {
+		return nil
+	}
+	return _log.onceFields
+}
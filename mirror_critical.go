@@ -0,0 +1,113 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// criticalMirror copies CRITICAL records straight to stderr, bypassing the
+// queue and the file, so fatal conditions stay visible even when the normal
+// pipeline is what broke. Output is rate limited and duplicate-suppressed.
+type criticalMirror struct {
+	mtx        sync.Mutex
+	out        io.Writer
+	name       string
+	limit      int
+	window     time.Duration
+	windowFrom time.Time
+	sent       int
+	seen       map[string]time.Time
+	suppressed int
+	redact     *redactor
+}
+
+// WithCriticalStderr mirrors every CRITICAL record to stderr, writing at most
+// limit lines per window. A message identical to one already mirrored within
+// the window is suppressed; the number of suppressed lines is reported on the
+// next mirrored one.
+func WithCriticalStderr(limit int, window time.Duration) Option {
+	return func(conf *config) {
+		if limit > 0 && window > 0 {
+			conf.critMirror = &criticalMirror{
+				out:    os.Stderr,
+				limit:  limit,
+				window: window,
+				seen:   make(map[string]time.Time),
+			}
+		}
+	}
+}
+
+func (m *criticalMirror) mirror(msg string) {
+	now := time.Now()
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	if now.Sub(m.windowFrom) >= m.window {
+		m.windowFrom = now
+		m.sent = 0
+		for k, at := range m.seen {
+			if now.Sub(at) >= m.window {
+				delete(m.seen, k)
+			}
+		}
+	}
+	if at, dup := m.seen[msg]; dup && now.Sub(at) < m.window {
+		m.suppressed++
+		return
+	}
+	if m.sent >= m.limit {
+		m.suppressed++
+		return
+	}
+	m.sent++
+	m.seen[msg] = now
+
+	line := fmt.Sprintf("%s [CRITICAL] %s: %s", now.Format(time.RFC3339), m.name, msg)
+	if m.suppressed > 0 {
+		line += fmt.Sprintf(" (%d similar suppressed)", m.suppressed)
+		m.suppressed = 0
+	}
+	if m.redact != nil && len(m.redact.scrubbers) > 0 {
+		line = string(m.redact.redactLine([]byte(line), 0))
+	}
+	_, _ = io.WriteString(m.out, line+"\n")
+}
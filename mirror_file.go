@@ -0,0 +1,163 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"bytes"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	mirrorQueueLen = 64              // lotes pendientes antes de descartar
+	mirrorRetry    = 5 * time.Second // espera antes de reabrir un espejo caído
+)
+
+// mirrorFile duplicates every flushed batch into a second file from its own
+// goroutine, so a slow or unavailable mirror (e.g. an NFS mount) never blocks
+// the writer: batches that do not fit in its queue, or that fail, are dropped
+// and counted.
+type mirrorFile struct {
+	path    string
+	batches chan []byte
+	done    chan struct{}
+	dropped uint64
+	file    *os.File // solo goroutine del espejo
+	brk     *breaker // solo goroutine del espejo
+	errs    *errSink
+}
+
+// WithMirrorFile duplicates all output to path (e.g. local disk plus an NFS
+// mount). The mirror is written from its own goroutine and is never rotated;
+// when it is slow or unavailable its lines are dropped (see MirrorDropped),
+// the failure is reported once and the file is reopened a few seconds later
+// (see WithCircuitBreaker), while the primary keeps going. A relative path is
+// relative to the working directory.
+func WithMirrorFile(path string) Option {
+	return func(conf *config) {
+		if path != "" {
+			conf.mirror = &mirrorFile{
+				path:    path,
+				batches: make(chan []byte, mirrorQueueLen),
+				done:    make(chan struct{}),
+			}
+		}
+	}
+}
+
+// MirrorDropped returns the number of lines that did not reach the mirror
+// file, or 0 without WithMirrorFile.
+func (_log *Log) MirrorDropped() uint64 {
+	if _log.mirror == nil {
+		return 0
+	}
+	return atomic.LoadUint64(&_log.mirror.dropped)
+}
+
+// send hands a copy of p to the mirror goroutine without waiting.
+func (m *mirrorFile) send(p []byte) {
+	if len(p) == 0 {
+		return
+	}
+	select {
+	case m.batches <- append([]byte(nil), p...):
+	default:
+		m.drop(p)
+	}
+}
+
+func (m *mirrorFile) drop(p []byte) {
+	atomic.AddUint64(&m.dropped, uint64(bytes.Count(p, []byte{'\n'})))
+}
+
+func (m *mirrorFile) run() {
+	defer close(m.done)
+	for b := range m.batches {
+		m.write(b)
+	}
+	if m.file != nil {
+		if err := syncFile(m.file); err != nil {
+			m.errs.report("final mirror file sync error: %v", err)
+		}
+		if err := m.file.Close(); err != nil {
+			m.errs.report("final mirror file close error: %v", err)
+		}
+	}
+}
+
+// write appends b to the mirror, or drops it while the breaker is open.
+func (m *mirrorFile) write(b []byte) {
+	if !m.brk.allow() {
+		m.drop(b)
+		m.brk.skip(b)
+		return
+	}
+	err := m.writeFile(b)
+	if err != nil {
+		m.drop(b)
+	}
+	m.brk.done(err, b)
+}
+
+// writeFile opens the mirror if needed and writes b; after an error the file
+// is closed so the next attempt reopens it.
+func (m *mirrorFile) writeFile(b []byte) error {
+	if m.file == nil {
+		f, err := os.OpenFile(m.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return err
+		}
+		m.file = f
+	}
+	if _, err := m.file.Write(b); err != nil {
+		_ = m.file.Close()
+		m.file = nil
+		return err
+	}
+	return nil
+}
+
+// closeMirror stops the mirror goroutine, waiting at most barrierTimeout for
+// pending batches so a hung mirror cannot hang Close.
+func (_log *Log) closeMirror() {
+	m := _log.mirror
+	close(m.batches)
+	select {
+	case <-m.done:
+	case <-time.After(barrierTimeout):
+		_log.reportError("mirror file %s did not finish in time", m.path)
+	}
+}
@@ -0,0 +1,68 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import "os"
+
+// WithPreallocate reserves disk blocks for the whole rotation size (see
+// Rotation) when a log file is created or rotated, reducing fragmentation and
+// metadata updates on high-throughput appenders. The apparent file size is
+// not changed, so readers and size accounting are unaffected. It is a no-op
+// on platforms without fallocate and when size rotation is disabled.
+func WithPreallocate() Option {
+	return func(conf *config) {
+		conf.preallocate = true
+	}
+}
+
+func (_log *Log) preallocateFile(f *os.File) {
+	if !_log.preallocate || f == nil || _log.maxSize <= 0 {
+		return
+	}
+	if err := preallocate(f, _log.maxSize); err != nil {
+		_log.reportError("preallocating %s: %v", f.Name(), err)
+	}
+}
+
+// releasePreallocation returns the unused reserved blocks of a file that is
+// about to be rotated away.
+func (_log *Log) releasePreallocation(f *os.File, size int64) {
+	if !_log.preallocate || f == nil {
+		return
+	}
+	if err := f.Truncate(size); err != nil {
+		_log.reportError("releasing preallocation of %s: %v", f.Name(), err)
+	}
+}
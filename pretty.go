@@ -0,0 +1,195 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+)
+
+const (
+	prettyTimeLayout = "15:04:05.000"
+	prettyMsgWidth   = 40
+	ansiReset        = "\x1b[0m"
+	ansiDim          = "\x1b[2m"
+)
+
+// Badges de nivel con el mismo ancho para alinear columnas.
+var (
+	prettyBadges      = [...]string{"DEBUG", "INFO ", "WARN ", "ERROR", "CRIT "}
+	prettyBadgeColors = [...]string{"\x1b[90m", "\x1b[32m", "\x1b[33m", "\x1b[31m", "\x1b[1;37;41m"}
+)
+
+// formatPretty renders a structured entry for humans:
+//
+//	15:04:05.000 INFO  message                                  key=value …
+//
+// Multi-line messages and values continue on indented lines. Colors are only
+// used on terminals, honoring NO_COLOR and FORCE_COLOR (see OutputFormat).
+func (_log *Log) formatPretty(level string, fields map[string]interface{}) []byte {
+	msg, _ := fieldValue(fields["msg"]).(string)
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		if k != "msg" && k != "id" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	if _, ok := fields["id"]; ok {
+		keys = append(keys, "id")
+	}
+
+	buf := _log.appendPrettyHead(getBuf(), level, msg, len(keys) > 0)
+	var multi []string
+	for _, k := range keys {
+		var value []byte
+		value = _log.appendLogfmtPair(value, k, fields[k])
+		// appendLogfmtPair escribe " key=value"; los valores multilínea van aparte
+		if s, ok := fieldValue(fields[k]).(string); ok && strings.IndexByte(s, '\n') >= 0 {
+			multi = append(multi, k, s)
+			continue
+		}
+		buf = _log.appendPrettyField(buf, value)
+	}
+	return appendPrettyMultiline(buf, multi)
+}
+
+// appendPrettyEntry is the typed-fields counterpart of formatPretty.
+func (_log *Log) appendPrettyEntry(dst []byte, level, msg, id string, fields []Field) []byte {
+	dst = _log.appendPrettyHead(dst, level, msg, len(fields) > 0 || id != "")
+	var multi []string
+	for i := range fields {
+		f := &fields[i]
+		if f.kind == fieldString && strings.IndexByte(f.str, '\n') >= 0 {
+			multi = append(multi, f.Key, f.str)
+			continue
+		}
+		pair := append(make([]byte, 0, 32), ' ')
+		pair = appendLogfmtKey(pair, f.Key)
+		pair = append(pair, '=')
+		pair = appendFieldText(pair, f)
+		dst = _log.appendPrettyField(dst, pair)
+	}
+	if id != "" {
+		dst = _log.appendPrettyField(dst, append([]byte(" id="), id...))
+	}
+	return appendPrettyMultiline(dst, multi)
+}
+
+// appendPrettyHead writes the time, the level badge and the first line of
+// msg, padded when fields follow. Remaining message lines are indented.
+func (_log *Log) appendPrettyHead(dst []byte, level, msg string, padded bool) []byte {
+	color := _log.color
+	if !_log.noTimestamp || _log.staticPrefix != "" {
+		if color {
+			dst = append(dst, ansiDim...)
+		}
+		if _log.noTimestamp {
+			dst = append(dst, _log.staticPrefix...)
+		} else {
+			dst = _log.now().AppendFormat(dst, prettyTimeLayout)
+		}
+		if color {
+			dst = append(dst, ansiReset...)
+		}
+		dst = append(dst, ' ')
+	}
+
+	rank := levelRank(level)
+	if rank < 0 {
+		rank = 1
+	}
+	if color {
+		dst = append(dst, prettyBadgeColors[rank]...)
+	}
+	dst = append(dst, prettyBadges[rank]...)
+	if color {
+		dst = append(dst, ansiReset...)
+	}
+	dst = append(dst, ' ')
+
+	first, rest := msg, ""
+	if i := strings.IndexByte(msg, '\n'); i >= 0 {
+		first, rest = msg[:i], msg[i+1:]
+	}
+	dst = append(dst, first...)
+	if padded {
+		for n := len([]rune(first)); n < prettyMsgWidth; n++ {
+			dst = append(dst, ' ')
+		}
+	}
+	if rest != "" {
+		dst = appendPrettyIndented(append(dst, '\n'), rest)
+		if padded {
+			dst = append(dst, "\n             "...)
+		}
+	}
+	return dst
+}
+
+// appendPrettyField appends a " key=value" pair, dimming the key on terminals.
+func (_log *Log) appendPrettyField(dst []byte, pair []byte) []byte {
+	if !_log.color {
+		return append(dst, pair...)
+	}
+	eq := bytes.IndexByte(pair, '=')
+	dst = append(dst, ansiDim...)
+	dst = append(dst, pair[:eq+1]...)
+	dst = append(dst, ansiReset...)
+	return append(dst, pair[eq+1:]...)
+}
+
+// appendPrettyMultiline ends the line and writes multi-line values below it.
+func appendPrettyMultiline(dst []byte, multi []string) []byte {
+	for i := 0; i < len(multi); i += 2 {
+		dst = append(dst, "\n             "...)
+		dst = append(dst, multi[i]...)
+		dst = append(dst, ":\n"...)
+		dst = appendPrettyIndented(dst, strings.TrimRight(multi[i+1], "\n"))
+	}
+	return append(dst, '\n')
+}
+
+func appendPrettyIndented(dst []byte, s string) []byte {
+	for i, line := range strings.Split(s, "\n") {
+		if i > 0 {
+			dst = append(dst, '\n')
+		}
+		dst = append(dst, "             │ "...)
+		dst = append(dst, line...)
+	}
+	return dst
+}
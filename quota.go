@@ -0,0 +1,136 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// byteQuota caps the bytes a logger writes per window. Shared by the files of
+// WithWriters, so every field is atomic.
+type byteQuota struct {
+	max     int64
+	window  int64 // nanosegundos
+	resetAt int64
+	used    int64
+	over    int32 // 1: solo ERROR y CRITICAL hasta resetAt
+	notice  int32 // 1: falta escribir el aviso
+	dropped uint64
+}
+
+// WithByteQuota caps what the logger writes to maxBytes per window, so a
+// single noisy logger cannot fill a shared disk. Once the cap is reached,
+// only ERROR and CRITICAL records are written until the window ends, and a
+// single WARN line says so; structured formats carry "quota_bytes" and
+// "window_ms" fields. Routed records count, copies in level files do not.
+// OverQuota counts what was left out. maxBytes or window <= 0 is ignored.
+func WithByteQuota(maxBytes int64, window time.Duration) Option {
+	return func(conf *config) {
+		if maxBytes > 0 && window > 0 {
+			conf.quota = &byteQuota{max: maxBytes, window: int64(window)}
+		}
+	}
+}
+
+// OverQuota returns the number of records discarded by WithByteQuota.
+func (_log *Log) OverQuota() uint64 {
+	if _log.quota == nil {
+		return 0
+	}
+	return atomic.LoadUint64(&_log.quota.dropped)
+}
+
+// admit reports whether a record of the given level may be written, opening
+// a new window when the current one has ended.
+func (q *byteQuota) admit(level uint8) bool {
+	now := time.Now().UnixNano()
+	if resetAt := atomic.LoadInt64(&q.resetAt); now >= resetAt &&
+		atomic.CompareAndSwapInt64(&q.resetAt, resetAt, now+q.window) {
+		atomic.StoreInt64(&q.used, 0)
+		atomic.StoreInt32(&q.over, 0)
+	}
+	if level >= uint8(levelRank(Level.ERROR)) || atomic.LoadInt32(&q.over) == 0 {
+		return true
+	}
+	atomic.AddUint64(&q.dropped, 1)
+	return false
+}
+
+// charge counts n written bytes; the writer that crosses the cap owes the
+// notice.
+func (q *byteQuota) charge(n int) {
+	if atomic.AddInt64(&q.used, int64(n)) >= q.max && atomic.CompareAndSwapInt32(&q.over, 0, 1) {
+		atomic.StoreInt32(&q.notice, 1)
+	}
+}
+
+// appendQuotaNotice writes the pending over-quota line, if any. Writer
+// goroutine only, with mtx held, between lines.
+func (_log *Log) appendQuotaNotice(ts []byte) {
+	q := _log.quota
+	if !atomic.CompareAndSwapInt32(&q.notice, 1, 0) {
+		return
+	}
+	window := time.Duration(q.window)
+	level := uint8(levelRank(Level.WARN))
+	start := len(_log.buffer)
+	msg := "byte quota of " + strconv.FormatInt(q.max, 10) + " bytes per " + window.String() +
+		" exceeded, logging only ERROR and CRITICAL until the window ends"
+	if _log.lineFormat() == Format.Binary || _log.structured() {
+		raw := _log.encodeFields(Level.WARN, msg, "", []Field{Int64("quota_bytes", q.max), Int64("window_ms", window.Milliseconds())}, "")
+		if _log.lineFormat() == Format.Binary {
+			_log.buffer = _log.appendBinaryRecord(_log.buffer, _log.now().UnixNano(), raw)
+			putBuf(raw)
+			if _log.levelFiles != nil {
+				_log.copyToLevelFiles(start, level, true)
+			}
+			return
+		}
+		_log.buffer = append(_log.buffer, raw...)
+		putBuf(raw)
+	} else {
+		ev := logEvent{msgStr: msg, level: level, kind: eventString}
+		if _log.preciseTS {
+			ev.ts = _log.clock().UnixNano()
+		}
+		_log.buffer = appendEvent(_log.buffer, ts, _log.timestampLayout(), atomic.LoadInt32(&_log.utc) == 1, _log.levelTags, &ev)
+	}
+	_log.sealLine(start)
+	if _log.levelFiles != nil {
+		_log.copyToLevelFiles(start, level, false)
+	}
+}
@@ -0,0 +1,356 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Query selects the entries returned by a Reader. Zero values match
+// everything.
+type Query struct {
+	Level  string    // minimum level
+	Since  time.Time // inclusive
+	Until  time.Time // exclusive
+	Layout string    // text timestamp layout; by default every TS layout is tried
+}
+
+// Reader parses acacia log files, text or JSON lines (both may be mixed in
+// one file), into entries. Text lines keep their fields inside Message;
+// continuation lines (multi-line messages, stack traces) are appended to the
+// entry they follow. Entries whose timestamp cannot be parsed only match
+// queries without a time range.
+type Reader struct {
+	r       *bufio.Reader
+	q       Query
+	min     int
+	layouts []string
+	logger  string
+	pending *Entry
+	eof     bool
+}
+
+// NewReader returns a Reader over r returning the entries matching q. An
+// invalid q.Level is ignored.
+func NewReader(r io.Reader, q Query) *Reader {
+	rd := &Reader{r: bufio.NewReader(r), q: q, min: levelRank(strings.ToUpper(q.Level))}
+	if q.Layout != "" {
+		rd.layouts = append(rd.layouts, q.Layout)
+	}
+	rd.layouts = append(rd.layouts,
+		TS.Special, TS.RFC3339Nano, TS.RFC3339, TS.ANSIC, TS.UnixDate, TS.RubyDate,
+		TS.RFC822, TS.RFC822Z, TS.RFC850, TS.RFC1123, TS.RFC1123Z,
+		TS.StampNano, TS.StampMicro, TS.StampMilli, TS.Stamp, TS.Kitchen)
+	return rd
+}
+
+// ReadFile returns the entries of the log file at path matching q. Their
+// Logger is the file name.
+func ReadFile(path string, q Query) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	rd := NewReader(f, q)
+	rd.logger = filepath.Base(path)
+	var entries []Entry
+	for {
+		e, err := rd.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return entries, err
+		}
+		entries = append(entries, e)
+	}
+}
+
+// Next returns the next matching entry, or io.EOF after the last one.
+func (r *Reader) Next() (Entry, error) {
+	for {
+		e, err := r.next()
+		if err != nil {
+			return Entry{}, err
+		}
+		if r.match(&e) {
+			return e, nil
+		}
+	}
+}
+
+// next returns the next entry, whatever its level or time.
+func (r *Reader) next() (Entry, error) {
+	for !r.eof {
+		line, err := r.r.ReadBytes('\n')
+		if err != nil {
+			if err != io.EOF {
+				return Entry{}, err
+			}
+			r.eof = true
+		}
+		if e, ok := r.push(bytes.TrimRight(line, "\r\n")); ok {
+			return e, nil
+		}
+	}
+	if e, ok := r.flush(); ok {
+		return e, nil
+	}
+	return Entry{}, io.EOF
+}
+
+// push handles one line and returns the entry it completes, if any: an
+// entry is complete once the next one starts.
+func (r *Reader) push(line []byte) (Entry, bool) {
+	e, ok := r.parseLine(line)
+	if !ok {
+		// línea de continuación del registro anterior
+		if r.pending != nil && len(line) > 0 {
+			r.pending.Message += "\n" + string(line)
+		}
+		return Entry{}, false
+	}
+	prev := r.pending
+	r.pending = &e
+	if prev == nil {
+		return Entry{}, false
+	}
+	return *prev, true
+}
+
+// flush returns the entry still waiting for its next line, if any.
+func (r *Reader) flush() (Entry, bool) {
+	prev := r.pending
+	if prev == nil {
+		return Entry{}, false
+	}
+	r.pending = nil
+	return *prev, true
+}
+
+func (r *Reader) match(e *Entry) bool {
+	if r.min > 0 && levelRank(e.Level) < r.min {
+		return false
+	}
+	if r.q.Since.IsZero() && r.q.Until.IsZero() {
+		return true
+	}
+	if e.Time.IsZero() {
+		return false
+	}
+	return (r.q.Since.IsZero() || !e.Time.Before(r.q.Since)) &&
+		(r.q.Until.IsZero() || e.Time.Before(r.q.Until))
+}
+
+// parseLine parses the first line of an entry, or returns false for any
+// other line.
+func (r *Reader) parseLine(line []byte) (Entry, bool) {
+	if len(line) > 0 && line[0] == '{' {
+		return r.parseJSON(line)
+	}
+	return r.parseText(string(line))
+}
+
+// parseText parses "<ts> [LEVEL] [id] [g12] message".
+func (r *Reader) parseText(line string) (Entry, bool) {
+	for off := 0; ; {
+		i := strings.Index(line[off:], " [")
+		if i < 0 {
+			return Entry{}, false
+		}
+		i += off
+		j := strings.IndexByte(line[i+2:], ']')
+		if j < 0 {
+			return Entry{}, false
+		}
+		level := line[i+2 : i+2+j]
+		rest := line[i+3+j:]
+		if levelRank(level) < 0 || (rest != "" && rest[0] != ' ') {
+			off = i + 1
+			continue
+		}
+		e := Entry{Time: r.parseTime(line[:i]), Level: level, Logger: r.logger}
+		rest = strings.TrimPrefix(rest, " ")
+		if tag, after, ok := cutTag(rest); ok && !isGoroutineTag(tag) {
+			e.ID, rest = tag, after
+		}
+		if tag, after, ok := cutTag(rest); ok && isGoroutineTag(tag) {
+			n, _ := strconv.ParseInt(tag[1:], 10, 64)
+			e.Fields = append(e.Fields, Int64("goroutine", n))
+			rest = after
+		}
+		e.Message = rest
+		return e, true
+	}
+}
+
+// cutTag splits "[tag] rest".
+func cutTag(s string) (string, string, bool) {
+	if !strings.HasPrefix(s, "[") {
+		return "", s, false
+	}
+	i := strings.Index(s, "] ")
+	if i < 0 {
+		return "", s, false
+	}
+	return s[1:i], s[i+2:], true
+}
+
+func isGoroutineTag(tag string) bool {
+	if len(tag) < 2 || tag[0] != 'g' {
+		return false
+	}
+	_, err := strconv.ParseUint(tag[1:], 10, 64)
+	return err == nil
+}
+
+func (r *Reader) parseTime(s string) time.Time {
+	if s == "" {
+		return time.Time{}
+	}
+	for i, layout := range r.layouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			if i > 0 {
+				// el último formato reconocido se prueba primero
+				r.layouts[0], r.layouts[i] = r.layouts[i], r.layouts[0]
+			}
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// parseJSON parses a JSON line keeping the order of its members; ts, level,
+// msg and id (timestamp, severity and message with WithGKE) fill the Entry
+// and the rest become fields.
+func (r *Reader) parseJSON(line []byte) (Entry, bool) {
+	dec := json.NewDecoder(bytes.NewReader(line))
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return Entry{}, false
+	}
+	type member struct {
+		key string
+		v   interface{}
+	}
+	var members []member
+	s := defaultJSONSchema
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return Entry{}, false
+		}
+		key, _ := tok.(string)
+		var v interface{}
+		if err := dec.Decode(&v); err != nil {
+			return Entry{}, false
+		}
+		if key == gkeJSONSchema.level {
+			s = gkeJSONSchema
+		}
+		members = append(members, member{key, v})
+	}
+	e := Entry{Logger: r.logger}
+	for _, m := range members {
+		switch str, _ := m.v.(string); m.key {
+		case s.ts:
+			e.Time = r.parseJSONTime(m.v)
+		case s.level:
+			e.Level = s.acaciaLevel(str)
+		case s.msg:
+			e.Message = str
+		case "id":
+			e.ID = str
+		default:
+			e.Fields = append(e.Fields, jsonField(m.key, m.v))
+		}
+	}
+	if levelRank(e.Level) < 0 {
+		return Entry{}, false
+	}
+	return e, true
+}
+
+// parseJSONTime accepts formatted timestamps and WithEpochTimestamps
+// integers, whose unit is guessed from their magnitude.
+func (r *Reader) parseJSONTime(v interface{}) time.Time {
+	switch v := v.(type) {
+	case string:
+		return r.parseTime(v)
+	case json.Number:
+		n, err := v.Int64()
+		if err != nil {
+			return time.Time{}
+		}
+		switch {
+		case n < 1e11:
+			return time.Unix(n, 0)
+		case n < 1e14:
+			return time.Unix(0, n*int64(time.Millisecond))
+		case n < 1e17:
+			return time.Unix(0, n*int64(time.Microsecond))
+		default:
+			return time.Unix(0, n)
+		}
+	}
+	return time.Time{}
+}
+
+// jsonField turns a decoded JSON value into a typed field where possible.
+func jsonField(key string, v interface{}) Field {
+	switch v := v.(type) {
+	case string:
+		return String(key, v)
+	case bool:
+		return Bool(key, v)
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return Int64(key, n)
+		}
+		if f, err := v.Float64(); err == nil {
+			return Float64(key, f)
+		}
+	}
+	return Any(key, v)
+}
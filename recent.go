@@ -0,0 +1,139 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"io"
+	"sync"
+)
+
+// recentRing keeps the last entries logged, at any level, for DumpRecent.
+type recentRing struct {
+	mtx     sync.Mutex
+	entries []Entry
+	next    int // próxima posición a escribir
+	full    bool
+}
+
+// WithRecentBuffer keeps the last n entries in memory, including those below
+// the logger level, so crash handlers and admin endpoints can show recent
+// context with DumpRecent. Entries are formatted on the logging goroutine
+// even when they are not written, so DEBUG calls stop being free.
+func WithRecentBuffer(n int) Option {
+	return func(conf *config) {
+		if n > 0 {
+			conf.recent = &recentRing{entries: make([]Entry, n)}
+		}
+	}
+}
+
+func (r *recentRing) add(e Entry) {
+	r.mtx.Lock()
+	r.entries[r.next] = e
+	r.next++
+	if r.next == len(r.entries) {
+		r.next, r.full = 0, true
+	}
+	r.mtx.Unlock()
+}
+
+// snapshot returns the kept entries, oldest first.
+func (r *recentRing) snapshot() []Entry {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if !r.full {
+		return append([]Entry(nil), r.entries[:r.next]...)
+	}
+	out := make([]Entry, 0, len(r.entries))
+	out = append(out, r.entries[r.next:]...)
+	return append(out, r.entries[:r.next]...)
+}
+
+// remember keeps a Debug/Info/... call in the recent buffer, with the same
+// key redaction the written entry gets.
+func (_log *Log) remember(level string, data interface{}, args []interface{}) {
+	if len(args) == 0 && _log.redact != nil {
+		if m, ok := data.(map[string]interface{}); ok {
+			data, _ = _log.redact.redactMap(m)
+		} else if m, ok := structFields(_log.encoder, data); ok {
+			data, _ = _log.redact.redactMap(m)
+		}
+	}
+	_log.recent.add(Entry{Time: _log.now(), Level: level, Logger: _log.name, Message: _log.formatMessageString(data, args...)})
+}
+
+// rememberFields keeps a *Fields call in the recent buffer. The fields are
+// copied: callers may reuse their slice.
+func (_log *Log) rememberFields(level, msg string, fields []Field) {
+	if _log.redact != nil {
+		fields = _log.redact.redactFields(fields)
+	}
+	if len(fields) > 0 {
+		fields = append([]Field(nil), fields...)
+	}
+	_log.recent.add(Entry{Time: _log.now(), Level: level, Logger: _log.name, Message: msg, Fields: fields})
+}
+
+// DumpRecent writes the entries kept by WithRecentBuffer to w, oldest first,
+// as text lines ("<ts> [LEVEL] msg key=value…") whatever the output format.
+// Redaction patterns apply as on the log file. Without the option it writes
+// nothing.
+func (_log *Log) DumpRecent(w io.Writer) error {
+	if _log.recent == nil {
+		return nil
+	}
+	layout := _log.timestampLayout()
+	var buf []byte
+	for _, e := range _log.recent.snapshot() {
+		start := len(buf)
+		buf = e.Time.AppendFormat(buf, layout)
+		buf = append(buf, " ["...)
+		buf = append(buf, e.Level...)
+		buf = append(buf, "] "...)
+		buf = append(buf, e.Message...)
+		for i := range e.Fields {
+			buf = append(buf, ' ')
+			buf = appendLogfmtKey(buf, e.Fields[i].Key)
+			buf = append(buf, '=')
+			buf = appendFieldText(buf, &e.Fields[i])
+		}
+		buf = append(buf, '\n')
+		if _log.redact != nil {
+			buf = _log.redact.redactLine(buf, start)
+		}
+	}
+	_, err := w.Write(buf)
+	return err
+}
@@ -0,0 +1,81 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"io"
+	"os"
+)
+
+// PartialLineMarker is appended by WithPartialLineRecovery to a final line
+// that was cut short, before its newline.
+const PartialLineMarker = " [acacia: partial line recovered]"
+
+// WithPartialLineRecovery checks, when an existing log file is opened, that
+// it ends with a newline. If a crash left the last line half written, the
+// line is completed with PartialLineMarker and a newline, so the first new
+// record starts on a line of its own and line-based parsers see one marked,
+// broken line instead of two records glued together. It is meant for the
+// line formats; files written with Format.Binary have no lines.
+func WithPartialLineRecovery() Option {
+	return func(conf *config) {
+		conf.recoverPartial = true
+	}
+}
+
+// recoverPartialLine completa la última línea de f si no termina en '\n'.
+func (_log *Log) recoverPartialLine(f *os.File) {
+	if !_log.recoverPartial || f == nil {
+		return
+	}
+	info, err := f.Stat()
+	if err != nil || info.Size() == 0 {
+		return
+	}
+	// f solo admite escritura: leer el último byte con otro descriptor
+	rf, err := os.Open(f.Name())
+	if err != nil {
+		return
+	}
+	var last [1]byte
+	_, err = rf.ReadAt(last[:], info.Size()-1)
+	rf.Close()
+	if err != nil && err != io.EOF || last[0] == '\n' {
+		return
+	}
+	if _, err := f.WriteString(PartialLineMarker + "\n"); err != nil {
+		_log.reportError("recovering partial line of %s: %v", f.Name(), err)
+	}
+}
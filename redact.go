@@ -0,0 +1,206 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"regexp"
+	"strings"
+)
+
+// DefaultRedactMask replaces redacted values when RedactConfig.Mask is empty.
+const DefaultRedactMask = "[REDACTED]"
+
+// RedactConfig lists what must never reach the log. Keys are field names
+// matched case-insensitively (in maps, structs, nested maps and typed
+// fields); their values are replaced with Mask before encoding. Patterns are
+// matched against every complete line, whatever its format, and each match is
+// replaced with Mask.
+type RedactConfig struct {
+	Keys     []string
+	Patterns []*regexp.Regexp
+	Mask     string
+}
+
+type redactor struct {
+	keys      []string
+	scrubbers []scrubber
+	mask      string
+	maskB     []byte
+}
+
+// scrubber masks the matches of re, or only its first capture group when
+// group is set. valid, if not nil, filters out false positives.
+type scrubber struct {
+	re    *regexp.Regexp
+	group bool
+	valid func([]byte) bool
+}
+
+// redactorFor returns the configuration's redactor, creating it if needed.
+func redactorFor(conf *config) *redactor {
+	if conf.redact == nil {
+		conf.redact = &redactor{mask: DefaultRedactMask, maskB: []byte(DefaultRedactMask)}
+	}
+	return conf.redact
+}
+
+// WithRedaction masks sensitive fields and patterns centrally, so call sites
+// cannot forget to:
+//
+//	acacia.WithRedaction(acacia.RedactConfig{
+//		Keys:     []string{"password", "token", "ssn"},
+//		Patterns: []*regexp.Regexp{regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)},
+//	})
+//
+// Patterns run on the writer goroutine over each line, so keep them simple.
+func WithRedaction(c RedactConfig) Option {
+	return func(conf *config) {
+		if len(c.Keys) == 0 && len(c.Patterns) == 0 {
+			return
+		}
+		rd := redactorFor(conf)
+		rd.keys = append(rd.keys, c.Keys...)
+		for _, re := range c.Patterns {
+			rd.scrubbers = append(rd.scrubbers, scrubber{re: re})
+		}
+		if c.Mask != "" {
+			rd.mask, rd.maskB = c.Mask, []byte(c.Mask)
+		}
+	}
+}
+
+func (r *redactor) sensitive(key string) bool {
+	for _, k := range r.keys {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactMap returns fields with sensitive values masked, copying the map
+// (and nested maps) only when something has to change.
+func (r *redactor) redactMap(fields map[string]interface{}) (map[string]interface{}, bool) {
+	var out map[string]interface{}
+	for k, v := range fields {
+		var masked interface{}
+		if r.sensitive(k) {
+			masked = r.mask
+		} else if nested, ok := v.(map[string]interface{}); ok {
+			m, changed := r.redactMap(nested)
+			if !changed {
+				continue
+			}
+			masked = m
+		} else {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]interface{}, len(fields))
+			for k2, v2 := range fields {
+				out[k2] = v2
+			}
+		}
+		out[k] = masked
+	}
+	if out == nil {
+		return fields, false
+	}
+	return out, true
+}
+
+// redactFields is redactMap for typed fields; the caller's slice is never
+// modified.
+func (r *redactor) redactFields(fields []Field) []Field {
+	var out []Field
+	for i := range fields {
+		masked := String(fields[i].Key, r.mask)
+		if !r.sensitive(fields[i].Key) {
+			nested, ok := fields[i].iface.(map[string]interface{})
+			if fields[i].kind != fieldAny || !ok {
+				continue
+			}
+			m, changed := r.redactMap(nested)
+			if !changed {
+				continue
+			}
+			masked = Any(fields[i].Key, m)
+		}
+		if out == nil {
+			out = append(make([]Field, 0, len(fields)), fields...)
+		}
+		out[i] = masked
+	}
+	if out == nil {
+		return fields
+	}
+	return out
+}
+
+// redactLine masks every scrubber match in line, which starts at start in
+// buf, and returns the updated buffer.
+func (r *redactor) redactLine(buf []byte, start int) []byte {
+	for i := range r.scrubbers {
+		sc := &r.scrubbers[i]
+		line := buf[start:]
+		matches := sc.re.FindAllSubmatchIndex(line, -1)
+		if matches == nil {
+			continue
+		}
+		var out []byte
+		last := 0
+		for _, m := range matches {
+			from, to := m[0], m[1]
+			if sc.group && len(m) >= 4 && m[2] >= 0 {
+				from, to = m[2], m[3]
+			}
+			if sc.valid != nil && !sc.valid(line[from:to]) {
+				continue
+			}
+			if out == nil {
+				out = make([]byte, 0, len(line))
+			}
+			out = append(out, line[last:from]...)
+			out = append(out, r.maskB...)
+			last = to
+		}
+		if out == nil {
+			continue
+		}
+		out = append(out, line[last:]...)
+		buf = append(buf[:start], out...)
+	}
+	return buf
+}
@@ -0,0 +1,78 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import "sync"
+
+// registry holds the named loggers shared between packages.
+var registry = struct {
+	sync.RWMutex
+	logs map[string]*Log
+}{logs: make(map[string]*Log)}
+
+// Register makes lg available to Get under name, replacing any logger already
+// registered with that name. A nil lg removes the name. Loggers are usually
+// configured once at startup and registered for the rest of the program:
+//
+//	db, _ := acacia.Start("db.log", "./logs", acacia.Level.INFO)
+//	acacia.Register("db", db)
+//	...
+//	acacia.Get("db").Info("connected")
+func Register(name string, lg *Log) {
+	registry.Lock()
+	if lg == nil {
+		delete(registry.logs, name)
+	} else {
+		registry.logs[name] = lg
+	}
+	registry.Unlock()
+}
+
+// Get returns the logger registered under name, or nil.
+func Get(name string) *Log {
+	registry.RLock()
+	lg := registry.logs[name]
+	registry.RUnlock()
+	return lg
+}
+
+// unregister removes name if it still refers to lg.
+func unregister(name string, lg *Log) {
+	registry.Lock()
+	if registry.logs[name] == lg {
+		delete(registry.logs, name)
+	}
+	registry.Unlock()
+}
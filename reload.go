@@ -0,0 +1,161 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultWatchInterval is how often WatchConfig polls the file by default.
+const defaultWatchInterval = 2 * time.Second
+
+// ReloadConfig applies the settings of the JSON file at path that can change
+// safely at run time to the loggers registered under the same names: level,
+// rotation limits and sampling parameters. Sampling can be retuned or turned
+// off, but only turned on for loggers started with it. Any other change, and
+// new loggers, need a restart. The whole file is validated before anything is
+// applied; each updated logger then writes an INFO line about the reload,
+// whatever its level.
+func ReloadConfig(path string) error {
+	conf, err := readConfig(path)
+	if err != nil {
+		return err
+	}
+	levels := make([]string, len(conf.Loggers))
+	for i, lc := range conf.Loggers {
+		levels[i] = strings.ToUpper(lc.Level)
+		if levels[i] == "" {
+			levels[i] = Level.INFO
+		}
+		if !verifyLevel(levels[i]) {
+			return fmt.Errorf("acacia: logger %q: unknown level %q", lc.Name, lc.Level)
+		}
+	}
+	for i := range conf.Loggers {
+		lc := &conf.Loggers[i]
+		lg := Get(lc.Name)
+		if lg == nil {
+			reportInternalError("config reload: logger %q is not running, restart to add it", lc.Name)
+			continue
+		}
+		lg.applyConfig(lc, levels[i], path)
+	}
+	return nil
+}
+
+// applyConfig updates the live settings of _log from lc.
+func (_log *Log) applyConfig(lc *LoggerConfig, level, path string) {
+	rot := lc.Rotation
+	err := _log.barrier(func() {
+		// en la goroutine writer: sin rotaciones ni escrituras en curso
+		size, backups := int64(rot.SizeMB)*1024*1024, rot.Backups
+		if size < 0 {
+			size = 0
+		}
+		if backups < 1 {
+			backups = 1
+		}
+		if size != _log.maxSize || backups != _log.maxRotation {
+			_log.Rotation(rot.SizeMB, rot.Backups)
+		}
+		_log.mtx.Lock()
+		daily := _log.daily
+		_log.mtx.Unlock()
+		if daily != rot.Daily {
+			_log.DailyRotation(rot.Daily)
+		}
+	}, barrierTimeout)
+	if err != nil {
+		reportInternalError("config reload: rotation of %q: %v", lc.Name, err)
+	}
+
+	switch s := lc.Sampling; {
+	case _log.sampler == nil:
+		if s != nil {
+			reportInternalError("config reload: logger %q was started without sampling, restart to enable it", lc.Name)
+		}
+	case s == nil:
+		_log.sampler.set(0, 0, 0)
+	case s.Window > 0 && s.First > 0 && s.Thereafter >= 0:
+		_log.sampler.set(time.Duration(s.Window), s.First, s.Thereafter)
+	}
+
+	_log.writeFields(Level.INFO, "configuration reloaded", []Field{String("config", path), String("level", level)})
+	// el nivel cambia al final: quien lo observe ya tiene la línea de recarga encolada
+	_log.SetLevel(level)
+}
+
+// WatchConfig polls the file at path every interval (2s if interval <= 0)
+// and calls ReloadConfig when its modification time or size changes. Errors
+// are reported on stderr and the previous settings stay in place. Call the
+// returned function to stop watching.
+func WatchConfig(path string, interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+	last, _ := os.Stat(path)
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+			info, err := os.Stat(path)
+			if err != nil {
+				// el archivo puede faltar un instante mientras se reemplaza
+				continue
+			}
+			if last != nil && info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size() {
+				continue
+			}
+			last = info
+			if err := ReloadConfig(path); err != nil {
+				reportInternalError("config reload: %v", err)
+			}
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
@@ -0,0 +1,208 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"io"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+// retryInterval is how often a failing destination is tried again.
+const retryInterval = time.Second
+
+// retrySpool is the on-disk queue behind WithRetrySpool. Writer goroutine
+// only, except the counter.
+type retrySpool struct {
+	path    string
+	max     int64
+	f       *os.File
+	size    int64 // bytes escritos en el archivo
+	off     int64 // bytes ya entregados al destino
+	lastTry time.Time
+	down    bool // el destino falló y todavía no se recuperó
+	chunk   []byte
+	dropped uint64 // lotes descartados por falta de espacio, atómico
+	errs    *errSink
+}
+
+// WithRetrySpool makes a logger started with StartWriter survive outages of
+// its destination, typically a network writer (a net.Conn, an HTTP shipper)
+// whose peer is down. When a write fails, the batch, from the first byte not
+// accepted, is appended to the spool file at path, and later batches queue
+// behind it. The writer tries the destination again at most once per second
+// (and on every Sync), replays the spool in order and, once it is empty,
+// goes back to writing batches directly.
+//
+// The spool holds at most maxBytes; batches that do not fit are dropped and
+// counted in SpoolDropped. Whatever is left at Close stays on disk and is
+// replayed when a logger is started again with the same path. Loggers
+// started with Start ignore this option.
+func WithRetrySpool(path string, maxBytes int64) Option {
+	return func(conf *config) {
+		if path != "" && maxBytes > 0 {
+			conf.retryPath = path
+			conf.retryMax = maxBytes
+		}
+	}
+}
+
+// SpoolDropped returns how many batches did not fit in the retry spool, or 0
+// without WithRetrySpool.
+func (_log *Log) SpoolDropped() uint64 {
+	if _log.retry == nil {
+		return 0
+	}
+	return atomic.LoadUint64(&_log.retry.dropped)
+}
+
+// openRetrySpool abre el spool y retoma lo que dejó una ejecución anterior.
+func openRetrySpool(path string, max int64) (*retrySpool, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	r := &retrySpool{path: path, max: max, f: f}
+	if info, err := f.Stat(); err == nil {
+		r.size = info.Size()
+	}
+	return r, nil
+}
+
+func (r *retrySpool) pending() bool {
+	return r.off < r.size
+}
+
+// shipBatch writes p to the StartWriter destination, behind whatever the
+// spool holds, and spools what the destination does not take.
+func (_log *Log) shipBatch(p []byte, force bool) {
+	r := _log.retry
+	if r.pending() && (force || time.Since(r.lastTry) >= retryInterval) {
+		_log.resendSpool()
+	}
+	if r.pending() {
+		r.append(p)
+		return
+	}
+	if len(p) == 0 {
+		return
+	}
+	n, err := _log.sink.Write(p)
+	if err != nil {
+		r.failed(err)
+		r.append(p[n:])
+		return
+	}
+	r.down = false
+}
+
+// resendSpool replays the spool into the destination until it is empty or
+// a write fails.
+func (_log *Log) resendSpool() {
+	r := _log.retry
+	for r.pending() {
+		n := r.size - r.off
+		last := n <= spillChunk
+		if !last {
+			n = spillChunk
+		}
+		if cap(r.chunk) < int(n) {
+			r.chunk = make([]byte, n)
+		}
+		buf := r.chunk[:n]
+		read, err := r.f.ReadAt(buf, r.off)
+		if err != nil && err != io.EOF {
+			_log.reportError("reading retry spool %s, dropping %d bytes: %v", r.path, r.size-r.off, err)
+			r.off = r.size
+			break
+		}
+		buf = buf[:read]
+		if !last {
+			buf = buf[:wholeRecords(buf, _log.lineFormat() == Format.Binary)]
+		}
+		written, err := _log.sink.Write(buf)
+		r.off += int64(written)
+		if err != nil {
+			r.failed(err)
+			return
+		}
+	}
+	r.down = false
+	if err := r.f.Truncate(0); err != nil {
+		_log.reportError("truncating retry spool %s: %v", r.path, err)
+	}
+	r.size, r.off = 0, 0
+}
+
+// failed registra el error del destino, avisando solo al comienzo del corte.
+func (r *retrySpool) failed(err error) {
+	r.lastTry = time.Now()
+	if !r.down {
+		r.down = true
+		r.errs.report("writer unavailable, spooling to %s: %v", r.path, err)
+	}
+}
+
+// append encola p en el spool, o lo descarta si no entra.
+func (r *retrySpool) append(p []byte) {
+	if len(p) == 0 {
+		return
+	}
+	if r.size-r.off+int64(len(p)) > r.max {
+		atomic.AddUint64(&r.dropped, 1)
+		if r.errs != nil {
+			r.errs.send(&InternalError{Logger: r.errs.name, Msg: "retry spool " + r.path + " is full, batch dropped"})
+		}
+		return
+	}
+	if _, err := r.f.Write(p); err != nil {
+		r.errs.report("writing retry spool %s: %v", r.path, err)
+		atomic.AddUint64(&r.dropped, 1)
+		return
+	}
+	r.size += int64(len(p))
+}
+
+// close closes the spool and removes it when nothing is left.
+func (r *retrySpool) close() error {
+	err := r.f.Close()
+	if !r.pending() {
+		if rmErr := os.Remove(r.path); rmErr != nil && !os.IsNotExist(rmErr) && err == nil {
+			err = rmErr
+		}
+	}
+	return err
+}
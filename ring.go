@@ -0,0 +1,332 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+type cacheLinePad [64]byte
+
+type ringSlot struct {
+	seq uint64 // pos+1 cuando el evento de pos está publicado, 0 si libre
+	ev  logEvent
+}
+
+// ringChunkSize is the number of slots allocated together. Chunks are taken
+// when producers first reach them and released when the writer drains them,
+// so an idle ring holds about one chunk whatever its capacity.
+const ringChunkSize = 256
+
+type ringChunk [ringChunkSize]ringSlot
+
+// ringChunks recicla bloques entre rings: pop los devuelve vacíos (seq 0).
+var ringChunks = sync.Pool{New: func() interface{} { return new(ringChunk) }}
+
+// eventRing is a bounded lock-free multi-producer / single-consumer queue.
+// Producers claim positions with a CAS on tail and publish each slot by
+// setting its sequence; the writer goroutine is the only consumer, so head is
+// advanced without CAS. head and tail live on separate cache lines to avoid
+// false sharing between producers and the consumer. Slots live in chunks
+// that exist only while they hold events; a producer may enter a chunk only
+// once the writer has left its previous lap, so the writer can release it.
+type eventRing struct {
+	_      cacheLinePad
+	tail   uint64
+	_      cacheLinePad
+	head   uint64
+	_      cacheLinePad
+	seen   uint64 // último head leído por los productores, atrasado respecto a head
+	_      cacheLinePad
+	mask   uint64
+	chunks []unsafe.Pointer  // *ringChunk, nil mientras está vacío
+	spare  [4]unsafe.Pointer // bloques liberados listos para reusar, sin pasar por el pool
+}
+
+// newEventRing returns a ring with room for at least size events
+// (rounded up to a power of two, and to at least one chunk).
+func newEventRing(size int) *eventRing {
+	n := ringChunkSize
+	for n < size {
+		n <<= 1
+	}
+	return &eventRing{
+		mask:   uint64(n - 1),
+		chunks: make([]unsafe.Pointer, n/ringChunkSize),
+	}
+}
+
+// room returns how many positions from pos on producers may claim: up to a
+// full lap past the chunk head is in. It checks against the head producers
+// last saw, and reads head itself (the consumer's cache line) only when that
+// one leaves no room.
+func (r *eventRing) room(pos uint64) uint64 {
+	limit := atomic.LoadUint64(&r.seen)&^(ringChunkSize-1) + r.mask + 1
+	if pos < limit {
+		return limit - pos
+	}
+	head := atomic.LoadUint64(&r.head)
+	if seen := atomic.LoadUint64(&r.seen); head > seen {
+		atomic.CompareAndSwapUint64(&r.seen, seen, head)
+	}
+	limit = head&^(ringChunkSize-1) + r.mask + 1
+	if pos >= limit {
+		return 0
+	}
+	return limit - pos
+}
+
+// claimedSlot returns the slot of a position the caller has claimed,
+// installing its chunk if the writer released it.
+func (r *eventRing) claimedSlot(pos uint64) *ringSlot {
+	i := pos & r.mask
+	p := &r.chunks[i/ringChunkSize]
+	c := atomic.LoadPointer(p)
+	if c == nil {
+		fresh := r.takeChunk()
+		if atomic.CompareAndSwapPointer(p, nil, fresh) {
+			c = fresh
+		} else {
+			// otro productor del mismo bloque lo instaló antes
+			r.releaseChunk(fresh)
+			c = atomic.LoadPointer(p)
+		}
+	}
+	return &(*ringChunk)(c)[i%ringChunkSize]
+}
+
+// takeChunk returns an empty chunk: a spare of this ring if there is one,
+// so a busy ring reuses the chunks it releases, or one from the pool.
+func (r *eventRing) takeChunk() unsafe.Pointer {
+	for i := range r.spare {
+		if atomic.LoadPointer(&r.spare[i]) != nil {
+			if c := atomic.SwapPointer(&r.spare[i], nil); c != nil {
+				return c
+			}
+		}
+	}
+	return unsafe.Pointer(ringChunks.Get().(*ringChunk))
+}
+
+// releaseChunk keeps an empty chunk as a spare, or returns it to the pool.
+func (r *eventRing) releaseChunk(c unsafe.Pointer) {
+	for i := range r.spare {
+		if atomic.CompareAndSwapPointer(&r.spare[i], nil, c) {
+			return
+		}
+	}
+	ringChunks.Put((*ringChunk)(c))
+}
+
+// tryPush enqueues ev and returns its position, or false if the ring is full.
+// Positions are handed out in claim order, which is the order the consumer
+// sees, so events from one goroutine are always consumed in program order.
+func (r *eventRing) tryPush(ev logEvent) (uint64, bool) {
+	for {
+		pos := atomic.LoadUint64(&r.tail)
+		if r.room(pos) == 0 {
+			return 0, false
+		}
+		if atomic.CompareAndSwapUint64(&r.tail, pos, pos+1) {
+			slot := r.claimedSlot(pos)
+			slot.ev = ev
+			atomic.StoreUint64(&slot.seq, pos+1)
+			return pos, true
+		}
+		// otro productor tomó la posición: reintentar
+	}
+}
+
+// tryPushN enqueues as many events of evs as fit, claiming their positions
+// with a single CAS, and returns the position of the first one and how many
+// were taken (0 if the ring is full).
+func (r *eventRing) tryPushN(evs []logEvent) (uint64, int) {
+	for {
+		pos := atomic.LoadUint64(&r.tail)
+		n := r.room(pos)
+		if uint64(len(evs)) < n {
+			n = uint64(len(evs))
+		}
+		if n == 0 {
+			return 0, 0
+		}
+		if !atomic.CompareAndSwapUint64(&r.tail, pos, pos+n) {
+			continue
+		}
+		for i := uint64(0); i < n; i++ {
+			slot := r.claimedSlot(pos + i)
+			slot.ev = evs[i]
+			atomic.StoreUint64(&slot.seq, pos+i+1)
+		}
+		return pos, int(n)
+	}
+}
+
+// pop dequeues the next event. Only the writer goroutine may call it.
+func (r *eventRing) pop() (logEvent, bool) {
+	pos := r.head
+	i := pos & r.mask
+	p := &r.chunks[i/ringChunkSize]
+	c := atomic.LoadPointer(p)
+	if c == nil {
+		return logEvent{}, false
+	}
+	slot := &(*ringChunk)(c)[i%ringChunkSize]
+	if atomic.LoadUint64(&slot.seq) != pos+1 {
+		return logEvent{}, false
+	}
+	ev := slot.ev
+	slot.ev = logEvent{}
+	atomic.StoreUint64(&slot.seq, 0)
+	if i%ringChunkSize == ringChunkSize-1 {
+		// último slot del bloque: nadie más lo usa hasta la próxima vuelta
+		atomic.StorePointer(p, nil)
+		r.releaseChunk(c)
+	}
+	atomic.StoreUint64(&r.head, pos+1)
+	return ev, true
+}
+
+// len returns the number of claimed slots (published or about to be).
+func (r *eventRing) len() int {
+	tail := atomic.LoadUint64(&r.tail)
+	head := atomic.LoadUint64(&r.head)
+	if tail < head {
+		return 0
+	}
+	return int(tail - head)
+}
+
+func (r *eventRing) cap() int {
+	return int(r.mask + 1)
+}
+
+// enqueued is the number of positions ever claimed by producers.
+func (r *eventRing) enqueued() uint64 {
+	return atomic.LoadUint64(&r.tail)
+}
+
+// dequeued is the number of events ever consumed by the writer.
+func (r *eventRing) dequeued() uint64 {
+	return atomic.LoadUint64(&r.head)
+}
+
+// enqueue pushes ev into the queue, waiting for room when it is full
+// (back-pressure instead of loss). Events logged after Close are counted as
+// dropped.
+func (_log *Log) enqueue(ev logEvent) {
+	if _log.shards != nil {
+		_log.nextShard().enqueue(ev)
+		return
+	}
+	if atomic.LoadInt32(&_log.headerPending) == 1 {
+		_log.takeHeader()
+	}
+	var seq uint64
+	for spins := 0; ; spins++ {
+		if atomic.LoadInt32(&_log.closed) == 1 {
+			atomic.AddUint64(&_log.dropped, 1)
+			return
+		}
+		if pos, ok := _log.queue.tryPush(ev); ok {
+			seq = pos + 1
+			_log.markSync(ev.level, seq)
+			break
+		}
+		_log.wakeWriter()
+		if spins < 64 {
+			runtime.Gosched()
+		} else {
+			time.Sleep(50 * time.Microsecond)
+		}
+	}
+	if atomic.LoadInt32(&_log.writerParked) == 1 {
+		_log.wakeWriter()
+	}
+	if _log.ack != nil {
+		_log.waitFor(seq)
+	}
+}
+
+// enqueueBatch is enqueue for several events, which keep their order and are
+// claimed in as few ring operations as room allows.
+func (_log *Log) enqueueBatch(evs []logEvent) {
+	if _log.shards != nil {
+		_log.nextShard().enqueueBatch(evs)
+		return
+	}
+	if atomic.LoadInt32(&_log.headerPending) == 1 {
+		_log.takeHeader()
+	}
+	var seq uint64
+	for spins := 0; len(evs) > 0; spins++ {
+		if atomic.LoadInt32(&_log.closed) == 1 {
+			atomic.AddUint64(&_log.dropped, uint64(len(evs)))
+			return
+		}
+		if pos, n := _log.queue.tryPushN(evs); n > 0 {
+			for i := 0; i < n; i++ {
+				_log.markSync(evs[i].level, pos+uint64(i)+1)
+			}
+			seq = pos + uint64(n)
+			if evs = evs[n:]; len(evs) == 0 {
+				break
+			}
+		}
+		_log.wakeWriter()
+		if spins < 64 {
+			runtime.Gosched()
+		} else {
+			time.Sleep(50 * time.Microsecond)
+		}
+	}
+	if atomic.LoadInt32(&_log.writerParked) == 1 {
+		_log.wakeWriter()
+	}
+	if _log.ack != nil && seq > 0 {
+		_log.waitFor(seq)
+	}
+}
+
+func (_log *Log) wakeWriter() {
+	select {
+	case _log.wake <- struct{}{}:
+	default:
+	}
+}
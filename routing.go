@@ -0,0 +1,212 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxRoutedOpen bounds the routed files kept open at once; the least
+// recently written one is closed (and reopened on demand) beyond it.
+const maxRoutedOpen = 64
+
+// router sends records to a file chosen by the value of one field.
+type router struct {
+	key         string
+	name        string // con "{value}"
+	maxSize     int64
+	maxRotation int
+	files       map[string]*levelFile // por valor, protegido por Log.mtx
+	all         []*levelFile          // archivos de nivel y luego los enrutados
+	flushes     uint64                // solo writer
+	errs        *errSink
+}
+
+// WithRouting writes records carrying the field key to a file of their own
+// instead of the main one, so a multi-tenant service gets separated logs from
+// one logger. The file name is name with "{value}" replaced by the field
+// value:
+//
+//	acacia.WithRouting("tenant", "tenant-{value}.log", 10, 5)
+//
+// Files live in the logger's directory, are created with their first record
+// and rotate by size on their own (sizeMB <= 0: no rotation). Values are
+// reduced to letters, digits, '-', '_' and '.' for the file name. Routing
+// applies to typed fields (InfoFields…) in every format and to map entries in
+// structured formats; records without the field or with an empty value stay
+// in the main file. Level files still get their copy; the hash chain only
+// covers the main file.
+func WithRouting(key, name string, sizeMB, backup int) Option {
+	return func(conf *config) {
+		if key == "" || !strings.Contains(name, "{value}") {
+			return
+		}
+		if backup < 1 {
+			backup = 1
+		}
+		r := &router{key: key, name: name, maxRotation: backup, files: make(map[string]*levelFile)}
+		if sizeMB > 0 {
+			r.maxSize = int64(sizeMB) * 1024 * 1024
+		}
+		conf.router = r
+	}
+}
+
+// fieldRoute returns the routing value carried by fields, or "".
+func (_log *Log) fieldRoute(fields []Field) string {
+	if _log.router == nil {
+		return ""
+	}
+	for i := range fields {
+		if fields[i].Key != _log.router.key {
+			continue
+		}
+		if fields[i].kind == fieldString {
+			return fields[i].str
+		}
+		return fmt.Sprint(fields[i].Value())
+	}
+	return ""
+}
+
+// mapRoute returns the routing value of a structured map entry, or "".
+func (_log *Log) mapRoute(fields map[string]interface{}) string {
+	if _log.router == nil {
+		return ""
+	}
+	v, ok := fields[_log.router.key]
+	if !ok || v == nil {
+		return ""
+	}
+	if s, ok := fieldValue(v).(string); ok {
+		return s
+	}
+	return fmt.Sprint(fieldValue(v))
+}
+
+// routeLine moves the line that starts at start in the batch buffer to the
+// file for value. Binary records get an absolute timestamp, since the file
+// does not see the record before them. Writer goroutine only, with mtx held.
+func (_log *Log) routeLine(start int, value string, binary bool) {
+	r := _log.router
+	lf := r.files[value]
+	if lf == nil {
+		lf = &levelFile{
+			name:        strings.Replace(r.name, "{value}", routeFileValue(value), -1),
+			maxSize:     r.maxSize,
+			maxRotation: r.maxRotation,
+			errs:        _log.errs,
+		}
+		r.files[value] = lf
+		r.all = append(r.all, lf)
+	}
+	if binary {
+		lf.buf = appendBinaryAbsolute(lf.buf, _log.buffer[start:], _log.binPrev)
+	} else {
+		lf.buf = append(lf.buf, _log.buffer[start:]...)
+	}
+	_log.buffer = _log.buffer[:start]
+}
+
+// routeFileValue makes value safe as part of a file name.
+func routeFileValue(value string) string {
+	if len(value) > 64 {
+		value = value[:64]
+	}
+	b := []byte(value)
+	for i, c := range b {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+			b[i] = '_'
+		}
+	}
+	if s := string(b); s != "." && s != ".." {
+		return s
+	}
+	return "_"
+}
+
+// sideFiles returns the level files followed by the routed files.
+func (_log *Log) sideFiles() []*levelFile {
+	if _log.router == nil {
+		return _log.levelFiles
+	}
+	return _log.router.all
+}
+
+// flushRoutes writes the lines queued for every routed file, closing the
+// least recently written ones beyond maxRoutedOpen. Writer goroutine only;
+// the buffers were swapped under mtx by flush.
+func (_log *Log) flushRoutes() {
+	r := _log.router
+	r.flushes++
+	routed := r.all[len(_log.levelFiles):]
+	for _, lf := range routed {
+		if len(lf.out) == 0 {
+			continue
+		}
+		if lf.file == nil {
+			r.evict(routed)
+		}
+		_log.writeSideFile(lf, lf.out)
+		lf.out = lf.out[:0]
+		lf.used = r.flushes
+	}
+}
+
+// evict closes the least recently written file when maxRoutedOpen are open.
+func (r *router) evict(routed []*levelFile) {
+	var lru *levelFile
+	open := 0
+	for _, lf := range routed {
+		if lf.file == nil {
+			continue
+		}
+		open++
+		if lru == nil || lf.used < lru.used {
+			lru = lf
+		}
+	}
+	if open < maxRoutedOpen {
+		return
+	}
+	if err := syncFile(lru.file); err != nil {
+		r.errs.report("fsync routed file %s: %v", lru.name, err)
+	}
+	if err := lru.file.Close(); err != nil {
+		r.errs.report("closing routed file %s: %v", lru.name, err)
+	}
+	lru.file = nil
+}
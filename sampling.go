@@ -0,0 +1,160 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// samplerSlots is the number of counters per level; messages are hashed into
+// them, so rare collisions make two messages share a budget.
+const samplerSlots = 4096
+
+// sampler limits identical messages per level within a window: the first
+// `first` go through, then every `thereafter`-th one.
+type sampler struct {
+	window     int64 // nanosegundos; atómicos, ver set
+	first      uint64
+	thereafter uint64
+	counts     [5][samplerSlots]samplerCount
+}
+
+type samplerCount struct {
+	resetAt int64
+	n       uint64
+}
+
+// WithSampling protects disks during log storms, zap style: within each
+// window, the first `first` records with the same level and message are
+// logged, then only every `thereafter`-th (none if thereafter is 0). Messages
+// are compared by format string, so arguments do not matter; for maps the
+// "msg" field is used. Sampled() counts what was left out.
+func WithSampling(window time.Duration, first, thereafter int) Option {
+	return func(conf *config) {
+		if window > 0 && first > 0 && thereafter >= 0 {
+			conf.sampler = &sampler{
+				window:     int64(window),
+				first:      uint64(first),
+				thereafter: uint64(thereafter),
+			}
+		}
+	}
+}
+
+// set changes the sampling parameters of a running logger; first 0 turns
+// sampling off. Windows already open keep their end time.
+func (s *sampler) set(window time.Duration, first, thereafter int) {
+	atomic.StoreInt64(&s.window, int64(window))
+	atomic.StoreUint64(&s.first, uint64(first))
+	atomic.StoreUint64(&s.thereafter, uint64(thereafter))
+}
+
+// Sampled returns the number of records discarded by WithSampling.
+func (_log *Log) Sampled() uint64 { return atomic.LoadUint64(&_log.sampled) }
+
+// sample reports whether a record with the given level and message key may be
+// logged, counting it as sampled out otherwise.
+func (_log *Log) sample(level string, hash uint32) bool {
+	s := _log.sampler
+	rank := levelRank(level)
+	if rank < 0 {
+		return true
+	}
+	c := &s.counts[rank][hash%samplerSlots]
+	now := time.Now().UnixNano()
+	window := atomic.LoadInt64(&s.window)
+	first, thereafter := atomic.LoadUint64(&s.first), atomic.LoadUint64(&s.thereafter)
+	if first == 0 {
+		// muestreo desactivado por una recarga de configuración
+		return true
+	}
+
+	var n uint64
+	if resetAt := atomic.LoadInt64(&c.resetAt); resetAt > now {
+		n = atomic.AddUint64(&c.n, 1)
+	} else if atomic.CompareAndSwapInt64(&c.resetAt, resetAt, now+window) {
+		atomic.StoreUint64(&c.n, 1)
+		n = 1
+	} else {
+		n = atomic.AddUint64(&c.n, 1)
+	}
+
+	if n <= first || (thereafter > 0 && (n-first)%thereafter == 0) {
+		return true
+	}
+	atomic.AddUint64(&_log.sampled, 1)
+	return false
+}
+
+// FNV-1a de 32 bits, sin allocs para string ni []byte
+const (
+	fnvOffset = 2166136261
+	fnvPrime  = 16777619
+)
+
+func hashString(s string) uint32 {
+	h := uint32(fnvOffset)
+	for i := 0; i < len(s); i++ {
+		h ^= uint32(s[i])
+		h *= fnvPrime
+	}
+	return h
+}
+
+func hashBytes(b []byte) uint32 {
+	h := uint32(fnvOffset)
+	for _, c := range b {
+		h ^= uint32(c)
+		h *= fnvPrime
+	}
+	return h
+}
+
+// sampleKey hashes what identifies "the same message" for data.
+func sampleKey(data interface{}) uint32 {
+	switch v := data.(type) {
+	case string:
+		return hashString(v)
+	case []byte:
+		return hashBytes(v)
+	case map[string]interface{}:
+		if msg, ok := v["msg"].(string); ok {
+			return hashString(msg)
+		}
+	}
+	return hashString(fmt.Sprint(data))
+}
@@ -0,0 +1,104 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import "regexp"
+
+type getSecret struct {
+	AWSKeys      string
+	BearerTokens string
+	CreditCards  string
+}
+
+// Secret lists the built-in scrubbers accepted by WithSecretScrubbing.
+var Secret = getSecret{
+	AWSKeys:      "AWS_KEYS",
+	BearerTokens: "BEARER_TOKENS",
+	CreditCards:  "CREDIT_CARDS",
+}
+
+var secretScrubbers = map[string][]scrubber{
+	// IDs de access key y el valor de aws_secret_access_key
+	Secret.AWSKeys: {
+		{re: regexp.MustCompile(`\b(?:AKIA|ASIA|ABIA|ACCA|AGPA|AIDA|AIPA|ANPA|ANVA|APKA|AROA)[A-Z0-9]{16}\b`)},
+		{re: regexp.MustCompile(`(?i)aws_?secret_?(?:access_?)?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+]{40})\b`), group: true},
+	},
+	Secret.BearerTokens: {
+		{re: regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9\-._~+/]+=*)`), group: true},
+	},
+	Secret.CreditCards: {
+		{re: regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`), valid: luhnValid},
+	},
+}
+
+// WithSecretScrubbing replaces well-known secret shapes with the redaction
+// mask ([REDACTED] unless WithRedaction sets another) in every line: AWS
+// access key IDs and secret keys, bearer tokens, and card numbers that pass
+// the Luhn check. With no arguments every scrubber in Secret is enabled.
+func WithSecretScrubbing(kinds ...string) Option {
+	if len(kinds) == 0 {
+		kinds = []string{Secret.AWSKeys, Secret.BearerTokens, Secret.CreditCards}
+	}
+	return func(conf *config) {
+		for _, kind := range kinds {
+			if sc, ok := secretScrubbers[kind]; ok {
+				rd := redactorFor(conf)
+				rd.scrubbers = append(rd.scrubbers, sc...)
+			}
+		}
+	}
+}
+
+// luhnValid reports whether the digits of b (spaces and dashes ignored) pass
+// the Luhn checksum used by payment cards.
+func luhnValid(b []byte) bool {
+	sum, n := 0, 0
+	for i := len(b) - 1; i >= 0; i-- {
+		c := b[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && n <= 19 && sum%10 == 0
+}
@@ -0,0 +1,107 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import "strconv"
+
+type getSeverity struct {
+	Syslog string
+	OTel   string
+}
+
+// Severity names the numeric scales of WithSeverity.
+var Severity = getSeverity{
+	Syslog: "SYSLOG", // RFC 5424: 7 DEBUG, 6 INFO, 4 WARN, 3 ERROR, 2 CRITICAL
+	OTel:   "OTEL",   // OpenTelemetry SeverityNumber: 5 DEBUG, 9 INFO, 13 WARN, 17 ERROR, 21 CRITICAL
+}
+
+// severities holds the numbers of each scale, by levelRank.
+var (
+	syslogSeverities = [5]int{7, 6, 4, 3, 2}
+	otelSeverities   = [5]int{5, 9, 13, 17, 21}
+)
+
+// WithSeverity adds a numeric "severity" next to the level in JSON and
+// logfmt entries, on the given Severity scale, for alerting rules that
+// compare numbers (severity <= 3) rather than names. With WithGKE, whose
+// level member is already "severity", it is named "severity_number". An
+// unknown scale is ignored.
+func WithSeverity(scale string) Option {
+	return func(conf *config) {
+		switch scale {
+		case Severity.Syslog:
+			conf.severities = &syslogSeverities
+		case Severity.OTel:
+			conf.severities = &otelSeverities
+		}
+	}
+}
+
+// SyslogSeverity returns the RFC 5424 severity of level (2 Critical to
+// 7 Debug), or 6 (Informational) for unknown levels.
+func SyslogSeverity(level string) int {
+	if rank := levelRank(level); rank >= 0 {
+		return syslogSeverities[rank]
+	}
+	return syslogSeverities[1]
+}
+
+// severityKey returns the member name of the numeric severity for schema.
+func severityKey(s *jsonSchema) string {
+	if s.level == "severity" {
+		return "severity_number"
+	}
+	return "severity"
+}
+
+// severity returns the number of level on the logger's scale, and false
+// without WithSeverity.
+func (_log *Log) severity(level string) (int, bool) {
+	rank := levelRank(level)
+	if _log.severities == nil || rank < 0 {
+		return 0, false
+	}
+	return _log.severities[rank], true
+}
+
+// appendLogfmtSeverity appends " severity=N" when enabled.
+func (_log *Log) appendLogfmtSeverity(dst []byte, level string) []byte {
+	n, ok := _log.severity(level)
+	if !ok {
+		return dst
+	}
+	dst = append(dst, " severity="...)
+	return strconv.AppendInt(dst, int64(n), 10)
+}
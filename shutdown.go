@@ -0,0 +1,107 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// managers holds the Managers not closed yet, for Shutdown.
+var managers = struct {
+	sync.Mutex
+	live map[*Manager]struct{}
+}{live: make(map[*Manager]struct{})}
+
+// Shutdown flushes and closes every Manager and every logger in the registry
+// or set as Default, concurrently, and empties the registry, so main needs a
+// single call for a graceful exit:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	defer cancel()
+//	if err := acacia.Shutdown(ctx); err != nil {
+//		fmt.Fprintln(os.Stderr, err)
+//	}
+//
+// It returns the first close error, or ctx.Err() if ctx ends first; the
+// remaining loggers keep closing in the background. Loggers already closed by
+// their owner are skipped.
+func Shutdown(ctx context.Context) error {
+	managers.Lock()
+	ms := managers.live
+	managers.live = make(map[*Manager]struct{})
+	managers.Unlock()
+
+	registry.Lock()
+	named := registry.logs
+	registry.logs = make(map[string]*Log)
+	registry.Unlock()
+
+	logs := make(map[*Log]struct{}, len(named)+1)
+	for _, lg := range named {
+		logs[lg] = struct{}{}
+	}
+	if def := Default(); def != nil {
+		logs[def] = struct{}{}
+		SetDefault(nil)
+	}
+	// los de un Manager los cierra su Close
+	for m := range ms {
+		for _, lg := range m.logs {
+			delete(logs, lg)
+		}
+	}
+
+	results := make(chan error, len(ms)+len(logs))
+	for m := range ms {
+		go func(m *Manager) { results <- m.Close() }(m)
+	}
+	for lg := range logs {
+		go func(lg *Log) { results <- lg.Close() }(lg)
+	}
+	var first error
+	for i := 0; i < cap(results); i++ {
+		select {
+		case err := <-results:
+			if err != nil && !errors.Is(err, ErrLoggerClosed) && first == nil {
+				first = err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return first
+}
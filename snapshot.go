@@ -0,0 +1,156 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+)
+
+// snapshotTimeout bounds how long Snapshot waits for the writer, which
+// may need to copy a large live file.
+const snapshotTimeout = 30 * time.Second
+
+// Snapshot flushes the logger and copies the current file plus its rotated
+// backups into the directory dstPath, which must not exist yet. The set is
+// assembled in a temporary directory and renamed into place, so readers see
+// either the whole snapshot or nothing.
+//
+// The snapshot runs on the writer goroutine, so no rotation or write can
+// happen while it is taken. Backups are hard-linked when possible (they are
+// never written again); the live file is always copied.
+func (_log *Log) Snapshot(dstPath string) error {
+	if _log.sink != nil {
+		return ErrNoFile
+	}
+	if dstPath == "" {
+		return fmt.Errorf("snapshot destination cannot be empty")
+	}
+	dstPath = filepath.Clean(dstPath)
+	if _, err := os.Stat(dstPath); err == nil {
+		return fmt.Errorf("snapshot destination %s already exists", dstPath)
+	}
+
+	var snapErr error
+	run := func() {
+		snapErr = _log.snapshotFiles(dstPath)
+	}
+	if err := _log.barrier(run, snapshotTimeout); err != nil {
+		return err
+	}
+	return snapErr
+}
+
+func (_log *Log) snapshotFiles(dstPath string) error {
+	tmp := fmt.Sprintf("%s.tmp-%d", dstPath, time.Now().UnixNano())
+	if err := os.MkdirAll(tmp, 0755); err != nil {
+		return err
+	}
+
+	files, err := _log.backupFiles()
+	if err != nil {
+		_ = os.RemoveAll(tmp)
+		return err
+	}
+
+	current := filepath.Join(_log.path, _log.name)
+	for _, src := range files {
+		dst := filepath.Join(tmp, filepath.Base(src))
+		if src != current {
+			if err := os.Link(src, dst); err == nil {
+				continue
+			}
+		}
+		if err := copyFile(src, dst); err != nil {
+			_ = os.RemoveAll(tmp)
+			return err
+		}
+	}
+
+	if err := os.Rename(tmp, dstPath); err != nil {
+		_ = os.RemoveAll(tmp)
+		return err
+	}
+	return nil
+}
+
+// backupFiles lists the live file and every backup produced by size and
+// daily rotation: app.log, app.log.N, app-YYYY-MM-DD.log and app-YYYY-MM-DD.log.N.
+func (_log *Log) backupFiles() ([]string, error) {
+	stem, ext := splitLogExt(_log.name)
+	re := regexp.MustCompile(`^(?:` + regexp.QuoteMeta(_log.name) + `|` +
+		regexp.QuoteMeta(stem) + `-\d{4}-\d{2}-\d{2}` + regexp.QuoteMeta(ext) + `)(?:\.\d+)?$`)
+
+	entries, err := os.ReadDir(_log.path)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		if e.IsDir() || !re.MatchString(e.Name()) {
+			continue
+		}
+		files = append(files, filepath.Join(_log.path, e.Name()))
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,217 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+	"sync/atomic"
+)
+
+// spillChunk bounds how much of the spill file is replayed per flush.
+const spillChunk = 4 << 20
+
+// spillFile is the write-ahead file behind WithSpillover. Writer goroutine
+// only, except the counter.
+type spillFile struct {
+	path      string
+	threshold int
+	f         *os.File
+	size      int64 // bytes escritos en el archivo
+	off       int64 // bytes ya repetidos al archivo principal
+	force     bool  // Sync/Close: repetir aunque la cola siga cargada
+	chunk     []byte
+	spilled   uint64 // lotes desviados, atómico
+}
+
+// WithSpillover keeps bursts out of memory: while more than threshold events
+// are queued, the writer appends its encoded batches to "<name>.wal" next to
+// the log file instead of the log itself, and replays them into the log, in
+// order and through the usual rotation, once the queue is down to half the
+// threshold. Sync and Close replay everything first.
+//
+// A spill file left by a crash is replayed when the logger starts again, so
+// batches the writer had already taken from memory survive the process.
+// Records replayed when the crash hit may then appear twice. Loggers started
+// with StartWriter ignore this option.
+func WithSpillover(threshold int) Option {
+	return func(conf *config) {
+		if threshold > 0 {
+			conf.spillThreshold = threshold
+		}
+	}
+}
+
+// Spilled returns how many batches went through the spill file, or 0 without
+// WithSpillover.
+func (_log *Log) Spilled() uint64 {
+	if _log.spill == nil {
+		return 0
+	}
+	return atomic.LoadUint64(&_log.spill.spilled)
+}
+
+// openSpill opens the spill file of the log at path, picking up whatever a
+// previous run left in it.
+func openSpill(path string, threshold int) (*spillFile, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, err
+	}
+	s := &spillFile{path: path, threshold: threshold, f: f}
+	if info, err := f.Stat(); err == nil {
+		s.size = info.Size()
+	}
+	return s, nil
+}
+
+func (s *spillFile) pending() bool {
+	return s.off < s.size
+}
+
+// routeSpill decides where the batch p goes. It returns what the writer must write
+// to the log now: p itself, p behind replayed spill data, or nil when p went
+// to the spill file.
+func (_log *Log) routeSpill(p []byte) []byte {
+	s := _log.spill
+	queued := _log.queue.len()
+	if !s.pending() && queued <= s.threshold {
+		return p
+	}
+	if !s.force && queued > s.threshold/2 {
+		// sigue la ráfaga: al archivo de desborde, detrás de lo ya desviado
+		if len(p) == 0 {
+			return nil
+		}
+		if _, err := s.f.Write(p); err != nil {
+			_log.reportError("writing spill file %s: %v", s.path, err)
+			return s.replay(_log, p)
+		}
+		s.size += int64(len(p))
+		atomic.AddUint64(&s.spilled, 1)
+		return nil
+	}
+	return s.replay(_log, p)
+}
+
+// replay returns the next chunk of spilled records, cut at a record boundary,
+// followed by p once the spill file is exhausted. Until then p is appended to
+// the spill file to keep the order.
+func (s *spillFile) replay(_log *Log, p []byte) []byte {
+	if !s.pending() {
+		return p
+	}
+	n := s.size - s.off
+	last := n <= spillChunk
+	if !last {
+		n = spillChunk
+	}
+	if cap(s.chunk) < int(n) {
+		s.chunk = make([]byte, n)
+	}
+	buf := s.chunk[:n]
+	read, err := s.f.ReadAt(buf, s.off)
+	if err != nil && err != io.EOF {
+		// sin poder leerlo no hay forma de avanzar: se descarta
+		_log.reportError("reading spill file %s, dropping %d bytes: %v", s.path, s.size-s.off, err)
+		s.off, read, last = s.size, 0, true
+	}
+	buf = buf[:read]
+	if !last {
+		buf = buf[:wholeRecords(buf, _log.lineFormat() == Format.Binary)]
+	}
+	s.off += int64(len(buf))
+	if s.pending() {
+		if len(p) == 0 {
+			return buf
+		}
+		if _, err := s.f.Write(p); err != nil {
+			_log.reportError("writing spill file %s: %v", s.path, err)
+			return append(buf, p...)
+		}
+		s.size += int64(len(p))
+		return buf
+	}
+	if err := s.f.Truncate(0); err != nil {
+		_log.reportError("truncating spill file %s: %v", s.path, err)
+	}
+	s.size, s.off = 0, 0
+	return append(buf, p...)
+}
+
+// wholeRecords returns the length of the complete records at the start of p
+// (all of p if not even one is complete).
+func wholeRecords(p []byte, bin bool) int {
+	end := 0
+	if bin {
+		for end < len(p) {
+			n, k := binary.Uvarint(p[end:])
+			if k <= 0 || n > uint64(len(p)-end-k) {
+				break
+			}
+			end += k + int(n)
+		}
+	} else {
+		end = bytes.LastIndexByte(p, '\n') + 1
+	}
+	if end == 0 {
+		return len(p)
+	}
+	return end
+}
+
+// drainSpill replays the whole spill file into the log. Writer goroutine
+// only.
+func (_log *Log) drainSpill() {
+	s := _log.spill
+	s.force = true
+	for s.pending() {
+		_log.flush()
+	}
+	s.force = false
+}
+
+// closeSpill closes the spill file and removes it when nothing is left.
+func (s *spillFile) close() error {
+	err := s.f.Close()
+	if !s.pending() {
+		if rmErr := os.Remove(s.path); rmErr != nil && !os.IsNotExist(rmErr) && err == nil {
+			err = rmErr
+		}
+	}
+	return err
+}
@@ -0,0 +1,116 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+const (
+	// packagePath identifies acacia's own frames, which are left out of traces.
+	packagePath   = "github.com/humanjuan/acacia/v2."
+	maxStackDepth = 32
+)
+
+// WithStackTrace captures the calling goroutine's stack for entries at level
+// or above (for example Level.ERROR). Text lines get one indented
+// "at func (file:line)" line per frame; structured formats get a "stack"
+// field with the same frames separated by newlines.
+func WithStackTrace(level string) Option {
+	return func(conf *config) {
+		level = strings.ToUpper(level)
+		if verifyLevel(level) {
+			conf.stackLevel = level
+		}
+	}
+}
+
+// stackFor returns the stack of the logging call, one frame per line, or ""
+// when level does not ask for one.
+func (_log *Log) stackFor(level string) string {
+	if _log.stackLevel == "" || levelRank(level) < levelRank(_log.stackLevel) {
+		return ""
+	}
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(2, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+
+	buf := getBuf()
+	defer putBuf(buf)
+	caller := false
+	for {
+		fr, more := frames.Next()
+		if !caller && strings.HasPrefix(fr.Function, packagePath) {
+			// frames de acacia hasta llegar al código que llamó al logger
+			if !more {
+				break
+			}
+			continue
+		}
+		caller = true
+		if strings.HasPrefix(fr.Function, "runtime.") {
+			break
+		}
+		if len(buf) > 0 {
+			buf = append(buf, '\n')
+		}
+		buf = append(buf, fr.Function...)
+		buf = append(buf, " ("...)
+		buf = append(buf, filepath.Base(fr.File)...)
+		buf = append(buf, ':')
+		buf = strconv.AppendInt(buf, int64(fr.Line), 10)
+		buf = append(buf, ')')
+		if !more {
+			break
+		}
+	}
+	return string(buf)
+}
+
+// appendStackText appends stack to a text message, one "\tat frame" line per
+// frame.
+func appendStackText(msg, stack string) string {
+	var b strings.Builder
+	b.Grow(len(msg) + len(stack) + 8*strings.Count(stack, "\n") + 8)
+	b.WriteString(strings.TrimRight(msg, "\n"))
+	for _, frame := range strings.Split(stack, "\n") {
+		b.WriteString("\n\tat ")
+		b.WriteString(frame)
+	}
+	return b.String()
+}
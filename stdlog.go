@@ -0,0 +1,124 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"io"
+	"log"
+	"regexp"
+	"strings"
+)
+
+// stdTimestamp matches the date and time the standard logger writes with its
+// default flags, so they are not logged twice.
+var stdTimestamp = regexp.MustCompile(`^\d{4}/\d{2}/\d{2} \d{2}:\d{2}:\d{2}(\.\d+)? `)
+
+// stdPrefixes maps the level prefixes found in legacy log lines, in upper
+// case, to acacia levels. Longer names go first so "WARNING" is not read as
+// "WARN" plus "ING".
+var stdPrefixes = []struct {
+	name, level string
+}{
+	{"CRITICAL", Level.CRITICAL},
+	{"WARNING", Level.WARN},
+	{"FATAL", Level.CRITICAL},
+	{"PANIC", Level.CRITICAL},
+	{"ERROR", Level.ERROR},
+	{"DEBUG", Level.DEBUG},
+	{"CRIT", Level.CRITICAL},
+	{"WARN", Level.WARN},
+	{"INFO", Level.INFO},
+	{"ERR", Level.ERROR},
+}
+
+type stdBridge struct {
+	lg *Log
+}
+
+// StdWriter returns an io.Writer for the standard library logger
+// (log.SetOutput, log.New) that routes each line to the level named by its
+// prefix, so legacy logs are classified correctly: "ERROR: x", "warning: x",
+// "[DEBUG] x" and "FATAL x" become ERROR, WARN, DEBUG and CRITICAL entries
+// with message "x". Lines without a known prefix are logged as INFO. The
+// date and time written by the default log flags are dropped, since acacia
+// adds its own.
+func (_log *Log) StdWriter() io.Writer {
+	return stdBridge{lg: _log}
+}
+
+// StdLogger returns a standard library *log.Logger writing through StdWriter,
+// for APIs such as http.Server.ErrorLog.
+func (_log *Log) StdLogger() *log.Logger {
+	return log.New(_log.StdWriter(), "", 0)
+}
+
+func (b stdBridge) Write(p []byte) (int, error) {
+	level, msg := stdLevel(strings.TrimRight(string(p), "\r\n"))
+	b.lg.logfString(level, msg)
+	return len(p), nil
+}
+
+// stdLevel detects the level prefix of line and returns it with the rest of
+// the message.
+func stdLevel(line string) (string, string) {
+	if loc := stdTimestamp.FindStringIndex(line); loc != nil {
+		line = line[loc[1]:]
+	}
+	s := strings.TrimLeft(line, " \t")
+	bracket := strings.HasPrefix(s, "[")
+	if bracket {
+		s = s[1:]
+	}
+	for _, p := range stdPrefixes {
+		if len(s) < len(p.name) || !strings.EqualFold(s[:len(p.name)], p.name) {
+			continue
+		}
+		rest := s[len(p.name):]
+		switch {
+		case bracket && strings.HasPrefix(rest, "]"):
+			rest = rest[1:]
+		case bracket:
+			continue
+		case strings.HasPrefix(rest, ":"):
+			rest = rest[1:]
+		case rest == "" || rest[0] == ' ' || rest[0] == '\t':
+		default:
+			// "Errors found": la palabra continúa, no es un prefijo
+			continue
+		}
+		return p.level, strings.TrimLeft(rest, " \t")
+	}
+	return Level.INFO, line
+}
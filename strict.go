@@ -0,0 +1,82 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"bytes"
+	"encoding/json"
+	"unicode/utf8"
+)
+
+// invalidJSONField marks the records WithStrictUTF8 had to rewrap.
+const invalidJSONField = "invalid_json"
+
+// WithStrictUTF8 validates every line on the writer before it is written:
+// invalid UTF-8 sequences become U+FFFD in any format and, with Format.JSON,
+// a line that does not parse (a custom Encoder or a json.RawMessage field
+// gone wrong) is replaced by a record holding it as its "msg", with
+// "invalid_json":true, so every line downstream consumers read is valid.
+// The built-in encoders already produce valid JSON; this covers what they do
+// not control, at the cost of a scan (and, for JSON, a parse) per line.
+func WithStrictUTF8() Option {
+	return func(conf *config) {
+		conf.strictUTF8 = true
+	}
+}
+
+// lineValid reports whether line passes WithStrictUTF8 as it is.
+func (_log *Log) lineValid(line []byte) bool {
+	if _log.lineFormat() == Format.JSON {
+		return json.Valid(line)
+	}
+	return utf8.Valid(line)
+}
+
+// enforceValid repara la línea que empieza en start del buffer del lote.
+// Writer goroutine only, with mtx held.
+func (_log *Log) enforceValid(start int, level uint8) {
+	line := _log.buffer[start:]
+	if _log.lineValid(line) {
+		return
+	}
+	if _log.lineFormat() == Format.JSON {
+		msg := string(bytes.TrimSuffix(line, []byte{'\n'}))
+		_log.buffer = _log.appendJSONEntry(_log.buffer[:start], levelString(level), msg, "",
+			[]Field{Bool(invalidJSONField, true)})
+		return
+	}
+	fixed := bytes.ToValidUTF8(line, []byte("\uFFFD"))
+	_log.buffer = append(_log.buffer[:start], fixed...)
+}
@@ -0,0 +1,144 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Encoder serializes structured entries. Its method set matches jsoniter's
+// and sonic's configs, so those can be passed to WithEncoder as is.
+type Encoder interface {
+	Marshal(v interface{}) ([]byte, error)
+}
+
+// EncoderFunc adapts a function to the Encoder interface.
+type EncoderFunc func(v interface{}) ([]byte, error)
+
+// Marshal calls f(v).
+func (f EncoderFunc) Marshal(v interface{}) ([]byte, error) { return f(v) }
+
+// defaultEncoder is encoding/json.
+var defaultEncoder Encoder = EncoderFunc(json.Marshal)
+
+// WithEncoder replaces encoding/json for structured output. The encoder must
+// produce a single JSON object with no trailing newline; it is called on the
+// logging goroutines (to flatten structs) and on the writer goroutine (to
+// encode JSON map entries), and must be safe for concurrent use.
+func WithEncoder(enc Encoder) Option {
+	return func(conf *config) {
+		if enc != nil {
+			conf.encoder = enc
+		}
+	}
+}
+
+// structFields flattens a struct (or pointer to struct) into entry fields
+// through encoding/json, so `json:` tags, omitempty and embedded structs
+// behave as they would with json.Marshal. Values are kept as raw JSON.
+func structFields(enc Encoder, data interface{}) (map[string]interface{}, bool) {
+	v := reflect.ValueOf(data)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, false
+	}
+	encoded, err := enc.Marshal(data)
+	if err != nil {
+		return nil, false
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(encoded, &raw); err != nil {
+		// MarshalJSON propio que no produce un objeto
+		return nil, false
+	}
+	fields := make(map[string]interface{}, len(raw))
+	for k, val := range raw {
+		fields[k] = val
+	}
+	return fields, true
+}
+
+// fieldValue picks how a field value is encoded: json.Marshaler first, then
+// encoding.TextMarshaler, error and fmt.Stringer, so custom types render as
+// their authors intended instead of through default reflection (an error, for
+// instance, would otherwise encode as {}).
+func fieldValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case *LazyValue:
+		return fieldValue(val.Value())
+	case nil, string, bool, int, int64, float64, json.Marshaler:
+		return v
+	case encoding.TextMarshaler:
+		text, err := marshalText(val)
+		if err != nil {
+			return fmt.Sprintf("!MarshalText error: %v", err)
+		}
+		return text
+	case error:
+		return callString(val, val.Error)
+	case fmt.Stringer:
+		return callString(val, val.String)
+	}
+	return v
+}
+
+func marshalText(m encoding.TextMarshaler) (text string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			text, err = fmt.Sprint(m), nil
+		}
+	}()
+	b, err := m.MarshalText()
+	return string(b), err
+}
+
+// callString calls Error/String, falling back to fmt if it panics (nil
+// receivers, typically).
+func callString(v interface{}, fn func() string) (s string) {
+	defer func() {
+		if recover() != nil {
+			s = fmt.Sprint(v)
+		}
+	}()
+	return fn()
+}
@@ -0,0 +1,191 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"errors"
+	"os"
+	"sync/atomic"
+	"time"
+)
+
+type syncKind uint8
+
+const (
+	syncNever syncKind = iota
+	syncEveryFlush
+	syncInterval
+	syncOnLevel
+)
+
+// SyncPolicy decides when the log file is fsynced, on top of the explicit
+// Sync() and Close() calls. For files, policy fsyncs run on a goroutine of
+// their own, so a slow disk delays durability but never the writer; a
+// StartWriter destination, and a file in AuditMode, whose records are only
+// acknowledged once fsynced, are synced by the writer itself.
+type SyncPolicy struct {
+	kind  syncKind
+	every time.Duration
+	level int
+}
+
+var (
+	// SyncNever keeps pure buffered writes; data reaches the disk when the OS
+	// decides, or on Sync()/Close(). This is the default.
+	SyncNever = SyncPolicy{kind: syncNever}
+	// SyncEveryFlush fsyncs after every flush that wrote data.
+	SyncEveryFlush = SyncPolicy{kind: syncEveryFlush}
+)
+
+// SyncInterval fsyncs at most once every d, if something was written since
+// the last fsync.
+func SyncInterval(d time.Duration) SyncPolicy {
+	if d <= 0 {
+		return SyncEveryFlush
+	}
+	return SyncPolicy{kind: syncInterval, every: d}
+}
+
+// SyncOnLevel fsyncs on the flush that writes a record at level or above
+// (e.g. Level.ERROR), leaving lower levels buffered.
+func SyncOnLevel(level string) SyncPolicy {
+	rank := levelRank(level)
+	if rank < 0 {
+		return SyncNever
+	}
+	return SyncPolicy{kind: syncOnLevel, level: rank}
+}
+
+// WithSyncPolicy sets the fsync policy of the writer.
+func WithSyncPolicy(policy SyncPolicy) Option {
+	return func(conf *config) {
+		conf.syncPolicy = policy
+	}
+}
+
+// markSync remembers, with SyncOnLevel, that the flush covering queue
+// position seq must fsync.
+func (_log *Log) markSync(level uint8, seq uint64) {
+	if _log.syncPolicy.kind != syncOnLevel || int(level) < _log.syncPolicy.level {
+		return
+	}
+	for {
+		cur := atomic.LoadUint64(&_log.syncWanted)
+		if cur >= seq || atomic.CompareAndSwapUint64(&_log.syncWanted, cur, seq) {
+			return
+		}
+	}
+}
+
+// applySyncPolicy runs on the writer goroutine at the end of a flush.
+// deq is the dequeue sequence covered by the bytes just written; with a
+// syncer the fsync of everything up to it is handed over and not awaited.
+func (_log *Log) applySyncPolicy(deq uint64, wrote bool) {
+	if wrote {
+		_log.unsynced = true
+	}
+	if !_log.unsynced {
+		return
+	}
+
+	switch _log.syncPolicy.kind {
+	case syncEveryFlush:
+	case syncInterval:
+		if time.Since(_log.lastSync) < _log.syncPolicy.every {
+			return
+		}
+	case syncOnLevel:
+		wanted := atomic.LoadUint64(&_log.syncWanted)
+		if wanted <= _log.syncedUpTo || wanted > deq {
+			return
+		}
+		_log.syncedUpTo = wanted
+	default:
+		return
+	}
+
+	if _log.syncReq != nil {
+		_log.requestSync(deq)
+		_log.unsynced = false
+		_log.lastSync = time.Now()
+		return
+	}
+	if err := _log.syncOut(); err != nil {
+		_log.reportError("fsync by sync policy: %v", err)
+		return
+	}
+	_log.unsynced = false
+	_log.lastSync = time.Now()
+}
+
+// requestSync pide al syncer un fsync que cubra hasta la posición deq. Las
+// peticiones pendientes se funden: un solo fsync cubre la mayor.
+func (_log *Log) requestSync(deq uint64) {
+	for {
+		cur := atomic.LoadUint64(&_log.syncTarget)
+		if cur >= deq || atomic.CompareAndSwapUint64(&_log.syncTarget, cur, deq) {
+			break
+		}
+	}
+	select {
+	case _log.syncReq <- struct{}{}:
+	default:
+	}
+}
+
+// runSyncer hace los fsync de la política fuera del writer. syncedSeq avanza
+// hasta la posición de cola que cubre el último fsync terminado.
+func (_log *Log) runSyncer() {
+	defer _log.wg.Done()
+	for {
+		select {
+		case <-_log.syncReq:
+		case <-_log.done:
+			// Close hace el fsync final
+			return
+		}
+		target := atomic.LoadUint64(&_log.syncTarget)
+		if target <= atomic.LoadUint64(&_log.syncedSeq) {
+			continue
+		}
+		// una rotación puede cerrar el archivo durante el fsync; el nuevo
+		// lo cubre la siguiente petición
+		if err := _log.syncOut(); err != nil && !errors.Is(err, os.ErrClosed) {
+			_log.reportError("fsync by sync policy: %v", err)
+			continue
+		}
+		atomic.StoreUint64(&_log.syncedSeq, target)
+	}
+}
@@ -0,0 +1,304 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultSDID is the SD-ID of the structured-data element that carries the
+// fields of RFC 5424 entries. 32473 is the enterprise number RFC 5612
+// reserves for documentation; use your own for production receivers.
+const DefaultSDID = "fields@32473"
+
+// SyslogConfig describes the header of RFC 5424 entries. Facility is the
+// syslog facility code (16 local0 … 23 local7); 0 (kernel messages, which
+// applications must not use) selects 1, user-level. Empty Hostname and
+// AppName default to the host name and the program name; SDID defaults to
+// DefaultSDID.
+type SyslogConfig struct {
+	Facility int
+	Hostname string
+	AppName  string
+	SDID     string
+}
+
+// syslogHeader holds the parts of the header that never change.
+type syslogHeader struct {
+	facility int
+	host     string
+	app      string
+	procID   string
+	sdID     string
+}
+
+// syslogTimeLayout is RFC 3339 with at most 6 fractional digits, as RFC 5424
+// requires.
+const syslogTimeLayout = "2006-01-02T15:04:05.000000Z07:00"
+
+// WithSyslog configures RFC 5424 output and makes Format.Syslog the initial
+// format:
+//
+//	<PRI>1 TIMESTAMP HOSTNAME APP-NAME PROCID MSGID [SD-ID key="value" …] MSG
+//
+// PRI combines the facility with the SyslogSeverity of the level. MSGID is
+// the "event" field when present, as the CEF event class. The other fields
+// become the parameters of a single SD-ELEMENT, sorted for maps and in call
+// order for typed fields; names are cut to 32 printable ASCII characters.
+// Line breaks in MSG are written as spaces so every entry stays on one line.
+func WithSyslog(c SyslogConfig) Option {
+	return func(conf *config) {
+		conf.syslog = newSyslogHeader(c)
+	}
+}
+
+// defaultSyslog is the header used when Format.Syslog is selected without
+// WithSyslog.
+func defaultSyslog() *syslogHeader {
+	return newSyslogHeader(SyslogConfig{})
+}
+
+func newSyslogHeader(c SyslogConfig) *syslogHeader {
+	if c.Facility <= 0 || c.Facility > 23 {
+		c.Facility = 1
+	}
+	if c.Hostname == "" {
+		c.Hostname, _ = os.Hostname()
+	}
+	if c.AppName == "" && len(os.Args) > 0 {
+		c.AppName = filepath.Base(os.Args[0])
+	}
+	if c.SDID == "" {
+		c.SDID = DefaultSDID
+	}
+	return &syslogHeader{
+		facility: c.Facility,
+		host:     syslogHeaderValue(c.Hostname, 255),
+		app:      syslogHeaderValue(c.AppName, 48),
+		procID:   strconv.Itoa(os.Getpid()),
+		sdID:     syslogName(c.SDID),
+	}
+}
+
+// formatSyslog renders a structured entry as an RFC 5424 line.
+func (_log *Log) formatSyslog(level string, fields map[string]interface{}) []byte {
+	msg, _ := fieldValue(fields["msg"]).(string)
+	event, _ := fieldValue(fields["event"]).(string)
+	buf := _log.appendSyslogHeader(getBuf(), level, event)
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		if k != "msg" && k != "event" {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	buf = _log.openSyslogSD(buf, len(keys) > 0)
+	for _, k := range keys {
+		buf = appendSyslogParamName(buf, k)
+		switch v := fieldValue(fields[k]).(type) {
+		case string:
+			buf = appendSyslogParamValue(buf, v)
+		default:
+			if out, ok := appendInt(buf, v); ok {
+				buf = out
+			} else if out, ok := appendValue(buf, v); ok {
+				buf = out
+			} else if encoded, err := _log.encoder.Marshal(v); err == nil {
+				buf = appendSyslogParamValue(buf, string(encoded))
+			}
+		}
+		buf = append(buf, '"')
+	}
+	return appendSyslogMsg(closeSyslogSD(buf, len(keys) > 0), msg)
+}
+
+// appendSyslogEntry is the typed-fields counterpart of formatSyslog.
+func (_log *Log) appendSyslogEntry(dst []byte, level, msg, id string, fields []Field) []byte {
+	event := ""
+	params := 0
+	for i := range fields {
+		if fields[i].Key == "event" && fields[i].kind == fieldString {
+			event = fields[i].str
+		} else {
+			params++
+		}
+	}
+	if id != "" {
+		params++
+	}
+	dst = _log.appendSyslogHeader(dst, level, event)
+	dst = _log.openSyslogSD(dst, params > 0)
+	for i := range fields {
+		f := &fields[i]
+		if f.Key == "event" && f.kind == fieldString {
+			continue
+		}
+		dst = appendSyslogParamName(dst, f.Key)
+		switch f.kind {
+		case fieldString:
+			dst = appendSyslogParamValue(dst, f.str)
+		case fieldError:
+			if f.iface != nil {
+				dst = appendSyslogParamValue(dst, callString(f.iface, f.iface.(error).Error))
+			}
+		case fieldAny:
+			if s, ok := fieldValue(f.iface).(string); ok {
+				dst = appendSyslogParamValue(dst, s)
+			} else if encoded, err := _log.encoder.Marshal(fieldValue(f.iface)); err == nil {
+				dst = appendSyslogParamValue(dst, string(encoded))
+			}
+		default:
+			// números, bools, duraciones y fechas no llevan caracteres a escapar
+			dst = appendFieldText(dst, f)
+		}
+		dst = append(dst, '"')
+	}
+	if id != "" {
+		dst = appendSyslogParamName(dst, "id")
+		dst = appendSyslogParamValue(dst, id)
+		dst = append(dst, '"')
+	}
+	return appendSyslogMsg(closeSyslogSD(dst, params > 0), msg)
+}
+
+// appendSyslogHeader appends everything up to STRUCTURED-DATA.
+func (_log *Log) appendSyslogHeader(dst []byte, level, event string) []byte {
+	h := _log.syslog
+	dst = append(dst, '<')
+	dst = strconv.AppendInt(dst, int64(h.facility*8+SyslogSeverity(level)), 10)
+	dst = append(dst, ">1 "...)
+	if _log.noTimestamp {
+		dst = append(dst, '-')
+	} else {
+		dst = _log.now().AppendFormat(dst, syslogTimeLayout)
+	}
+	for _, part := range [...]string{h.host, h.app, h.procID} {
+		dst = append(dst, ' ')
+		dst = appendSyslogNil(dst, part)
+	}
+	dst = append(dst, ' ')
+	return appendSyslogNil(dst, syslogHeaderValue(event, 32))
+}
+
+func (_log *Log) openSyslogSD(dst []byte, params bool) []byte {
+	if !params {
+		return append(dst, " -"...)
+	}
+	dst = append(dst, " ["...)
+	return append(dst, _log.syslog.sdID...)
+}
+
+func closeSyslogSD(dst []byte, params bool) []byte {
+	if params {
+		dst = append(dst, ']')
+	}
+	return dst
+}
+
+// appendSyslogMsg appends " MSG" (nothing for an empty one) and the newline.
+func appendSyslogMsg(dst []byte, msg string) []byte {
+	if msg != "" {
+		dst = append(dst, ' ')
+		if strings.ContainsAny(msg, "\r\n") {
+			msg = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ").Replace(msg)
+		}
+		dst = append(dst, msg...)
+	}
+	return append(dst, '\n')
+}
+
+// appendSyslogNil appends s, or the NILVALUE "-" when empty.
+func appendSyslogNil(dst []byte, s string) []byte {
+	if s == "" {
+		return append(dst, '-')
+	}
+	return append(dst, s...)
+}
+
+// appendSyslogParamName appends ` name="`.
+func appendSyslogParamName(dst []byte, name string) []byte {
+	dst = append(dst, ' ')
+	dst = append(dst, syslogName(name)...)
+	return append(dst, '=', '"')
+}
+
+// appendSyslogParamValue escapes '"', '\' and ']', as RFC 5424 requires.
+func appendSyslogParamValue(dst []byte, s string) []byte {
+	if !strings.ContainsAny(s, "\"\\]") {
+		return append(dst, s...)
+	}
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '"', '\\', ']':
+			dst = append(dst, '\\', c)
+		default:
+			dst = append(dst, c)
+		}
+	}
+	return dst
+}
+
+// syslogHeaderValue keeps the printable ASCII characters of s, up to max.
+func syslogHeaderValue(s string, max int) string {
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s) && len(b) < max; i++ {
+		if c := s[i]; c > ' ' && c < 0x7f {
+			b = append(b, c)
+		}
+	}
+	return string(b)
+}
+
+// syslogName makes s a valid SD-NAME: up to 32 printable ASCII characters
+// other than '=', ' ', ']' and '"'.
+func syslogName(s string) string {
+	if s == "" {
+		return "_"
+	}
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s) && len(b) < 32; i++ {
+		c := s[i]
+		if c <= ' ' || c >= 0x7f || c == '=' || c == ']' || c == '"' {
+			c = '_'
+		}
+		b = append(b, c)
+	}
+	return string(b)
+}
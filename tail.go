@@ -0,0 +1,193 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const defaultTailInterval = 250 * time.Millisecond
+
+// TailOptions configures Tail. The embedded Query filters the streamed
+// entries.
+type TailOptions struct {
+	Query
+	FromStart bool            // stream the existing content first instead of starting at the end
+	Interval  time.Duration   // poll interval, 250ms if <= 0
+	Done      <-chan struct{} // close it to stop following; the entry channel is then closed
+}
+
+// Tail follows the log file at path like "tail -F" and streams its entries,
+// parsed as by Reader. It keeps following when the file is rotated (the path
+// points to a new file) or truncated. The file must exist when Tail is
+// called; entries are delivered once their last line has been written.
+func Tail(path string, opts TailOptions) (<-chan Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	var offset int64
+	if !opts.FromStart {
+		if offset, err = f.Seek(0, io.SeekEnd); err != nil {
+			f.Close()
+			return nil, err
+		}
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = defaultTailInterval
+	}
+	t := &tailer{
+		path:   path,
+		f:      f,
+		offset: offset,
+		rd:     NewReader(nil, opts.Query),
+		out:    make(chan Entry, 64),
+		done:   opts.Done,
+	}
+	t.rd.logger = filepath.Base(path)
+	go t.run(opts.Interval)
+	return t.out, nil
+}
+
+type tailer struct {
+	path    string
+	f       *os.File
+	offset  int64
+	partial []byte // última línea, aún sin '\n'
+	rd      *Reader
+	out     chan Entry
+	done    <-chan struct{}
+}
+
+func (t *tailer) run(interval time.Duration) {
+	defer close(t.out)
+	defer func() { t.f.Close() }()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := t.f.Read(buf)
+		if n > 0 {
+			t.offset += int64(n)
+			if !t.lines(buf[:n]) {
+				return
+			}
+			continue
+		}
+		if err != nil && err != io.EOF {
+			reportInternalError("tail %s: %v", t.path, err)
+		}
+		// sin datos nuevos: la última entrada ya está completa
+		if e, ok := t.rd.flush(); ok && !t.send(e) {
+			return
+		}
+		t.follow()
+		select {
+		case <-t.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// lines splits p into lines and sends the entries they complete.
+func (t *tailer) lines(p []byte) bool {
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			t.partial = append(t.partial, p...)
+			return true
+		}
+		line := p[:i]
+		if len(t.partial) > 0 {
+			line = append(t.partial, line...)
+			t.partial = t.partial[:0]
+		}
+		p = p[i+1:]
+		if e, ok := t.rd.push(bytes.TrimRight(line, "\r")); ok && !t.send(e) {
+			return false
+		}
+	}
+	return true
+}
+
+func (t *tailer) send(e Entry) bool {
+	if !t.rd.match(&e) {
+		return true
+	}
+	select {
+	case t.out <- e:
+		return true
+	case <-t.done:
+		return false
+	}
+}
+
+// follow switches to the file now at path if it was rotated, or rewinds if
+// the file was truncated. Lines written to the old file after this check are
+// lost.
+func (t *tailer) follow() {
+	info, err := os.Stat(t.path)
+	if err != nil {
+		// el archivo puede faltar un instante durante la rotación
+		return
+	}
+	cur, err := t.f.Stat()
+	if err == nil && os.SameFile(cur, info) {
+		if info.Size() < t.offset {
+			if _, err := t.f.Seek(0, io.SeekStart); err == nil {
+				t.offset = 0
+				t.partial = t.partial[:0]
+			}
+		}
+		return
+	}
+	f, err := os.Open(t.path)
+	if err != nil {
+		return
+	}
+	if len(t.partial) > 0 {
+		if e, ok := t.rd.push(t.partial); ok {
+			t.send(e)
+		}
+		t.partial = t.partial[:0]
+	}
+	t.f.Close()
+	t.f, t.offset = f, 0
+}
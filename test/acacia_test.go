@@ -203,3 +203,19 @@ go test -v -race ./...
 go test -run TestConcurrentWrites -race   // harder
 go test -bench=. -run=Benchmark
 */
+
+func TestLogAfterCloseIsDropped(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("closed.log", tmp, acacia.Level.INFO)
+	lg.Info("antes")
+	lg.Close()
+
+	lg.Info("despues")
+	lg.InfoBytes([]byte("despues bytes"))
+	if lg.Dropped() != 2 {
+		t.Fatalf("Se esperaban 2 descartes tras Close(), hay %d", lg.Dropped())
+	}
+	if strings.Contains(readLog(t, filepath.Join(tmp, "closed.log")), "despues") {
+		t.Fatal("Se escribió un registro posterior a Close()")
+	}
+}
@@ -0,0 +1,64 @@
+package acacia_test
+
+import (
+	"fmt"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+	"github.com/humanjuan/acacia/v2/acaciatest"
+)
+
+// recordingT captura los fallos de las aserciones sin fallar el test real.
+type recordingT struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordingT) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordingT) Helper() {}
+
+func TestAcaciatestObserves(t *testing.T) {
+	lg := acaciatest.New(t)
+	lg.Debug("cache %s", "fría")
+	lg.WarnFields("disco", acacia.Int("pct", 91))
+	lg.Error("fallo al guardar")
+
+	entries := lg.ObservedEntries()
+	if len(entries) != 3 || entries[0].Message != "cache fría" || entries[1].Fields[0].Value() != int64(91) {
+		t.Fatalf("Entradas inesperadas: %+v", entries)
+	}
+	if got := lg.FilterLevel(acacia.Level.WARN); len(got) != 1 || got[0].Message != "disco" {
+		t.Fatalf("FilterLevel inesperado: %+v", got)
+	}
+	if got := lg.FilterMessage("guardar"); len(got) != 1 || got[0].Level != acacia.Level.ERROR {
+		t.Fatalf("FilterMessage inesperado: %+v", got)
+	}
+	lg.AssertLogged(acacia.Level.ERROR, "guardar")
+	lg.AssertLogged("", "cache")
+	lg.AssertNotLogged(acacia.Level.INFO, "")
+
+	lg.Reset()
+	if got := lg.ObservedEntries(); len(got) != 0 {
+		t.Fatalf("Reset no vació las entradas: %+v", got)
+	}
+}
+
+func TestAcaciatestAssertFailures(t *testing.T) {
+	rt := &recordingT{TB: t}
+	lg := acaciatest.New(rt, acacia.WithRedaction(acacia.RedactConfig{Keys: []string{"token"}}))
+	lg.SetLevel(acacia.Level.INFO)
+	lg.Debug("oculto")
+	lg.InfoFields("login", acacia.String("token", "abc"))
+
+	lg.AssertLogged(acacia.Level.DEBUG, "oculto")
+	lg.AssertNotLogged(acacia.Level.INFO, "login")
+	if len(rt.errors) != 2 {
+		t.Fatalf("Se esperaban 2 fallos, obtenidos %q", rt.errors)
+	}
+	if f := lg.FilterMessage("login")[0].Fields[0]; f.Value() != "[REDACTED]" {
+		t.Fatalf("Las entradas observadas debían estar redactadas: %v", f.Value())
+	}
+}
@@ -0,0 +1,72 @@
+package acacia_test
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+type stringerVal struct{ name string }
+
+func (s stringerVal) String() string { return "<" + s.name + ">" }
+
+// formatterErr imprime otra cosa con %v y %s que con Error, como pkg/errors.
+type formatterErr struct{ msg string }
+
+func (e formatterErr) Error() string { return e.msg }
+
+func (e formatterErr) Format(s fmt.State, verb rune) {
+	fmt.Fprintf(s, "%s (stack)", e.msg)
+}
+
+type nilStringer struct{}
+
+func (n *nilStringer) String() string { return "boom" + fmt.Sprint(*n) }
+
+// El formateo propio debe producir exactamente lo mismo que fmt.Sprintf,
+// incluso cuando recurre a fmt para verbos o tipos no soportados.
+func TestFormattedMatchesFmt(t *testing.T) {
+	var np *nilStringer
+	cases := []struct {
+		format string
+		args   []interface{}
+	}{
+		{"user %s logged in from %s", []interface{}{"juan", "192.168.1.100"}},
+		{"id=%d n=%d u=%d", []interface{}{42, int64(-7), uint8(255)}},
+		{"mem %.2f GB, ratio %f", []interface{}{7.8, float32(0.5)}},
+		{"v: %v %v %v %v %v", []interface{}{"s", 12, true, 3.25, errors.New("fail")}},
+		{"stringer %s / %v", []interface{}{stringerVal{"x"}, stringerVal{"y"}}},
+		{"bytes %s vs %v", []interface{}{[]byte("abc"), []byte("abc")}},
+		{"100%% done in %v", []interface{}{time.Second}},
+		{"width %5d and %x", []interface{}{3, 255}},
+		{"missing %d %d", []interface{}{1}},
+		{"extra %d", []interface{}{1, 2}},
+		{"wrong %d", []interface{}{"str"}},
+		{"nil stringer %v", []interface{}{np}},
+		{"formatter %v / %s", []interface{}{formatterErr{"fail"}, formatterErr{"fail"}}},
+		{"slice %v map %v", []interface{}{[]int{1, 2}, map[string]int{"a": 1}}},
+	}
+
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("fmt.log", tmp, acacia.Level.INFO)
+	for _, c := range cases {
+		lg.Info(c.format, c.args...)
+	}
+	lg.Close()
+
+	lines := strings.Split(strings.TrimSuffix(readLog(t, filepath.Join(tmp, "fmt.log")), "\n"), "\n")
+	if len(lines) != len(cases) {
+		t.Fatalf("Se esperaban %d líneas, hay %d", len(cases), len(lines))
+	}
+	for i, c := range cases {
+		want := "[INFO] " + fmt.Sprintf(c.format, c.args...)
+		if !strings.HasSuffix(lines[i], want) {
+			t.Errorf("Formato %q:\n  got  %q\n  want sufijo %q", c.format, lines[i], want)
+		}
+	}
+}
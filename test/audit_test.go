@@ -0,0 +1,118 @@
+package acacia_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestAuditMode(t *testing.T) {
+	key := []byte("secreto")
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "audit.log")
+
+	lg, err := acacia.Start("audit.log", tmp, acacia.Level.INFO, acacia.AuditMode(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lg.Close()
+
+	// el registro está en disco en cuanto vuelve la llamada, sin Sync
+	lg.Info("rol admin otorgado a juan")
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "rol admin otorgado a juan hmac=") {
+		t.Fatalf("El registro debería estar escrito y encadenado al volver:\n%s", content)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 20; i++ {
+				lg.Info(fmt.Sprintf("g%d evento %d", g, i))
+			}
+		}(g)
+	}
+	wg.Wait()
+	content, _ = os.ReadFile(path)
+	if n := bytes.Count(content, []byte("\n")); n != 161 {
+		t.Fatalf("Se esperaban 161 líneas al volver las llamadas, hay %d", n)
+	}
+	if _, err := acacia.VerifyHashChain(bytes.NewReader(content), key, nil); err != nil {
+		t.Fatalf("La cadena debería verificar: %v", err)
+	}
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if perm := info.Mode().Perm(); perm != 0600 {
+			t.Fatalf("Permisos esperados 0600, obtenidos %o", perm)
+		}
+	}
+}
+
+func TestAuditModeKeepsBackups(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "audit.log")
+
+	// un archivo previo con permisos amplios se restringe al abrirlo
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	lg, err := acacia.Start("audit.log", tmp, acacia.Level.INFO, acacia.AuditMode(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg.Rotation(1, 1)
+	line := strings.Repeat("x", 1000)
+	for i := 0; i < 5000; i++ {
+		lg.Info(line)
+	}
+	if err := lg.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var total int
+	backups := 0
+	for i := 0; ; i++ {
+		content, err := os.ReadFile(fmt.Sprintf("%s.%d", path, i))
+		if err != nil {
+			break
+		}
+		backups++
+		total += bytes.Count(content, []byte("\n"))
+		if runtime.GOOS != "windows" {
+			info, _ := os.Stat(fmt.Sprintf("%s.%d", path, i))
+			if perm := info.Mode().Perm(); perm != 0600 {
+				t.Fatalf("Respaldo %d con permisos %o, se esperaba 0600", i, perm)
+			}
+		}
+	}
+	content, _ := os.ReadFile(path)
+	total += bytes.Count(content, []byte("\n"))
+	if backups < 4 {
+		t.Fatalf("Se esperaban varios respaldos pese a Rotation(1, 1), hay %d", backups)
+	}
+	if total != 5000 {
+		t.Fatalf("La rotación de auditoría no debe borrar registros: %d de 5000", total)
+	}
+	if runtime.GOOS != "windows" {
+		info, _ := os.Stat(path)
+		if perm := info.Mode().Perm(); perm != 0600 {
+			t.Fatalf("Permisos esperados 0600, obtenidos %o", perm)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package acacia_test
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestBarrierCoversPriorRecords(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("barrier.log", tmp, acacia.Level.INFO)
+	defer lg.Close()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				if i%2 == 0 {
+					lg.Info("rapido")
+				} else {
+					lg.Info("formato %d-%d", g, i)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if err := lg.Barrier(); err != nil {
+		t.Fatalf("Barrier falló: %v", err)
+	}
+	if n := strings.Count(readLog(t, filepath.Join(tmp, "barrier.log")), "[INFO]"); n != 4000 {
+		t.Fatalf("Tras Barrier() se esperaban 4000 líneas en disco, hay %d", n)
+	}
+}
+
+func TestBarrierAfterClose(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("barrier.log", tmp, acacia.Level.INFO)
+	lg.Close()
+	if err := lg.Barrier(); !errors.Is(err, acacia.ErrLoggerClosed) {
+		t.Fatalf("Se esperaba ErrLoggerClosed, se obtuvo %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package acacia_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestLogBatch(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "batch.log")
+	lg, err := acacia.Start("batch.log", tmp, acacia.Level.INFO, acacia.WithBufferSize(256))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// lotes más grandes que la cola, en paralelo con registros sueltos
+	const batches, perBatch = 4, 3000
+	var wg sync.WaitGroup
+	for b := 0; b < batches; b++ {
+		wg.Add(1)
+		go func(b int) {
+			defer wg.Done()
+			entries := make([]acacia.Entry, perBatch)
+			for i := range entries {
+				entries[i] = acacia.Entry{Level: "info", Message: fmt.Sprintf("b%d n%05d", b, i), Fields: []acacia.Field{acacia.Int("row", i)}}
+			}
+			lg.LogBatch(entries)
+		}(b)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			lg.Info("suelto")
+		}
+	}()
+	wg.Wait()
+	lg.LogBatch([]acacia.Entry{
+		{Level: acacia.Level.DEBUG, Message: "debajo del nivel"},
+		{Level: "TRACE", Message: "nivel desconocido"},
+		{Level: acacia.Level.WARN, Message: "con id", ID: "req-7"},
+	})
+	if err := lg.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	content := readLog(t, path)
+	next := make([]int, batches)
+	for _, line := range strings.Split(strings.TrimSpace(content), "\n") {
+		i := strings.Index(line, "] b")
+		if i < 0 {
+			continue
+		}
+		var b, n int
+		if _, err := fmt.Sscanf(line[i+2:], "b%d n%d", &b, &n); err != nil {
+			t.Fatalf("Línea inesperada %q: %v", line, err)
+		}
+		if n != next[b] || !strings.HasSuffix(line, fmt.Sprintf(" row=%d", n)) {
+			t.Fatalf("b%d: se esperaba n%05d, llegó %q", b, next[b], line)
+		}
+		next[b]++
+	}
+	for b, n := range next {
+		if n != perBatch {
+			t.Fatalf("b%d: %d de %d entradas", b, n, perBatch)
+		}
+	}
+	if got := strings.Count(content, "suelto"); got != 1000 {
+		t.Fatalf("Se esperaban 1000 registros sueltos, hay %d", got)
+	}
+	if !strings.Contains(content, "[WARN] [req-7] con id") {
+		t.Fatalf("El ID de la entrada debería conservarse:\n%s", content[len(content)-200:])
+	}
+	if strings.Contains(content, "debajo del nivel") || strings.Contains(content, "nivel desconocido") {
+		t.Fatal("Las entradas bajo el nivel o con nivel desconocido no deben escribirse")
+	}
+}
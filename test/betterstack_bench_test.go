@@ -56,6 +56,18 @@ func Benchmark_string_Parallel_1KB(b *testing.B) {
 	})
 }
 
+func Benchmark_formatted(b *testing.B) {
+	lg, _ := acacia.Start("bench.log", b.TempDir(), acacia.Level.INFO, acacia.WithBufferSize(5_000_000), acacia.WithBatchSize(512*1024))
+	defer lg.Close()
+
+	user, ip := "juan", "192.168.1.100"
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lg.Info("User %s logged in from %s", user, ip)
+	}
+}
+
 /*
 # Benchmark básico
 go test -bench=Benchmark_string -benchmem
@@ -93,3 +93,19 @@ go test -bench=Benchmark_structured_1KB -benchmem -benchtime=5s
 # Paralelo + 1KB
 go test -bench=Benchmark_structured_Parallel_1KB -benchmem -benchtime=5s
 */
+
+func Benchmark_structured_fields(b *testing.B) {
+	lg, _ := acacia.Start("bench.log", b.TempDir(), acacia.Level.INFO, acacia.WithBufferSize(5_000_000), acacia.WithBatchSize(512*1024))
+	lg.StructuredJSON(true)
+	defer lg.Close()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		lg.InfoFields("user_auth",
+			acacia.String("user", "juan@example.com"),
+			acacia.String("ip", "192.168.1.1"),
+			acacia.Int("attempt", i),
+		)
+	}
+}
@@ -0,0 +1,136 @@
+package acacia_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	acacia "github.com/humanjuan/acacia/v2"
+	"github.com/humanjuan/acacia/v2/binlog"
+)
+
+func TestBinaryFormatRoundTrip(t *testing.T) {
+	tmp := t.TempDir()
+	lg, err := acacia.Start("app.bin", tmp, acacia.Level.DEBUG,
+		acacia.WithLevelFile("app.error.bin", acacia.Level.ERROR, 1, 2))
+	if err != nil {
+		t.Fatalf("Start falló: %v", err)
+	}
+	lg.OutputFormat(acacia.Format.Binary)
+	lg.Info("arranque")
+	lg.Debug("valor %d", 42)
+	lg.ErrorFields("fallo",
+		acacia.String("user", "ana"),
+		acacia.Int("n", -7),
+		acacia.Float64("ratio", 0.5),
+		acacia.Bool("ok", false),
+		acacia.Duration("took", 1500*time.Millisecond),
+		acacia.Any("meta", map[string]int{"a": 1}))
+	lg.Sync()
+	lg.Warn("fin")
+	lg.Close()
+
+	f, err := os.Open(filepath.Join(tmp, "app.bin"))
+	if err != nil {
+		t.Fatalf("No se pudo abrir el log: %v", err)
+	}
+	defer f.Close()
+	rd := binlog.NewReader(f)
+	var entries []acacia.Entry
+	for {
+		e, err := rd.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Next falló: %v", err)
+		}
+		entries = append(entries, e)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("Se esperaban 4 registros, obtenidos %d", len(entries))
+	}
+	want := []struct{ level, msg string }{
+		{acacia.Level.INFO, "arranque"},
+		{acacia.Level.DEBUG, "valor 42"},
+		{acacia.Level.ERROR, "fallo"},
+		{acacia.Level.WARN, "fin"},
+	}
+	for i, w := range want {
+		if entries[i].Level != w.level || entries[i].Message != w.msg {
+			t.Fatalf("Registro %d inesperado: %+v", i, entries[i])
+		}
+		if i > 0 && entries[i].Time.Before(entries[i-1].Time) {
+			t.Fatalf("Timestamps no monótonos: %v", entries)
+		}
+	}
+	if d := time.Since(entries[0].Time); d < 0 || d > time.Minute {
+		t.Fatalf("Timestamp absoluto incorrecto: %v", entries[0].Time)
+	}
+	fields := entries[2].Fields
+	if len(fields) != 6 || fields[0].Value() != "ana" || fields[1].Value() != int64(-7) ||
+		fields[2].Value() != 0.5 || fields[3].Value() != false || fields[4].Value() != 1500*time.Millisecond {
+		t.Fatalf("Campos inesperados: %v", fields)
+	}
+
+	var js bytes.Buffer
+	f.Seek(0, io.SeekStart)
+	if err := binlog.Convert(&js, f, acacia.Format.JSON); err != nil {
+		t.Fatalf("Convert JSON falló: %v", err)
+	}
+	if !strings.Contains(js.String(), `"level":"ERROR","msg":"fallo","user":"ana","n":-7,"ratio":0.5,"ok":false,"took":"1.5s","meta":{"a":1}`) {
+		t.Fatalf("JSON inesperado: %s", js.String())
+	}
+
+	lf, err := os.Open(filepath.Join(tmp, "app.error.bin"))
+	if err != nil {
+		t.Fatalf("No se pudo abrir el archivo de nivel: %v", err)
+	}
+	defer lf.Close()
+	var txt bytes.Buffer
+	if err := binlog.Convert(&txt, lf, acacia.Format.Text); err != nil {
+		t.Fatalf("Convert texto falló: %v", err)
+	}
+	if got := strings.TrimSpace(txt.String()); strings.Count(got, "\n") != 0 || !strings.Contains(got, "[ERROR] fallo user=ana n=-7") {
+		t.Fatalf("Texto inesperado: %q", got)
+	}
+}
+
+func TestBinaryRotationAndTruncation(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("app.bin", tmp, acacia.Level.INFO)
+	lg.OutputFormat(acacia.Format.Binary)
+	lg.Rotation(1, 3)
+	chunk := strings.Repeat("x", 100*1024)
+	for i := 0; i < 15; i++ {
+		lg.Info(chunk)
+		lg.Sync()
+	}
+	lg.Close()
+
+	// Cada archivo rotado debe poder leerse por sí solo con timestamps absolutos.
+	for _, name := range []string{"app.bin", "app.bin.0"} {
+		data, err := os.ReadFile(filepath.Join(tmp, name))
+		if err != nil {
+			t.Fatalf("Se esperaba %s: %v", name, err)
+		}
+		rd := binlog.NewReader(bytes.NewReader(data))
+		e, err := rd.Next()
+		if err != nil || e.Message != chunk || time.Since(e.Time) > time.Minute {
+			t.Fatalf("Primer registro de %s inválido: %v %v", name, e.Time, err)
+		}
+
+		rd = binlog.NewReader(bytes.NewReader(data[:len(data)-10]))
+		for err == nil {
+			_, err = rd.Next()
+		}
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Fatalf("Se esperaba io.ErrUnexpectedEOF en %s, obtenido %v", name, err)
+		}
+	}
+}
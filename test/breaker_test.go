@@ -0,0 +1,39 @@
+package acacia_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestCircuitBreakerLevelFile(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("main.log", tmp, acacia.Level.INFO,
+		acacia.WithLevelFile(filepath.Join("errs", "error.log"), acacia.Level.ERROR, 0, 1),
+		acacia.WithCircuitBreaker(2, 300*time.Millisecond))
+	lg.Error("uno")
+	lg.Sync()
+	lg.Error("dos")
+	lg.Sync()
+	// el directorio vuelve, pero el breaker sigue abierto hasta el fin de la pausa
+	if err := os.Mkdir(filepath.Join(tmp, "errs"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	lg.Error("tres")
+	lg.Sync()
+	time.Sleep(350 * time.Millisecond)
+	lg.Error("cuatro")
+	lg.Close()
+
+	got := readLog(t, filepath.Join(tmp, "errs", "error.log"))
+	if strings.Contains(got, "tres") || !strings.Contains(got, "[ERROR] cuatro") {
+		t.Fatalf("Durante la pausa no se escribe y después se reintenta: %q", got)
+	}
+	if main := readLog(t, filepath.Join(tmp, "main.log")); strings.Count(main, "[ERROR]") != 4 {
+		t.Fatalf("El archivo principal no debe verse afectado: %q", main)
+	}
+}
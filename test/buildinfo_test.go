@@ -0,0 +1,52 @@
+package acacia_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestBuildInfoFirstEntryOnly(t *testing.T) {
+	want := map[string]bool{}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if v := info.Main.Version; v != "" && v != "(devel)" {
+			want["module_version"] = true
+		}
+		for _, s := range info.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				want["vcs_revision"] = true
+			case "vcs.modified":
+				want["vcs_modified"] = true
+			}
+		}
+	}
+
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("build.log", tmp, acacia.Level.INFO, acacia.WithBuildInfo(false))
+	lg.StructuredJSON(true)
+	lg.Info("primera")
+	lg.InfoFields("segunda")
+	lg.Close()
+
+	lines := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "build.log"))), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Se esperaban 2 líneas, obtenidas %d: %q", len(lines), lines)
+	}
+	for i, line := range lines {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("JSON inválido %q: %v", line, err)
+		}
+		for _, key := range []string{"module_version", "vcs_revision", "vcs_modified"} {
+			_, has := entry[key]
+			if has != (want[key] && i == 0) {
+				t.Fatalf("Línea %d: campo %s presente=%v, esperado=%v", i, key, has, want[key] && i == 0)
+			}
+		}
+	}
+}
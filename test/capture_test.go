@@ -0,0 +1,35 @@
+package acacia_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestCaptureOutput(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("capture.log", tmp, acacia.Level.INFO)
+	restore, err := lg.CaptureOutput()
+	if errors.Is(err, acacia.ErrCaptureUnsupported) {
+		t.Skip("captura no soportada en esta plataforma")
+	}
+	if err != nil {
+		t.Fatalf("CaptureOutput() = %v", err)
+	}
+	fmt.Println("hola desde stdout")
+	fmt.Fprintln(os.Stderr, "aviso desde stderr")
+	if err := restore(); err != nil {
+		t.Fatalf("restore() = %v", err)
+	}
+	lg.Close()
+
+	got := readLog(t, filepath.Join(tmp, "capture.log"))
+	if !strings.Contains(got, "[INFO] hola desde stdout source=stdout\n") || !strings.Contains(got, "[INFO] aviso desde stderr source=stderr\n") {
+		t.Fatalf("Salida capturada inesperada: %q", got)
+	}
+}
@@ -0,0 +1,56 @@
+package acacia_test
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestCEFOutput(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("cef.log", tmp, acacia.Level.INFO, acacia.WithCEF(acacia.CEFConfig{
+		Vendor:     "Acme",
+		Product:    "Gate|way",
+		Version:    "1.0",
+		Extensions: map[string]string{"ip": "src", "user": "suser"},
+	}))
+	lg.Warn(map[string]interface{}{
+		"msg":   "login fallido",
+		"event": "auth-failure",
+		"ip":    "10.0.0.7",
+		"user":  "juan",
+		"query": "a=b\\c",
+	})
+	lg.CriticalFields("intrusión", acacia.String("ip", "10.0.0.8"), acacia.Int("attempts", 12))
+	lg.Close()
+
+	lines := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "cef.log"))), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Se esperaban 2 líneas, obtenidas %d: %q", len(lines), lines)
+	}
+	want := []string{
+		`^CEF:0\|Acme\|Gate\\\|way\|1\.0\|auth-failure\|login fallido\|5\|rt=\d+ src=10\.0\.0\.7 query=a\\=b\\\\c suser=juan$`,
+		`^CEF:0\|Acme\|Gate\\\|way\|1\.0\|CRITICAL\|intrusión\|10\|rt=\d+ src=10\.0\.0\.8 attempts=12$`,
+	}
+	for i, pattern := range want {
+		if !regexp.MustCompile(pattern).MatchString(lines[i]) {
+			t.Fatalf("Línea %d %q no coincide con %s", i, lines[i], pattern)
+		}
+	}
+}
+
+func TestCEFDefaults(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("cef.log", tmp, acacia.Level.INFO)
+	lg.OutputFormat(acacia.Format.CEF)
+	lg.Info("hola")
+	lg.Close()
+
+	line := strings.TrimSpace(readLog(t, filepath.Join(tmp, "cef.log")))
+	if !regexp.MustCompile(`^CEF:0\|humanjuan\|acacia\|[^|]+\|INFO\|hola\|3\|rt=\d+$`).MatchString(line) {
+		t.Fatalf("Línea CEF inesperada: %q", line)
+	}
+}
@@ -0,0 +1,56 @@
+package acacia_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+// fakeClock es un reloj controlado por el test.
+type fakeClock struct {
+	mtx sync.Mutex
+	t   time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) Set(t time.Time) {
+	c.mtx.Lock()
+	c.t = t
+	c.mtx.Unlock()
+}
+
+func TestWithClock(t *testing.T) {
+	tmp := t.TempDir()
+	clock := &fakeClock{t: time.Date(2030, 1, 1, 23, 59, 58, 0, time.UTC)}
+	lg, _ := acacia.Start("daily.log", tmp, acacia.Level.INFO, acacia.WithClock(clock.Now))
+	lg.UseUTC(true)
+	lg.DailyRotation(true)
+	lg.Info("primero")
+	lg.Sync()
+
+	clock.Set(time.Date(2030, 1, 2, 0, 0, 1, 0, time.UTC))
+	lg.Info("segundo")
+	lg.Sync()
+	lg.Close()
+
+	// la rotación forzada y la de medianoche comparten fecha: ninguna debe pisar a la otra
+	dated := filepath.Join(tmp, "daily-2030-01-01.log")
+	got := readLog(t, dated+".0") + readLog(t, dated)
+	if !strings.Contains(got, "Jan 1, 2030 23:59:58.000000 UTC [INFO] primero") ||
+		!strings.Contains(got, "Jan 2, 2030 00:00:01.000000 UTC [INFO] segundo") {
+		t.Fatalf("Timestamps del reloj o registros perdidos: %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "daily.log")); err != nil {
+		t.Fatalf("No se recreó el archivo principal: %v", err)
+	}
+}
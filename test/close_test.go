@@ -0,0 +1,61 @@
+package acacia_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestSyncAndCloseErrors(t *testing.T) {
+	if _, err := os.Stat("/dev/full"); err != nil {
+		t.Skip("/dev/full no disponible")
+	}
+	lg, err := acacia.Start("full", "/dev", acacia.Level.INFO)
+	if err != nil {
+		t.Skipf("No se pudo abrir /dev/full: %v", err)
+	}
+	lg.Info("sin espacio")
+	if err := lg.Sync(); !errors.Is(err, syscall.ENOSPC) {
+		t.Fatalf("Sync() = %v, se esperaba ENOSPC", err)
+	}
+	if err := lg.Sync(); err != nil {
+		t.Fatalf("El error ya reportado no debía repetirse: %v", err)
+	}
+	lg.Info("otra vez")
+	if err := lg.Close(); !errors.Is(err, syscall.ENOSPC) {
+		t.Fatalf("Close() = %v, se esperaba ENOSPC", err)
+	}
+	if err := lg.Close(); !errors.Is(err, acacia.ErrLoggerClosed) {
+		t.Fatalf("Segundo Close() = %v, se esperaba ErrLoggerClosed", err)
+	}
+}
+
+func TestCloseNoError(t *testing.T) {
+	lg, _ := acacia.Start("ok.log", t.TempDir(), acacia.Level.INFO)
+	lg.Info("hola")
+	if err := lg.Sync(); err != nil {
+		t.Fatalf("Sync() = %v", err)
+	}
+	if err := lg.Close(); err != nil {
+		t.Fatalf("Close() = %v", err)
+	}
+}
+
+func TestFlush(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("flush.log", tmp, acacia.Level.INFO, acacia.WithFlushInterval(time.Hour))
+	defer lg.Close()
+	lg.Info("pendiente")
+	if err := lg.Flush(); err != nil {
+		t.Fatalf("Flush() = %v", err)
+	}
+	if got := readLog(t, filepath.Join(tmp, "flush.log")); !strings.HasSuffix(got, "[INFO] pendiente\n") {
+		t.Fatalf("El registro no se escribió tras Flush: %q", got)
+	}
+}
@@ -0,0 +1,63 @@
+package acacia_test
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestCommandWriters(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("cmd.log", tmp, acacia.Level.INFO)
+	stdout, stderr := lg.CommandWriters("job")
+	stdout.Write([]byte("uno\r\ndo"))
+	stdout.Write([]byte("s\n\ntres"))
+	stderr.Write([]byte("fallo\n"))
+	stdout.Close()
+	stderr.Close()
+	lg.Close()
+
+	lines := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "cmd.log"))), "\n")
+	want := []string{
+		"[INFO] job: uno source=stdout",
+		"[INFO] job: dos source=stdout",
+		"[ERROR] job: fallo source=stderr",
+		"[INFO] job: tres source=stdout",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("Se esperaban %d líneas, hay %d: %q", len(want), len(lines), lines)
+	}
+	for i := range want {
+		if !strings.HasSuffix(lines[i], want[i]) {
+			t.Fatalf("Línea %d: se esperaba %q, obtenida %q", i, want[i], lines[i])
+		}
+	}
+}
+
+func TestCommandWritersExec(t *testing.T) {
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh no disponible")
+	}
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("exec.log", tmp, acacia.Level.INFO)
+	cmd := exec.Command(sh, "-c", "echo hola; echo roto >&2; printf final")
+	stdout, stderr := lg.CommandWriters("sh")
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatal(err)
+	}
+	stdout.Close()
+	stderr.Close()
+	lg.Close()
+
+	content := readLog(t, filepath.Join(tmp, "exec.log"))
+	for _, want := range []string{"[INFO] sh: hola source=stdout", "[ERROR] sh: roto source=stderr", "[INFO] sh: final source=stdout"} {
+		if !strings.Contains(content, want) {
+			t.Fatalf("Falta %q en:\n%s", want, content)
+		}
+	}
+}
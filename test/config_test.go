@@ -0,0 +1,88 @@
+package acacia_test
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	p := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(p, []byte(content), 0644); err != nil {
+		t.Fatalf("No se pudo escribir la configuración: %v", err)
+	}
+	return p
+}
+
+func TestStartFromConfig(t *testing.T) {
+	tmp := t.TempDir()
+	conf := writeConfig(t, "acacia.json", fmt.Sprintf(`{
+	  "loggers": [
+	    {"name": "app", "file": "app.log", "path": %q, "level": "debug", "default": true,
+	     "timestamp_format": "RFC3339", "flush_interval": "50ms",
+	     "rotation": {"size_mb": 10, "backups": 3},
+	     "level_files": [{"file": "app.error.log", "level": "error"}]},
+	    {"file": "audit.log", "path": %q, "level": "warn", "format": "json"}
+	  ]
+	}`, tmp, tmp))
+
+	logs, err := acacia.StartFromConfig(conf)
+	if err != nil {
+		t.Fatalf("StartFromConfig falló: %v", err)
+	}
+	defer acacia.SetDefault(nil)
+	defer acacia.Register("app", nil)
+	defer acacia.Register("audit.log", nil)
+	if len(logs) != 2 || acacia.Get("app") != logs["app"] || acacia.Get("audit.log") != logs["audit.log"] {
+		t.Fatalf("Loggers no registrados: %v", logs)
+	}
+	if acacia.Default() != logs["app"] {
+		t.Fatalf("El logger por defecto debía ser app")
+	}
+	acacia.Debug("detalle")
+	acacia.Error("fallo")
+	logs["audit.log"].Info("ignorado")
+	logs["audit.log"].Warn("acceso")
+	for _, lg := range logs {
+		lg.Close()
+	}
+
+	app := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "app.log"))), "\n")
+	if len(app) != 2 || !strings.Contains(app[0], "T") || !strings.HasSuffix(app[1], "[ERROR] fallo") {
+		t.Fatalf("app.log inesperado: %q", app)
+	}
+	if got := strings.TrimSpace(readLog(t, filepath.Join(tmp, "app.error.log"))); !strings.HasSuffix(got, "[ERROR] fallo") {
+		t.Fatalf("app.error.log inesperado: %q", got)
+	}
+	if got := strings.TrimSpace(readLog(t, filepath.Join(tmp, "audit.log"))); !strings.HasPrefix(got, "{") || !strings.Contains(got, `"msg":"acceso"`) || strings.Contains(got, "ignorado") {
+		t.Fatalf("audit.log inesperado: %q", got)
+	}
+}
+
+func TestStartFromConfigErrors(t *testing.T) {
+	if _, err := acacia.StartFromConfig(writeConfig(t, "acacia.yaml", "loggers: []")); !errors.Is(err, acacia.ErrConfigFormat) {
+		t.Fatalf("Se esperaba ErrConfigFormat, obtenido %v", err)
+	}
+	tmp := t.TempDir()
+	bad := []string{
+		`{"loggers": []}`,
+		fmt.Sprintf(`{"loggers": [{"file": "a.log", "path": %q, "level": "verbose"}]}`, tmp),
+		fmt.Sprintf(`{"loggers": [{"file": "a.log", "path": %q, "format": "xml"}]}`, tmp),
+		fmt.Sprintf(`{"loggers": [{"file": "a.log", "path": %q, "levle": "info"}]}`, tmp),
+		fmt.Sprintf(`{"loggers": [{"file": "a.log", "path": %q}, {"name": "a.log", "file": "b.log", "path": %q}]}`, tmp, tmp),
+	}
+	for _, c := range bad {
+		if _, err := acacia.StartFromConfig(writeConfig(t, "acacia.json", c)); err == nil {
+			t.Fatalf("Se esperaba un error para %s", c)
+		}
+	}
+	if acacia.Get("a.log") != nil {
+		t.Fatalf("Un arranque fallido no debía registrar loggers")
+	}
+}
@@ -0,0 +1,57 @@
+package acacia_test
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestRequestIDContext(t *testing.T) {
+	if acacia.RequestID(context.Background()) != "" {
+		t.Fatal("Un contexto sin ID debe devolver vacío")
+	}
+	id := acacia.NewRequestID()
+	if len(id) != 26 {
+		t.Fatalf("Se esperaba un ULID, obtenido %q", id)
+	}
+	ctx := acacia.ContextWithRequestID(context.Background(), id)
+	if acacia.RequestID(ctx) != id {
+		t.Fatal("El ID no se propagó en el contexto")
+	}
+
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("ctx.log", tmp, acacia.Level.INFO)
+	fields := []acacia.Field{acacia.String("op", "charge")}
+	lg.InfoCtx(ctx, "texto", fields...)
+	lg.InfoCtx(context.Background(), "sin id")
+	lg.DebugCtx(ctx, "filtrado")
+	lg.Sync()
+	lg.StructuredJSON(true)
+	lg.WarnCtx(ctx, "json", fields...)
+	lg.Close()
+
+	if len(fields) != 1 {
+		t.Fatal("Los campos del llamador no deben modificarse")
+	}
+	lines := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "ctx.log"))), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Se esperaban 3 líneas, hay %d: %q", len(lines), lines)
+	}
+	if !strings.Contains(lines[0], "texto request_id="+id+" op=charge") {
+		t.Fatalf("Falta el ID en modo texto: %s", lines[0])
+	}
+	if strings.Contains(lines[1], "request_id") {
+		t.Fatalf("Sin ID en el contexto no debe haber campo: %s", lines[1])
+	}
+	var rec map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[2]), &rec); err != nil {
+		t.Fatalf("JSON inválido %q: %v", lines[2], err)
+	}
+	if rec[acacia.RequestIDKey] != id || rec["op"] != "charge" {
+		t.Fatalf("Falta el ID en JSON: %s", lines[2])
+	}
+}
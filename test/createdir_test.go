@@ -0,0 +1,30 @@
+package acacia_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestCreateDir(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "var", "log", "app")
+	if _, err := acacia.Start("app.log", dir, acacia.Level.INFO); err == nil {
+		t.Fatal("Sin WithCreateDir un directorio inexistente debe fallar")
+	}
+	lg, err := acacia.Start("app.log", dir, acacia.Level.INFO, acacia.WithCreateDir(0750))
+	if err != nil {
+		t.Fatalf("WithCreateDir no creó el directorio: %v", err)
+	}
+	lg.Info("primer arranque")
+	lg.Close()
+	info, err := os.Stat(dir)
+	if err != nil || info.Mode().Perm() != 0750 {
+		t.Fatalf("Permisos inesperados: %v %v", info.Mode().Perm(), err)
+	}
+	if !strings.Contains(readLog(t, filepath.Join(dir, "app.log")), "primer arranque") {
+		t.Fatal("No se escribió el registro")
+	}
+}
@@ -0,0 +1,106 @@
+package acacia_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestDuplicateSuppression(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("dedup.log", tmp, acacia.Level.INFO, acacia.WithDuplicateSuppression(time.Minute))
+	for i := 0; i < 5; i++ {
+		lg.Error("conexión rechazada")
+	}
+	for i := 0; i < 3; i++ {
+		lg.Error("reintento %d", 7)
+	}
+	lg.Warn("reintento %d", 7) // otro nivel: no es repetición
+	lg.Info("distinto")
+	lg.Info("distinto")
+	lg.Close()
+
+	lines := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "dedup.log"))), "\n")
+	want := []string{
+		"[ERROR] conexión rechazada",
+		"[ERROR] last message repeated 4 times",
+		"[ERROR] reintento 7",
+		"[ERROR] last message repeated 2 times",
+		"[WARN] reintento 7",
+		"[INFO] distinto",
+		"[INFO] last message repeated 1 times",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("Se esperaban %d líneas, obtenidas %d: %q", len(want), len(lines), lines)
+	}
+	for i, suffix := range want {
+		if !strings.HasSuffix(lines[i], suffix) {
+			t.Fatalf("Línea %d: se esperaba sufijo %q, obtenida %q", i, suffix, lines[i])
+		}
+	}
+}
+
+func TestDuplicateSuppressionJSON(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("dedup.json", tmp, acacia.Level.INFO, acacia.WithDuplicateSuppression(time.Minute))
+	lg.StructuredJSON(true)
+	for i := 0; i < 3; i++ {
+		lg.WarnFields("cola llena", acacia.Int("size", 10))
+	}
+	lg.WarnFields("cola llena", acacia.Int("size", 11))
+	lg.Sync()
+	lg.WarnFields("cola llena", acacia.Int("size", 11))
+	lg.Close()
+
+	lines := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "dedup.json"))), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Se esperaban 4 líneas, obtenidas %d: %q", len(lines), lines)
+	}
+	var summary map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[1]), &summary); err != nil {
+		t.Fatalf("JSON inválido %q: %v", lines[1], err)
+	}
+	if summary["repeated"] != float64(2) || summary["level"] != "WARN" {
+		t.Fatalf("Resumen inesperado: %v", summary)
+	}
+	if !strings.Contains(lines[2], `"size":11`) || !strings.Contains(lines[3], `"repeated":1`) {
+		t.Fatalf("Líneas inesperadas: %q", lines)
+	}
+}
+
+func TestDuplicateSuppressionIdentity(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("dedup.log", tmp, acacia.Level.INFO, acacia.WithDuplicateSuppression(time.Minute))
+	// distintos, pero con el mismo hash FNV-1a de 32 bits
+	lg.Error("pedido 233899 rechazado")
+	lg.Error("pedido 1022980 rechazado")
+	lg.Close()
+
+	log := readLog(t, filepath.Join(tmp, "dedup.log"))
+	if !strings.Contains(log, "pedido 1022980 rechazado") || strings.Contains(log, "repeated") {
+		t.Fatalf("Un registro distinto con el mismo hash fue suprimido:\n%s", log)
+	}
+
+	lg, _ = acacia.Start("dedup.json", tmp, acacia.Level.INFO, acacia.WithDuplicateSuppression(time.Minute))
+	lg.StructuredJSON(true)
+	n := 1
+	lg.Info(map[string]interface{}{"msg": "puntero", "n": &n})
+	n = 2
+	lg.Info(map[string]interface{}{"msg": "puntero", "n": &n})
+	for i := 0; i < 2; i++ {
+		lg.Info(map[string]interface{}{"msg": "lazy", "v": acacia.Lazy(func() interface{} { return "igual" })})
+	}
+	lg.Close()
+
+	lines := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "dedup.json"))), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Se esperaban 4 líneas, obtenidas %d: %q", len(lines), lines)
+	}
+	if !strings.Contains(lines[1], `"n":2`) || !strings.Contains(lines[3], `"repeated":1`) {
+		t.Fatalf("Los valores no se compararon por contenido: %q", lines)
+	}
+}
@@ -0,0 +1,31 @@
+package acacia_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestDefaultLogger(t *testing.T) {
+	acacia.Info("sin logger por defecto") // no debe fallar
+
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("default.log", tmp, acacia.Level.DEBUG)
+	acacia.SetDefault(lg)
+	defer acacia.SetDefault(nil)
+	if acacia.Default() != lg {
+		t.Fatalf("Default() no devolvió el logger configurado")
+	}
+	acacia.Info("hola %s", "mundo")
+	acacia.WarnFields("disco", acacia.Int("libre", 5))
+	acacia.Debug("detalle")
+	lg.Close()
+
+	lines := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "default.log"))), "\n")
+	if len(lines) != 3 || !strings.HasSuffix(lines[0], "[INFO] hola mundo") ||
+		!strings.HasSuffix(lines[1], "[WARN] disco libre=5") || !strings.HasSuffix(lines[2], "[DEBUG] detalle") {
+		t.Fatalf("Líneas inesperadas: %q", lines)
+	}
+}
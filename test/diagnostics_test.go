@@ -0,0 +1,87 @@
+package acacia_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestDumpState(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("diag.log", tmp, acacia.Level.INFO)
+	defer lg.Close()
+
+	lg.Info("antes del dump")
+	lg.Sync()
+
+	var buf bytes.Buffer
+	if err := lg.DumpState(&buf); err != nil {
+		t.Fatalf("DumpState falló: %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{"queue:", "buffers:", "pools", "startWriting"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("Falta %q en el dump:\n%s", want, out)
+		}
+	}
+}
+
+func TestWriteDiagnostics(t *testing.T) {
+	tmp := t.TempDir()
+	diag := filepath.Join(tmp, "custom.diag")
+	lg, _ := acacia.Start("diag.log", tmp, acacia.Level.INFO, acacia.WithDiagnosticsFile(diag))
+	defer lg.Close()
+
+	if err := lg.WriteDiagnostics(); err != nil {
+		t.Fatalf("WriteDiagnostics falló: %v", err)
+	}
+	data, err := os.ReadFile(diag)
+	if err != nil {
+		t.Fatalf("No se creó el archivo de diagnóstico: %v", err)
+	}
+	if !strings.Contains(string(data), "diagnostics at") {
+		t.Fatal("Contenido de diagnóstico inesperado")
+	}
+}
+
+func TestDumpStateWriterBusy(t *testing.T) {
+	tmp := t.TempDir()
+	release := make(chan struct{})
+	entered := make(chan struct{}, 1)
+	// el filtro corre en el writer con su mutex tomado: lo deja bloqueado
+	stall := func(e acacia.Entry) bool {
+		if e.Message == "bloquea" {
+			entered <- struct{}{}
+			<-release
+		}
+		return true
+	}
+	lg, _ := acacia.Start("diag.log", tmp, acacia.Level.INFO, acacia.WithFilter(stall))
+	defer lg.Close()
+	lg.Rotation(5, 3)
+	lg.Info("antes")
+	lg.Sync()
+	lg.Info("bloquea")
+	<-entered
+
+	done := make(chan string, 1)
+	go func() {
+		var buf bytes.Buffer
+		lg.DumpState(&buf)
+		done <- buf.String()
+	}()
+	select {
+	case out := <-done:
+		if !strings.Contains(out, "maxSize=5242880 maxRotation=3") || strings.Contains(out, "currentSize=0 ") {
+			t.Errorf("Estado de rotación inesperado:\n%s", out)
+		}
+	case <-time.After(2 * time.Second):
+		t.Error("DumpState esperó al writer ocupado")
+	}
+	close(release)
+}
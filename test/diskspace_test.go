@@ -0,0 +1,133 @@
+package acacia_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestDiskSpaceMonitorPurge(t *testing.T) {
+	switch runtime.GOOS {
+	case "linux", "darwin", "freebsd", "dragonfly":
+	default:
+		t.Skip("statfs no disponible")
+	}
+	tmp := t.TempDir()
+	// backups de una ejecución anterior
+	for _, name := range []string{"disk.log.0", "disk.log.1", "disk-2025-11-17.log"} {
+		if err := os.WriteFile(filepath.Join(tmp, name), []byte("viejo\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	other := filepath.Join(tmp, "other.log.0")
+	os.WriteFile(other, []byte("ajeno\n"), 0644)
+
+	// umbral inalcanzable: el volumen siempre está "lleno"
+	lg, _ := acacia.Start("disk.log", tmp, acacia.Level.INFO,
+		acacia.WithDiskSpaceMonitor(1<<62, 10*time.Millisecond, true))
+	defer lg.Close()
+	lg.Info("vivo")
+
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) && fileExists(t, filepath.Join(tmp, "disk.log.0")) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	for _, name := range []string{"disk.log.0", "disk.log.1", "disk-2025-11-17.log"} {
+		if fileExists(t, filepath.Join(tmp, name)) {
+			t.Fatalf("No se purgó %s", name)
+		}
+	}
+	if !fileExists(t, filepath.Join(tmp, "disk.log")) || !fileExists(t, other) {
+		t.Fatal("La purga solo debe borrar backups del log")
+	}
+
+	select {
+	case err := <-lg.Errors():
+		var ie *acacia.InternalError
+		if !errors.As(err, &ie) || !strings.Contains(ie.Msg, "low disk space") {
+			t.Fatalf("Aviso inesperado: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("No se avisó del disco lleno")
+	}
+}
+
+func TestDiskSpaceMonitorWriters(t *testing.T) {
+	switch runtime.GOOS {
+	case "linux", "darwin", "freebsd", "dragonfly":
+	default:
+		t.Skip("statfs no disponible")
+	}
+	tmp := t.TempDir()
+	old := filepath.Join(tmp, "disk.log.0.0")
+	os.WriteFile(old, []byte("viejo\n"), 0644)
+
+	lg, _ := acacia.Start("disk.log", tmp, acacia.Level.INFO, acacia.WithWriters(2),
+		acacia.WithDiskSpaceMonitor(1<<62, 10*time.Millisecond, true))
+	defer lg.Close()
+	for i := 0; i < 10; i++ {
+		lg.Info("vivo %d", i)
+	}
+
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) && fileExists(t, old) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if fileExists(t, old) {
+		t.Fatal("No se purgó el backup de la franja")
+	}
+	time.Sleep(100 * time.Millisecond)
+	for _, name := range []string{"disk.log.0", "disk.log.1"} {
+		if !fileExists(t, filepath.Join(tmp, name)) {
+			t.Fatalf("Se purgó la franja viva %s", name)
+		}
+	}
+
+	warnings := 0
+	for {
+		select {
+		case err := <-lg.Errors():
+			if strings.Contains(err.Error(), "low disk space on") {
+				warnings++
+			}
+			continue
+		default:
+		}
+		break
+	}
+	if warnings != 1 {
+		t.Fatalf("Se esperaba un aviso de disco lleno, obtenidos %d", warnings)
+	}
+}
+
+func TestDiskSpaceMonitorAuditKeepsBackups(t *testing.T) {
+	switch runtime.GOOS {
+	case "linux", "darwin", "freebsd", "dragonfly":
+	default:
+		t.Skip("statfs no disponible")
+	}
+	tmp := t.TempDir()
+	backup := filepath.Join(tmp, "audit.log.0")
+	os.WriteFile(backup, []byte("viejo\n"), 0644)
+
+	lg, _ := acacia.Start("audit.log", tmp, acacia.Level.INFO, acacia.AuditMode(nil),
+		acacia.WithDiskSpaceMonitor(1<<62, 10*time.Millisecond, true))
+	lg.Info("vivo")
+
+	select {
+	case <-lg.Errors():
+	case <-time.After(time.Second):
+		t.Fatal("No se avisó del disco lleno")
+	}
+	time.Sleep(100 * time.Millisecond)
+	lg.Close()
+	if !fileExists(t, backup) {
+		t.Fatal("AuditMode nunca borra backups")
+	}
+}
@@ -0,0 +1,58 @@
+package acacia_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestErrorsChannel(t *testing.T) {
+	tmp := t.TempDir()
+	lg, err := acacia.Start("errs.log", tmp, acacia.Level.INFO,
+		acacia.WithLevelFile("errs-error.log", acacia.Level.ERROR, 0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// el archivo de nivel deja de poder abrirse: su fallo debe llegar al canal
+	lg.Error("primero")
+	if err := lg.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	levelPath := filepath.Join(tmp, "errs-error.log")
+	os.Remove(levelPath)
+	os.Mkdir(levelPath, 0755)
+	lg.Reopen()
+	lg.Error("segundo")
+	lg.Sync()
+
+	select {
+	case err := <-lg.Errors():
+		var ie *acacia.InternalError
+		if !errors.As(err, &ie) || ie.Logger != "errs.log" || !strings.Contains(ie.Error(), "acacia: errs.log: ") {
+			t.Fatalf("Error inesperado: %#v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("No llegó ningún error al canal")
+	}
+
+	lg.Close()
+	for range lg.Errors() {
+	}
+}
+
+func TestErrorsChannelNonBlocking(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("full.log", tmp, acacia.Level.INFO)
+	if cap(lg.Errors()) != acacia.ErrorsBuffer {
+		t.Fatalf("Capacidad inesperada: %d", cap(lg.Errors()))
+	}
+	lg.Close()
+	if _, ok := <-lg.Errors(); ok {
+		t.Fatal("El canal debe cerrarse con Close")
+	}
+}
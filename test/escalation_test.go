@@ -0,0 +1,37 @@
+package acacia_test
+
+import (
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestErrorEscalation(t *testing.T) {
+	tmp := t.TempDir()
+	var calls int32
+	lg, _ := acacia.Start("esc.log", tmp, acacia.Level.INFO,
+		acacia.WithErrorEscalation(3, time.Minute, func(msg string, count int, window time.Duration) {
+			atomic.AddInt32(&calls, 1)
+		}))
+
+	for i := 0; i < 10; i++ {
+		lg.Error("db timeout for user %d", i)
+	}
+	lg.Error("otro error")
+	lg.Close()
+
+	content := readLog(t, filepath.Join(tmp, "esc.log"))
+	if n := strings.Count(content, "[CRITICAL]"); n != 1 {
+		t.Fatalf("Se esperaba un único CRITICAL de escalamiento, hay %d:\n%s", n, content)
+	}
+	if !strings.Contains(content, "db timeout for user %d") {
+		t.Fatal("El resumen no identifica el error repetido")
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Fatalf("Callback invocado %d veces, se esperaba 1", calls)
+	}
+}
@@ -0,0 +1,48 @@
+package acacia_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestControlCharEscaping(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "esc.log")
+	lg, err := acacia.Start("esc.log", tmp, acacia.Level.INFO, acacia.WithControlCharEscaping())
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged := "juan\n2025-11-18 10:00:00 [CRITICAL] admin borró todo\r"
+	lg.Info("login fallido para " + forged)
+	lg.Warn("usuario %s", forged)
+	lg.InfoFields("usuario "+forged, acacia.String("ip", "10.0.0.1\n"))
+	fmt.Fprintf(lg, "write \x1b[31mrojo\x7f \u0085 con\ttab\n")
+	lg.Error([]byte("bytes\nfalsos"))
+	lg.Info("sin nada raro, ñandú")
+	lg.Close()
+
+	content := readLog(t, path)
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("Cada registro debe ocupar una sola línea, hay %d:\n%s", len(lines), content)
+	}
+	for _, want := range []string{
+		`[INFO] login fallido para juan\n2025-11-18 10:00:00 [CRITICAL] admin borró todo\r`,
+		`[WARN] usuario juan\n2025-11-18`,
+		`[INFO] usuario juan\n2025-11-18 10:00:00 [CRITICAL] admin borró todo\r ip="10.0.0.1\n"`,
+		"[INFO] write \\x1b[31mrojo\\x7f \\u0085 con\ttab",
+		`[ERROR] bytes\nfalsos`,
+		`[INFO] sin nada raro, ñandú`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Fatalf("Falta %q en:\n%s", want, content)
+		}
+	}
+	if strings.Count(content, "[CRITICAL]") != 3 || strings.Contains(content, "\n2025-11-18 10:00:00 [CRITICAL]") {
+		t.Fatalf("No debe poder falsificarse una línea:\n%s", content)
+	}
+}
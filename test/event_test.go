@@ -0,0 +1,59 @@
+package acacia_test
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestEventBuilder(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "event.log")
+	lg, err := acacia.Start("event.log", tmp, acacia.Level.INFO)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg.StructuredJSON(true)
+
+	lg.InfoEvent().Str("user", "juan").Int("n", 3).Bool("ok", true).
+		Dur("took", 1500*time.Millisecond).Err(errors.New("boom")).Msg("login")
+	lg.WarnEvent().Msgf("disco al %d%%", 91)
+	// nivel desactivado: la cadena no hace nada
+	lg.DebugEvent().Str("user", "ana").Msg("oculto")
+	lg.Sync()
+
+	content := readLog(t, path)
+	for _, want := range []string{
+		`"level":"INFO","msg":"login","user":"juan","n":3,"ok":true,"took":"1.5s","error":"boom"`,
+		`"level":"WARN","msg":"disco al 91%"`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Fatalf("Falta %s en:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "oculto") {
+		t.Fatalf("Un nivel desactivado no debe escribirse:\n%s", content)
+	}
+
+	// el builder no suma reservas a las del writer (ni el slice de *Fields)
+	events := testing.AllocsPerRun(1000, func() {
+		lg.InfoEvent().Str("user", "juan").Int("n", 3).Msg("login")
+	})
+	fields := testing.AllocsPerRun(1000, func() {
+		lg.InfoFields("login", acacia.String("user", "juan"), acacia.Int("n", 3))
+	})
+	if events > fields {
+		t.Fatalf("InfoEvent reservó %.1f por llamada, InfoFields %.1f", events, fields)
+	}
+	disabled := testing.AllocsPerRun(1000, func() {
+		lg.DebugEvent().Str("user", "juan").Int("n", 3).Msg("login")
+	})
+	if disabled != 0 {
+		t.Fatalf("Un nivel desactivado no debería reservar memoria: %.1f", disabled)
+	}
+	lg.Close()
+}
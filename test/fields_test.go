@@ -0,0 +1,79 @@
+package acacia_test
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestFieldsJSON(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("fields.log", tmp, acacia.Level.INFO)
+	lg.StructuredJSON(true)
+	when := time.Date(2025, 11, 18, 10, 30, 0, 5, time.UTC)
+	tricky := "comillas \" barra \\ salto \n tab \t ctrl \x01 inválido \xff sep   ñ"
+	lg.InfoFields("hola",
+		acacia.String("s", tricky),
+		acacia.Int("i", -42),
+		acacia.Uint64("u", math.MaxUint64),
+		acacia.Float64("f", 1.5),
+		acacia.Float64("nan", math.NaN()),
+		acacia.Bool("b", true),
+		acacia.Duration("d", 1500*time.Millisecond),
+		acacia.Time("t", when),
+		acacia.Err(errors.New("falló")),
+		acacia.Any("any", []int{1, 2}),
+	)
+	lg.Close()
+
+	line := strings.TrimSpace(readLog(t, filepath.Join(tmp, "fields.log")))
+	var entry map[string]interface{}
+	d := json.NewDecoder(strings.NewReader(line))
+	d.UseNumber()
+	if err := d.Decode(&entry); err != nil {
+		t.Fatalf("JSON inválido %q: %v", line, err)
+	}
+	want := map[string]interface{}{
+		"level": "INFO",
+		"msg":   "hola",
+		"s":     strings.ToValidUTF8(tricky, "�"),
+		"i":     json.Number("-42"),
+		"u":     json.Number("18446744073709551615"),
+		"f":     json.Number("1.5"),
+		"nan":   "NaN",
+		"b":     true,
+		"d":     "1.5s",
+		"t":     "2025-11-18T10:30:00.000000005Z",
+		"error": "falló",
+	}
+	for k, v := range want {
+		if entry[k] != v {
+			t.Fatalf("Campo %q: esperado %#v, obtenido %#v", k, v, entry[k])
+		}
+	}
+	if any, ok := entry["any"].([]interface{}); !ok || len(any) != 2 {
+		t.Fatalf("Campo any inesperado: %#v", entry["any"])
+	}
+	if !strings.HasPrefix(line, `{"ts":`) {
+		t.Fatalf("La entrada debe empezar por ts: %q", line)
+	}
+}
+
+func TestFieldsText(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("fields.log", tmp, acacia.Level.INFO)
+	lg.InfoFields("pedido creado", acacia.String("user", "juan"), acacia.String("note", "dos palabras"), acacia.Int("items", 3))
+	lg.DebugFields("filtrado", acacia.Int("x", 1))
+	lg.Close()
+
+	line := strings.TrimSpace(readLog(t, filepath.Join(tmp, "fields.log")))
+	if !strings.HasSuffix(line, `[INFO] pedido creado user=juan note="dos palabras" items=3`) {
+		t.Fatalf("Línea inesperada: %q", line)
+	}
+}
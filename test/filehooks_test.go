@@ -0,0 +1,81 @@
+package acacia_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestFileHooks(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "hooks.log")
+	header := func(file, previous string) string {
+		if previous == "" {
+			return "# schema 3, " + filepath.Base(file)
+		}
+		return "# schema 3, follows " + filepath.Base(previous)
+	}
+	footer := func(file, next string) string {
+		return fmt.Sprintf("# %s continued in %s\n", filepath.Base(file), filepath.Base(next))
+	}
+	lg, err := acacia.Start("hooks.log", tmp, acacia.Level.INFO,
+		acacia.WithFileHeader(header), acacia.WithFileFooter(footer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg.Rotation(1, 5)
+	line := strings.Repeat("x", 1000)
+	for i := 0; i < 2500; i++ {
+		lg.Info(line)
+	}
+	lg.Close()
+
+	oldest := readLog(t, path+".1")
+	if !strings.HasPrefix(oldest, "# schema 3, hooks.log\n") || !strings.HasSuffix(oldest, "# hooks.log.0 continued in hooks.log\n") {
+		t.Fatalf("Cabecera o pie inesperados en el primer archivo: %q … %q", oldest[:40], oldest[len(oldest)-60:])
+	}
+	middle := readLog(t, path+".0")
+	if !strings.HasPrefix(middle, "# schema 3, follows hooks.log.0\n") || !strings.HasSuffix(middle, "# hooks.log.0 continued in hooks.log\n") {
+		t.Fatalf("Cabecera o pie inesperados en el respaldo: %q … %q", middle[:40], middle[len(middle)-60:])
+	}
+	current := readLog(t, path)
+	if !strings.HasPrefix(current, "# schema 3, follows hooks.log.0\n") || strings.Contains(current, "continued in") {
+		t.Fatalf("El archivo activo lleva cabecera y no pie: %q", current[:40])
+	}
+
+	// reabrir un archivo con contenido no repite la cabecera
+	lg, _ = acacia.Start("hooks.log", tmp, acacia.Level.INFO, acacia.WithFileHeader(header))
+	lg.Info("otra vez")
+	lg.Close()
+	if got := strings.Count(readLog(t, path), "# schema 3"); got != 1 {
+		t.Fatalf("La cabecera solo va en archivos nuevos, aparece %d veces", got)
+	}
+}
+
+func TestFileFooterWithPreallocate(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "hooks.log")
+	footer := func(file, next string) string {
+		return "# fin de " + filepath.Base(file) + "\n"
+	}
+	lg, err := acacia.Start("hooks.log", tmp, acacia.Level.INFO,
+		acacia.WithPreallocate(), acacia.WithFileFooter(footer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg.Rotation(1, 3)
+	line := strings.Repeat("x", 1000)
+	for i := 0; i < 2500; i++ {
+		lg.Info(line)
+	}
+	lg.Close()
+
+	for _, name := range []string{path + ".1", path + ".0"} {
+		if got := readLog(t, name); !strings.HasSuffix(got, "# fin de hooks.log.0\n") {
+			t.Fatalf("Liberar la reserva recortó el pie de %s: %q", filepath.Base(name), got[len(got)-60:])
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package acacia_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestFilters(t *testing.T) {
+	noHealth := func(e acacia.Entry) bool {
+		for _, f := range e.Fields {
+			if f.Key == "path" && f.Value() == "/healthz" {
+				return false
+			}
+		}
+		return !strings.HasPrefix(e.Message, "GET /healthz")
+	}
+	noDebugNoise := func(e acacia.Entry) bool {
+		return e.Level != acacia.Level.DEBUG || !strings.Contains(e.Message, "ruido")
+	}
+
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("filter.log", tmp, acacia.Level.DEBUG,
+		acacia.WithFilter(noHealth), acacia.WithFilter(noDebugNoise))
+	lg.Info("GET /healthz 200")
+	lg.InfoBytes([]byte("GET /healthz 200"))
+	lg.Info("GET %s %d", "/api", 200)
+	lg.Debug("ruido de %s", "fondo")
+	lg.InfoFields("request", acacia.String("path", "/healthz"))
+	lg.InfoFields("request", acacia.String("path", "/api"))
+	lg.StructuredJSON(true)
+	lg.Info(map[string]interface{}{"msg": "request", "path": "/healthz"})
+	lg.Info(map[string]interface{}{"msg": "request", "path": "/login"})
+	lg.Close()
+
+	lines := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "filter.log"))), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Se esperaban 3 líneas, obtenidas %d: %q", len(lines), lines)
+	}
+	if !strings.HasSuffix(lines[0], "GET /api 200") || !strings.HasSuffix(lines[1], "request path=/api") || !strings.Contains(lines[2], `"path":"/login"`) {
+		t.Fatalf("Líneas inesperadas: %q", lines)
+	}
+	if got := lg.Filtered(); got != 5 {
+		t.Fatalf("Filtered() = %d, se esperaba 5", got)
+	}
+}
@@ -0,0 +1,124 @@
+package acacia_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestForget(t *testing.T) {
+	src := strings.Join([]string{
+		`2025-11-18 10:00:00 [INFO] login user_id=42 ip=10.0.0.1`,
+		`2025-11-18 10:00:01 [ERROR] fallo user_id="42" detalle="a b"`,
+		`	goroutine 1 [running]:`,
+		`	main.main()`,
+		`2025-11-18 10:00:02 [INFO] login user_id=420`,
+		`{"ts":"2025-11-18T10:00:03Z","level":"INFO","msg":"compra","user_id":42}`,
+		`{"ts":"2025-11-18T10:00:04Z","level":"INFO","msg":"user_id 42","user_id":"7"}`,
+		`ts=2025-11-18T10:00:05Z level=info msg=salida user_id=42`,
+		``,
+	}, "\n")
+
+	var out strings.Builder
+	rep, err := acacia.Forget(strings.NewReader(src), &out, acacia.ForgetRule{Key: "user_id", Value: "42"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := out.String()
+	if strings.Count(got, "\n") != 2 || !strings.Contains(got, "user_id=420") || !strings.Contains(got, `"user_id":"7"`) {
+		t.Fatalf("Salida inesperada:\n%s", got)
+	}
+	if strings.Contains(got, "goroutine 1") {
+		t.Fatal("Las líneas de continuación deben irse con su registro")
+	}
+	if rep.Records != 6 || rep.Removed != 4 || len(rep.Lines) != 4 || rep.Lines[1] != 2 || rep.Lines[3] != 8 {
+		t.Fatalf("Informe inesperado: %+v", rep)
+	}
+
+	out.Reset()
+	rep, err = acacia.Forget(strings.NewReader(src), &out, acacia.ForgetRule{Key: "user_id", Value: "42", Mask: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = out.String()
+	for _, want := range []string{
+		"login user_id=[REDACTED] ip=10.0.0.1",
+		`fallo user_id=[REDACTED] detalle="a b"`,
+		`"user_id":"[REDACTED]"}`,
+		"msg=salida user_id=[REDACTED]",
+		"goroutine 1 [running]",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("Falta %q en:\n%s", want, got)
+		}
+	}
+	if rep.Masked != 4 || rep.Removed != 0 {
+		t.Fatalf("Informe inesperado: %+v", rep)
+	}
+}
+
+func TestForgetFile(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("gdpr.log", tmp, acacia.Level.INFO, acacia.WithGzipOutput())
+	lg.StructuredJSON(true)
+	lg.InfoFields("alta", acacia.String("user_id", "u-1"))
+	lg.InfoFields("alta", acacia.String("user_id", "u-2"))
+	lg.Close()
+
+	path := filepath.Join(tmp, "gdpr.log.gz")
+	rep, err := acacia.ForgetFile(path, path, acacia.ForgetRule{Key: "user_id", Value: "u-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Removed != 1 || rep.Output != path {
+		t.Fatalf("Informe inesperado: %+v", rep)
+	}
+	got := gunzip(t, path, false)
+	if strings.Contains(got, "u-1") || !strings.Contains(got, "u-2") {
+		t.Fatalf("Reescritura incorrecta: %q", got)
+	}
+	entries, _ := os.ReadDir(tmp)
+	if len(entries) != 1 {
+		t.Fatalf("Quedaron archivos temporales: %v", entries)
+	}
+}
+
+func TestForgetFileLive(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "vivo.log")
+	lg, _ := acacia.Start("vivo.log", tmp, acacia.Level.INFO)
+	lg.Info("alta user_id=u-1")
+	lg.Sync()
+
+	rule := acacia.ForgetRule{Key: "user_id", Value: "u-1"}
+	if _, err := acacia.ForgetFile(path, path, rule); !errors.Is(err, acacia.ErrLiveFile) {
+		t.Fatalf("Reescribir el archivo vivo debería fallar con ErrLiveFile: %v", err)
+	}
+
+	// apartarlo y reabrir, como logrotate: el respaldo sí se puede limpiar
+	aside := path + ".gdpr"
+	if err := os.Rename(path, aside); err != nil {
+		t.Fatal(err)
+	}
+	if err := lg.Reopen(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := acacia.ForgetFile(aside, aside, rule); err != nil {
+		t.Fatalf("ForgetFile sobre el archivo apartado: %v", err)
+	}
+	lg.Info("después")
+	lg.Close()
+	if got := readLog(t, path); !strings.Contains(got, "después") {
+		t.Fatalf("El logger debe seguir escribiendo en el archivo reabierto: %q", got)
+	}
+	if got := readLog(t, aside); strings.Contains(got, "u-1") {
+		t.Fatalf("El registro olvidado sigue en el respaldo: %q", got)
+	}
+	if _, err := acacia.ForgetFile(path, path, rule); err != nil {
+		t.Fatalf("Tras Close el archivo ya no está vivo: %v", err)
+	}
+}
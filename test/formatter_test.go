@@ -0,0 +1,53 @@
+package acacia_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestCustomFormatter(t *testing.T) {
+	csv := acacia.FormatterFunc(func(dst []byte, e acacia.Entry) []byte {
+		dst = append(dst, e.Level...)
+		dst = append(dst, ';')
+		dst = append(dst, e.Message...)
+		for _, f := range e.Fields {
+			dst = append(dst, fmt.Sprintf(";%s=%v", f.Key, f.Value())...)
+		}
+		return dst
+	})
+
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("custom.log", tmp, acacia.Level.INFO, acacia.WithFormatter(csv))
+	lg.Info("usuario %s conectado", "juan")
+	lg.Warn(map[string]interface{}{"msg": "disco lleno", "pct": 97, "mount": "/var"})
+	lg.ErrorFields("falló", acacia.String("op", "write"), acacia.Int("code", 5), acacia.Bool("retry", true))
+	lg.InfoBytes([]byte("bytes"))
+	lg.OutputFormat(acacia.Format.Text)
+	lg.Info("texto")
+	lg.OutputFormat(acacia.Format.Custom)
+	lg.Debug("filtrado")
+	lg.Close()
+
+	lines := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "custom.log"))), "\n")
+	want := []string{
+		"INFO;usuario juan conectado",
+		"WARN;disco lleno;mount=/var;pct=97",
+		"ERROR;falló;op=write;code=5;retry=true",
+		"INFO;bytes",
+	}
+	if len(lines) != len(want)+1 {
+		t.Fatalf("Se esperaban %d líneas, obtenidas %d: %q", len(want)+1, len(lines), lines)
+	}
+	for i, line := range want {
+		if lines[i] != line {
+			t.Fatalf("Línea %d: se esperaba %q, obtenida %q", i, line, lines[i])
+		}
+	}
+	if !strings.HasSuffix(lines[4], "[INFO] texto") {
+		t.Fatalf("Línea de texto inesperada: %q", lines[4])
+	}
+}
@@ -0,0 +1,52 @@
+package acacia_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestWithGKE(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("app.log", tmp, acacia.Level.DEBUG, acacia.WithGKE())
+	lg.UseUTC(true)
+	lg.Warn("disco casi lleno")
+	lg.ErrorFields("fallo", acacia.Int("pct", 97))
+	lg.Info(map[string]interface{}{"msg": "mapa", "user": "ana"})
+	lg.Close()
+
+	path := filepath.Join(tmp, "app.log")
+	lines := strings.Split(strings.TrimSpace(readLog(t, path)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Se esperaban 3 líneas: %q", lines)
+	}
+	want := []struct{ severity, message string }{{"WARNING", "disco casi lleno"}, {"ERROR", "fallo"}, {"INFO", "mapa"}}
+	for i, w := range want {
+		var m map[string]interface{}
+		if err := json.Unmarshal([]byte(lines[i]), &m); err != nil {
+			t.Fatalf("JSON inválido %q: %v", lines[i], err)
+		}
+		if m["severity"] != w.severity || m["message"] != w.message {
+			t.Fatalf("Línea %d inesperada: %s", i, lines[i])
+		}
+		ts, _ := m["timestamp"].(string)
+		if _, err := time.Parse(time.RFC3339Nano, ts); err != nil || !strings.HasSuffix(ts, "Z") {
+			t.Fatalf("timestamp no es RFC3339Nano en UTC: %q", ts)
+		}
+		if _, ok := m["ts"]; ok {
+			t.Fatalf("No debía emitirse la clave ts: %s", lines[i])
+		}
+	}
+	if !strings.HasPrefix(lines[1], `{"timestamp":"`) || !strings.Contains(lines[1], `"severity":"ERROR","message":"fallo","pct":97}`) {
+		t.Fatalf("Orden de miembros inesperado: %s", lines[1])
+	}
+
+	entries, err := acacia.ReadFile(path, acacia.Query{Level: acacia.Level.WARN})
+	if err != nil || len(entries) != 2 || entries[0].Level != acacia.Level.WARN || entries[0].Message != "disco casi lleno" || entries[0].Time.IsZero() {
+		t.Fatalf("ReadFile no interpretó el esquema GKE: %+v %v", entries, err)
+	}
+}
@@ -0,0 +1,64 @@
+package acacia_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestGoroutineIDText(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("gid.log", tmp, acacia.Level.INFO, acacia.WithGoroutineID())
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lg.Info("desde goroutine")
+			lg.InfoBytes([]byte("bytes"))
+		}()
+	}
+	wg.Wait()
+	lg.Close()
+
+	lines := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "gid.log"))), "\n")
+	if len(lines) != 8 {
+		t.Fatalf("Se esperaban 8 líneas, obtenidas %d: %q", len(lines), lines)
+	}
+	tag := regexp.MustCompile(`\[INFO\] \[g([1-9]\d*)\] `)
+	ids := map[string]bool{}
+	for _, line := range lines {
+		m := tag.FindStringSubmatch(line)
+		if m == nil {
+			t.Fatalf("Línea sin ID de goroutine: %q", line)
+		}
+		ids[m[1]] = true
+	}
+	if len(ids) != 4 {
+		t.Fatalf("Se esperaban 4 goroutines distintas, obtenidas %v", ids)
+	}
+}
+
+func TestGoroutineIDJSON(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("gid.json", tmp, acacia.Level.INFO, acacia.WithGoroutineID())
+	lg.StructuredJSON(true)
+	lg.InfoFields("tipado")
+	lg.Info("mapa")
+	lg.Close()
+
+	for _, line := range strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "gid.json"))), "\n") {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("JSON inválido %q: %v", line, err)
+		}
+		if g, ok := entry["goroutine"].(float64); !ok || g <= 0 {
+			t.Fatalf("Campo goroutine inválido en %q", line)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package acacia_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+// gunzip descomprime path; partial admite un stream sin final.
+func gunzip(t *testing.T, path string, partial bool) string {
+	t.Helper()
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("%s no es gzip: %v", path, err)
+	}
+	out, err := io.ReadAll(zr)
+	if err != nil && !(partial && err == io.ErrUnexpectedEOF) {
+		t.Fatalf("Error al descomprimir %s: %v", path, err)
+	}
+	return string(out)
+}
+
+func TestGzipOutput(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "app.log.gz")
+	lg, err := acacia.Start("app.log", tmp, acacia.Level.INFO, acacia.WithGzipOutput())
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg.Info("antes del flush point")
+	if err := lg.Sync(); err != nil {
+		t.Fatalf("Sync devolvió error: %v", err)
+	}
+	// archivo abierto: sin final, pero legible hasta el flush point
+	if got := gunzip(t, path, true); !strings.Contains(got, "antes del flush point") {
+		t.Fatalf("El flush point no dejó el registro legible: %q", got)
+	}
+	lg.Info("después")
+	if err := lg.Close(); err != nil {
+		t.Fatalf("Close devolvió error: %v", err)
+	}
+	got := gunzip(t, path, false)
+	if strings.Count(got, "\n") != 2 || !strings.Contains(got, "después") {
+		t.Fatalf("Contenido inesperado: %q", got)
+	}
+	if fileExists(t, filepath.Join(tmp, "app.log")) {
+		t.Fatal("No debe crearse el archivo sin comprimir")
+	}
+
+	// reabrir añade un miembro gzip nuevo
+	lg, _ = acacia.Start("app.log", tmp, acacia.Level.INFO, acacia.WithGzipOutput())
+	lg.Info("segunda ejecución")
+	lg.Close()
+	if got := gunzip(t, path, false); strings.Count(got, "\n") != 3 {
+		t.Fatalf("Se esperaban 3 líneas tras reabrir: %q", got)
+	}
+}
+
+func TestGzipOutputRotation(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("rot.log.gz", tmp, acacia.Level.INFO, acacia.WithGzipOutput())
+	lg.Rotation(1, 10)
+	for i := 0; i < 3000; i++ {
+		lg.Info(fmt.Sprintf("linea %d %s", i, strings.Repeat("z", 600)))
+	}
+	lg.Close()
+
+	if !fileExists(t, filepath.Join(tmp, "rot.log.gz.0")) {
+		t.Fatal("No se rotó el archivo comprimido")
+	}
+	total := 0
+	for _, name := range []string{"rot.log.gz.1", "rot.log.gz.0", "rot.log.gz"} {
+		if p := filepath.Join(tmp, name); fileExists(t, p) {
+			total += strings.Count(gunzip(t, p, false), "\n")
+		}
+	}
+	if total != 3000 {
+		t.Fatalf("Se esperaban 3000 líneas en total, hay %d", total)
+	}
+}
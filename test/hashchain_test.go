@@ -0,0 +1,55 @@
+package acacia_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestHashChain(t *testing.T) {
+	key := []byte("secreto")
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "audit.log")
+
+	lg, _ := acacia.Start("audit.log", tmp, acacia.Level.INFO, acacia.WithHashChain(key))
+	lg.Info("usuario juan creado")
+	lg.Warn("línea\nmultilínea")
+	lg.StructuredJSON(true)
+	lg.InfoFields("permiso otorgado", acacia.String("rol", "admin"))
+	lg.Close()
+
+	// reinicio: la cadena continúa desde el último MAC del archivo
+	lg, _ = acacia.Start("audit.log", tmp, acacia.Level.INFO, acacia.WithHashChain(key))
+	lg.Info("después del reinicio")
+	lg.Close()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(content, []byte(`,"hmac":"`)) || !bytes.Contains(content, []byte(" hmac=")) {
+		t.Fatalf("Faltan MACs:\n%s", content)
+	}
+	if _, err := acacia.VerifyHashChain(bytes.NewReader(content), key, nil); err != nil {
+		t.Fatalf("La cadena intacta debería verificar: %v", err)
+	}
+	if _, err := acacia.VerifyHashChain(bytes.NewReader(content), []byte("otra"), nil); !errors.Is(err, acacia.ErrChainBroken) {
+		t.Fatalf("Una clave distinta debería fallar, obtenido %v", err)
+	}
+
+	tampered := bytes.Replace(content, []byte("admin"), []byte("guest"), 1)
+	if _, err := acacia.VerifyHashChain(bytes.NewReader(tampered), key, nil); !errors.Is(err, acacia.ErrChainBroken) {
+		t.Fatalf("Una línea modificada debería romper la cadena, obtenido %v", err)
+	}
+
+	lines := strings.SplitAfter(string(content), "\n")
+	removed := strings.Join(append(lines[:1:1], lines[2:]...), "")
+	if _, err := acacia.VerifyHashChain(strings.NewReader(removed), key, nil); !errors.Is(err, acacia.ErrChainBroken) {
+		t.Fatalf("Una línea eliminada debería romper la cadena, obtenido %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package acacia_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestHeader(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "app.log")
+	header := acacia.WithHeader(acacia.HeaderConfig{App: "billing", Version: "1.4.2"})
+
+	for run := 0; run < 2; run++ {
+		lg, err := acacia.Start("app.log", tmp, acacia.Level.INFO, header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lg.Info("run %d", run)
+		lg.Close()
+	}
+	lines := strings.Split(strings.TrimSpace(readLog(t, path)), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Se esperaban banner y registro por arranque, hay %d líneas: %q", len(lines), lines)
+	}
+	banner := "=== billing 1.4.2 | host "
+	for _, i := range []int{0, 2} {
+		if !strings.HasPrefix(lines[i], banner) || !strings.Contains(lines[i], fmt.Sprintf("| pid %d | Logger Version: ", os.Getpid())) || !strings.HasSuffix(lines[i], " ===") {
+			t.Fatalf("Banner inesperado: %q", lines[i])
+		}
+	}
+
+	// JSON: el banner es una entrada más
+	jsonLog, _ := acacia.Start("json.log", tmp, acacia.Level.INFO, acacia.WithGKE(), header)
+	jsonLog.Close()
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(readLog(t, filepath.Join(tmp, "json.log")))), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["app"] != "billing" || entry["version"] != "1.4.2" || entry["logger_version"] == nil || !strings.HasPrefix(entry["message"].(string), "=== billing") {
+		t.Fatalf("Entrada de banner inesperada: %v", entry)
+	}
+
+	// apertura diferida: sin registros no hay archivo
+	lazy, _ := acacia.Start("lazy.log", tmp, acacia.Level.INFO, acacia.WithLazyOpen(), header)
+	lazy.Sync()
+	if fileExists(t, filepath.Join(tmp, "lazy.log")) {
+		t.Fatal("El banner no debe crear un archivo diferido")
+	}
+	lazy.Info("primero")
+	lazy.Close()
+	lines = strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "lazy.log"))), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "=== ") || !strings.HasSuffix(lines[1], "primero") {
+		t.Fatalf("El banner debe preceder al primer registro: %q", lines)
+	}
+}
+
+func TestHeaderFormatAfterStart(t *testing.T) {
+	tmp := t.TempDir()
+	lg, err := acacia.Start("app.log", tmp, acacia.Level.INFO, acacia.WithHeader(acacia.HeaderConfig{App: "billing"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg.StructuredJSON(true)
+	lg.Info("listo")
+	lg.Close()
+
+	entries := readJSONEntries(t, filepath.Join(tmp, "app.log"))
+	if len(entries) != 2 || entries[0]["app"] != "billing" || entries[1]["msg"] != "listo" {
+		t.Fatalf("El banner debe salir en JSON, seguido del registro: %v", entries)
+	}
+}
+
+func TestHeaderOnRotate(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "rot.log")
+	lg, err := acacia.Start("rot.log", tmp, acacia.Level.INFO, acacia.WithHeader(acacia.HeaderConfig{
+		Format:   func(h acacia.HeaderInfo) string { return fmt.Sprintf("# app=%s rotated=%t", h.App, h.Rotated) },
+		App:      "etl",
+		OnRotate: true,
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg.Rotation(1, 5)
+	line := strings.Repeat("x", 1000)
+	for i := 0; i < 4000; i++ {
+		lg.Info(line)
+	}
+	lg.Close()
+
+	first := strings.SplitN(readLog(t, path), "\n", 2)[0]
+	if first != "# app=etl rotated=true" {
+		t.Fatalf("El archivo nuevo debería empezar con el banner de rotación: %q", first)
+	}
+	oldest := strings.SplitN(readLog(t, path+".2"), "\n", 2)[0]
+	if oldest != "# app=etl rotated=false" {
+		t.Fatalf("El primer archivo debería empezar con el banner de arranque: %q", oldest)
+	}
+	for i := 0; i < 2; i++ {
+		content := readLog(t, fmt.Sprintf("%s.%d", path, i))
+		if !strings.HasPrefix(content, "# app=etl rotated=true\n") || strings.Count(content, "# app=") != 1 {
+			t.Fatalf("Respaldo %d: un solo banner al principio", i)
+		}
+		if info, _ := os.Stat(fmt.Sprintf("%s.%d", path, i)); info.Size() > 1024*1024 {
+			t.Fatalf("Respaldo %d supera el tamaño máximo: %d", i, info.Size())
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package acacia_test
+
+import (
+	"errors"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestHealthy(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("health.log", tmp, acacia.Level.INFO)
+
+	lg.Info("hola")
+	lg.Sync()
+	if err := lg.Healthy(); err != nil {
+		t.Fatalf("Se esperaba logger sano: %v", err)
+	}
+
+	lg.Close()
+	if err := lg.Healthy(); !errors.Is(err, acacia.ErrLoggerClosed) {
+		t.Fatalf("Se esperaba ErrLoggerClosed tras Close(), se obtuvo %v", err)
+	}
+}
@@ -0,0 +1,54 @@
+package acacia_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestDebugHex(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "hex.log")
+	lg, err := acacia.Start("hex.log", tmp, acacia.Level.DEBUG)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := []byte("GET / HTTP/1.1\r\nHost: x\r\n\x00\x01")
+	lg.DebugHex("request", payload)
+	lg.DebugHex("vacío", nil)
+	lg.Sync()
+
+	lines := strings.Split(strings.TrimSpace(readLog(t, path)), "\n")
+	want := []string{
+		"[DEBUG] request (27 bytes)",
+		"\t00000000  47 45 54 20 2f 20 48 54  54 50 2f 31 2e 31 0d 0a  |GET / HTTP/1.1..|",
+		"\t00000010  48 6f 73 74 3a 20 78 0d  0a 00 01                 |Host: x....|",
+		"[DEBUG] vacío (0 bytes)",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("Se esperaban %d líneas, hay %d: %q", len(want), len(lines), lines)
+	}
+	for i, w := range want {
+		if !strings.HasSuffix(lines[i], w) {
+			t.Fatalf("Línea %d: se esperaba %q, llegó %q", i, w, lines[i])
+		}
+	}
+
+	lg.StructuredJSON(true)
+	lg.DebugHex("request", payload)
+	lg.SetLevel(acacia.Level.INFO)
+	lg.DebugHex("oculto", payload)
+	lg.Close()
+
+	lines = strings.Split(strings.TrimSpace(readLog(t, path)), "\n")
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &entry); err != nil {
+		t.Fatalf("JSON inválido %q: %v", lines[len(lines)-1], err)
+	}
+	if entry["msg"] != "request" || entry["len"] != float64(27) || entry["base64"] != "R0VUIC8gSFRUUC8xLjENCkhvc3Q6IHgNCgAB" {
+		t.Fatalf("Entrada JSON inesperada: %v", entry)
+	}
+}
@@ -0,0 +1,84 @@
+package acacia_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+	"github.com/humanjuan/acacia/v2/httpdump"
+)
+
+func TestHTTPDump(t *testing.T) {
+	tmp := t.TempDir()
+	lg, err := acacia.Start("http.log", tmp, acacia.Level.INFO)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg.StructuredJSON(true)
+
+	req := httptest.NewRequest("POST", "https://api.example.com/v1/login?next=/home", strings.NewReader(`{"user":"juan","pass":"secreto"}`))
+	req.Header.Set("Authorization", "Bearer abc")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Api-Key", "k-123")
+	req.Header.Add("Accept", "text/plain")
+	req.Header.Add("Accept", "application/json")
+	lg.InfoFields("request", httpdump.Request(req, httpdump.Options{MaxBody: 12, Redact: []string{"x-api-key"}})...)
+
+	// el cuerpo sigue completo para el handler
+	body, _ := io.ReadAll(req.Body)
+	if string(body) != `{"user":"juan","pass":"secreto"}` {
+		t.Fatalf("El cuerpo debería seguir legible entero: %q", body)
+	}
+
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Set-Cookie", "session=xyz")
+	rec.WriteHeader(http.StatusCreated)
+	_, _ = rec.WriteString("ok")
+	resp := rec.Result()
+	resp.Request = req
+	lg.InfoFields("response", httpdump.Response(resp, httpdump.Options{MaxBody: 1024})...)
+	lg.Close()
+
+	lines := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "http.log"))), "\n")
+	var in, out map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &in); err != nil {
+		t.Fatalf("JSON inválido %q: %v", lines[0], err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &out); err != nil {
+		t.Fatalf("JSON inválido %q: %v", lines[1], err)
+	}
+	for k, v := range map[string]interface{}{
+		"method":               "POST",
+		"url":                  "https://api.example.com/v1/login?next=/home",
+		"host":                 "api.example.com",
+		"header.Authorization": acacia.DefaultRedactMask,
+		"header.X-Api-Key":     acacia.DefaultRedactMask,
+		"header.Accept":        "text/plain, application/json",
+		"header.Content-Type":  "application/json",
+		"body":                 `{"user":"jua`,
+		"body_truncated":       true,
+	} {
+		if in[k] != v {
+			t.Fatalf("Petición: %s = %v, se esperaba %v (%s)", k, in[k], v, lines[0])
+		}
+	}
+	for k, v := range map[string]interface{}{
+		"status_code":       float64(201),
+		"status":            "201 Created",
+		"method":            "POST",
+		"header.Set-Cookie": acacia.DefaultRedactMask,
+		"body":              "ok",
+	} {
+		if out[k] != v {
+			t.Fatalf("Respuesta: %s = %v, se esperaba %v (%s)", k, out[k], v, lines[1])
+		}
+	}
+	if _, ok := out["body_truncated"]; ok {
+		t.Fatalf("Un cuerpo corto no está truncado: %s", lines[1])
+	}
+}
@@ -0,0 +1,67 @@
+package acacia_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestIDGenerators(t *testing.T) {
+	ulid := acacia.NewULIDGenerator()
+	seen := make(map[string]bool)
+	prev := ""
+	for i := 0; i < 10000; i++ {
+		id := ulid()
+		if len(id) != 26 || seen[id] {
+			t.Fatalf("ULID inválido o repetido: %q", id)
+		}
+		if id <= prev {
+			t.Fatalf("ULID no monotónico: %q <= %q", id, prev)
+		}
+		seen[id] = true
+		prev = id
+	}
+
+	uuid := acacia.NewUUIDv7Generator()()
+	if !regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(uuid) {
+		t.Fatalf("UUIDv7 inválido: %s", uuid)
+	}
+
+	snow := acacia.NewSnowflakeGenerator(7)
+	a, b := snow(), snow()
+	if a == b {
+		t.Fatal("Snowflake repetido")
+	}
+}
+
+func TestIDOnRecords(t *testing.T) {
+	tmp := t.TempDir()
+	n := 0
+	gen := func() string { n++; return "req-" + string(rune('0'+n)) }
+	lg, _ := acacia.Start("ids.log", tmp, acacia.Level.INFO, acacia.WithIDGenerator(gen))
+
+	lg.Info("texto plano")
+	lg.Sync()
+	lg.StructuredJSON(true)
+	lg.Info(map[string]interface{}{"event": "login"})
+	lg.Close()
+
+	lines := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "ids.log"))), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Se esperaban 2 líneas, hay %d", len(lines))
+	}
+	if !strings.Contains(lines[0], "[INFO] [req-1] texto plano") {
+		t.Fatalf("Falta el ID en modo texto: %s", lines[0])
+	}
+	var rec map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[1]), &rec); err != nil {
+		t.Fatalf("JSON inválido: %v", err)
+	}
+	if rec["id"] != "req-2" {
+		t.Fatalf("Falta el ID en modo JSON: %s", lines[1])
+	}
+}
@@ -0,0 +1,36 @@
+package acacia_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestInternedKeys(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("keys.log", tmp, acacia.Level.INFO, acacia.WithoutTimestamp())
+	lg.OutputFormat(acacia.Format.Logfmt)
+	// claves dinámicas con el mismo largo: cada una debe codificarse con su contenido
+	for i := 0; i < 3; i++ {
+		key := fmt.Sprintf("k%d", i)
+		lg.InfoFields("dyn", acacia.Int(key, i), acacia.String("a b", "x"))
+	}
+	lg.Sync()
+	lg.StructuredJSON(true)
+	lg.InfoFields("json", acacia.String(`com"illa`, "v"), acacia.String(`com"illa`, "w"))
+	lg.Close()
+
+	lines := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "keys.log"))), "\n")
+	want := []string{
+		`level=info msg=dyn k0=0 a_b=x`,
+		`level=info msg=dyn k1=1 a_b=x`,
+		`level=info msg=dyn k2=2 a_b=x`,
+		`{"level":"INFO","msg":"json","com\"illa":"v","com\"illa":"w"}`,
+	}
+	if fmt.Sprint(lines) != fmt.Sprint(want) {
+		t.Fatalf("Claves mal codificadas:\n%s", strings.Join(lines, "\n"))
+	}
+}
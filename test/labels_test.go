@@ -0,0 +1,34 @@
+package acacia_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestLevelLabels(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("labels.log", tmp, acacia.Level.DEBUG, acacia.WithoutTimestamp(),
+		acacia.WithLevelLabels(map[string]string{acacia.Level.WARN: "WARNING", acacia.Level.ERROR: "FEHLER", "NOPE": "x"}),
+		acacia.WithLevelPadding())
+	lg.Debug("depurar")
+	lg.Info("info %d", 1)
+	lg.Warn("aviso")
+	lg.ErrorFields("fallo", acacia.Int("n", 2))
+	lg.Critical("grave")
+	lg.Close()
+
+	got := readLog(t, filepath.Join(tmp, "labels.log"))
+	want := strings.Join([]string{
+		"[DEBUG]    depurar",
+		"[INFO]     info 1",
+		"[WARNING]  aviso",
+		"[FEHLER]   fallo n=2",
+		"[CRITICAL] grave",
+	}, "\n") + "\n"
+	if got != want {
+		t.Fatalf("Etiquetas inesperadas:\n%s\nse esperaba:\n%s", got, want)
+	}
+}
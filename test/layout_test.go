@@ -0,0 +1,41 @@
+package acacia_test
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestLayout(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("layout.log", tmp, acacia.Level.INFO,
+		acacia.WithLayout("{ts:15:04:05} [{level}] {logger} {msg} {fields}"))
+	lg.Info("hola %s", "mundo")
+	lg.WarnFields("lento", acacia.String("op", "read file"), acacia.Int("ms", 250))
+	lg.Close()
+
+	lines := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "layout.log"))), "\n")
+	want := []string{
+		`^\d\d:\d\d:\d\d \[INFO\] layout\.log hola mundo$`,
+		`^\d\d:\d\d:\d\d \[WARN\] layout\.log lento op="read file" ms=250$`,
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("Se esperaban %d líneas, obtenidas %d: %q", len(want), len(lines), lines)
+	}
+	for i, pattern := range want {
+		if !regexp.MustCompile(pattern).MatchString(lines[i]) {
+			t.Fatalf("Línea %d %q no coincide con %s", i, lines[i], pattern)
+		}
+	}
+}
+
+func TestParseLayoutErrors(t *testing.T) {
+	for _, layout := range []string{"{ts} {nivel}", "{msg", "{msg:x}"} {
+		if _, err := acacia.ParseLayout(layout); err == nil {
+			t.Fatalf("ParseLayout(%q) debería fallar", layout)
+		}
+	}
+}
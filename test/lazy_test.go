@@ -0,0 +1,59 @@
+package acacia_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestLazy(t *testing.T) {
+	calls := 0
+	costly := func(v interface{}) *acacia.LazyValue {
+		return acacia.Lazy(func() interface{} {
+			calls++
+			return v
+		})
+	}
+
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("lazy.log", tmp, acacia.Level.INFO)
+	lg.Debug("estado: %v", costly("oculto"))
+	lg.DebugFields("estado", acacia.Any("x", costly(1)))
+	if calls != 0 {
+		t.Fatalf("Lazy se evaluó %d veces con el nivel desactivado", calls)
+	}
+	lg.Info("estado: %v, pi=%5.2f", costly("listo"), costly(3.14159))
+	lg.InfoFields("campos", acacia.Any("n", costly(42)))
+	lg.StructuredJSON(true)
+	lg.Info(map[string]interface{}{"msg": "mapa", "dump": costly(map[string]int{"a": 1})})
+	// sin esperar a Close: los valores del mapa se resuelven al registrar
+	if calls != 4 {
+		t.Fatalf("Lazy se evaluó %d veces, se esperaban 4", calls)
+	}
+	lg.Close()
+
+	lines := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "lazy.log"))), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Se esperaban 3 líneas, obtenidas %d: %q", len(lines), lines)
+	}
+	if !strings.HasSuffix(lines[0], "estado: listo, pi= 3.14") || !strings.HasSuffix(lines[1], "campos n=42") || !strings.Contains(lines[2], `"dump":{"a":1}`) {
+		t.Fatalf("Líneas inesperadas: %q", lines)
+	}
+}
+
+func TestEnabled(t *testing.T) {
+	lg, _ := acacia.Start("enabled.log", t.TempDir(), acacia.Level.WARN)
+	defer lg.Close()
+	if lg.DebugEnabled() || lg.Enabled(acacia.Level.INFO) || !lg.Enabled("warn") || !lg.Enabled(acacia.Level.CRITICAL) {
+		t.Fatalf("Enabled no respeta el nivel WARN")
+	}
+	if lg.Enabled("verbose") {
+		t.Fatalf("Un nivel desconocido no debía estar habilitado")
+	}
+	lg.SetLevel(acacia.Level.DEBUG)
+	if !lg.DebugEnabled() {
+		t.Fatalf("DebugEnabled debía seguir a SetLevel")
+	}
+}
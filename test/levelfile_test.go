@@ -0,0 +1,50 @@
+package acacia_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestLevelFile(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("app.log", tmp, acacia.Level.DEBUG,
+		acacia.WithLevelFile("app.error.log", acacia.Level.ERROR, 0, 1))
+	lg.Debug("depurando")
+	lg.Info("inicio")
+	lg.Error("falló %s", "db")
+	lg.CriticalFields("caída", acacia.Int("code", 7))
+	lg.Warn("aviso")
+	lg.Close()
+
+	all := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "app.log"))), "\n")
+	if len(all) != 5 {
+		t.Fatalf("Se esperaban 5 líneas en app.log, obtenidas %d: %q", len(all), all)
+	}
+	errs := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "app.error.log"))), "\n")
+	if len(errs) != 2 || !strings.HasSuffix(errs[0], "[ERROR] falló db") || !strings.HasSuffix(errs[1], "[CRITICAL] caída code=7") {
+		t.Fatalf("Contenido inesperado en app.error.log: %q", errs)
+	}
+}
+
+func TestLevelFileRotation(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("app.log", tmp, acacia.Level.INFO,
+		acacia.WithLevelFile("app.error.log", acacia.Level.ERROR, 1, 2))
+	chunk := strings.Repeat("x", 100*1024)
+	for i := 0; i < 15; i++ {
+		lg.Error(chunk)
+		lg.Sync()
+	}
+	lg.Close()
+
+	if _, err := os.Stat(filepath.Join(tmp, "app.error.log.0")); err != nil {
+		t.Fatalf("Se esperaba la rotación del archivo de nivel: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "app.log.0")); err == nil {
+		t.Fatalf("El archivo principal no debía rotar")
+	}
+}
@@ -0,0 +1,34 @@
+package acacia_test
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestDefaultLogDir(t *testing.T) {
+	if runtime.GOOS == "windows" || runtime.GOOS == "darwin" {
+		t.Skip("rutas XDG solo en Linux y otros Unix")
+	}
+	state := t.TempDir()
+	setenv(t, "XDG_STATE_HOME", state)
+	dir, err := acacia.DefaultLogDir("mycli")
+	if err != nil || dir != filepath.Join(state, "mycli") {
+		t.Fatalf("Directorio inesperado con XDG_STATE_HOME: %q %v", dir, err)
+	}
+
+	// una ruta relativa no es válida según la especificación XDG
+	home := t.TempDir()
+	setenv(t, "XDG_STATE_HOME", "relativo")
+	setenv(t, "HOME", home)
+	dir, err = acacia.DefaultLogDir("mycli")
+	if err != nil || dir != filepath.Join(home, ".local", "state", "mycli") {
+		t.Fatalf("Directorio por defecto inesperado: %q %v", dir, err)
+	}
+
+	if _, err := acacia.DefaultLogDir(""); err == nil {
+		t.Fatal("Un nombre vacío debe fallar")
+	}
+}
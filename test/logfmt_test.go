@@ -0,0 +1,39 @@
+package acacia_test
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestLogfmtOutput(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("logfmt.log", tmp, acacia.Level.INFO)
+	lg.TimestampFormat(acacia.TS.RFC3339)
+	lg.OutputFormat(acacia.Format.Logfmt)
+	lg.Info("usuario %s conectado", "juan")
+	lg.Warn(map[string]interface{}{"msg": "disco lleno", "pct": 97, "mount": "/var lib", "ok": false})
+	lg.ErrorFields("falló", acacia.String("op", "write"), acacia.Int("code", 5))
+	lg.OutputFormat(acacia.Format.Text)
+	lg.Info("texto")
+	lg.Close()
+
+	lines := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "logfmt.log"))), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Se esperaban 4 líneas, obtenidas %d: %q", len(lines), lines)
+	}
+	want := []string{
+		`^ts=\S+ level=info msg="usuario juan conectado"$`,
+		`^ts=\S+ level=warn msg="disco lleno" mount="/var lib" ok=false pct=97$`,
+		`^ts=\S+ level=error msg=falló op=write code=5$`,
+		`^\S+ \[INFO\] texto$`,
+	}
+	for i, pattern := range want {
+		if !regexp.MustCompile(pattern).MatchString(lines[i]) {
+			t.Fatalf("Línea %d %q no coincide con %s", i, lines[i], pattern)
+		}
+	}
+}
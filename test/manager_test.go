@@ -0,0 +1,70 @@
+package acacia_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestManager(t *testing.T) {
+	tmp := t.TempDir()
+	conf := writeConfig(t, "acacia.json", fmt.Sprintf(`{
+	  "loggers": [
+	    {"name": "access", "file": "access.log", "path": %q},
+	    {"name": "app", "file": "app.log", "path": %q, "level": "debug", "default": true},
+	    {"name": "audit", "file": "audit.log", "path": %q, "format": "json", "utc": true}
+	  ]
+	}`, tmp, tmp, tmp))
+
+	m, err := acacia.StartManager(conf)
+	if err != nil {
+		t.Fatalf("StartManager falló: %v", err)
+	}
+	if got := strings.Join(m.Names(), ","); got != "access,app,audit" {
+		t.Fatalf("Names() = %q", got)
+	}
+	if acacia.Get("app") != m.Get("app") || acacia.Default() != m.Get("app") {
+		t.Fatal("Los loggers del manager deben quedar registrados")
+	}
+	// más de un tick de la caché compartida
+	time.Sleep(250 * time.Millisecond)
+	m.Get("access").Info("GET /health 200")
+	acacia.Debug("detalle")
+	m.Get("audit").Warn("acceso")
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close falló: %v", err)
+	}
+	if err := m.Close(); err != nil {
+		t.Fatalf("Un segundo Close debe devolver lo mismo: %v", err)
+	}
+	if acacia.Get("app") != nil || acacia.Default() != nil {
+		t.Fatal("Close debe quitar los loggers del registro")
+	}
+
+	year := fmt.Sprint(time.Now().Year())
+	for file, want := range map[string]string{
+		"access.log": "[INFO] GET /health 200",
+		"app.log":    "[DEBUG] detalle",
+		"audit.log":  `"msg":"acceso"`,
+	} {
+		got := readLog(t, filepath.Join(tmp, file))
+		if !strings.Contains(got, want) || !strings.Contains(got, year) {
+			t.Fatalf("%s inesperado: %q", file, got)
+		}
+	}
+}
+
+func TestNewManagerError(t *testing.T) {
+	tmp := t.TempDir()
+	_, err := acacia.NewManager(acacia.Config{Loggers: []acacia.LoggerConfig{
+		{File: "a.log", Path: tmp},
+		{File: "b.log", Path: tmp, Level: "verbose"},
+	}})
+	if err == nil || acacia.Get("a.log") != nil {
+		t.Fatalf("Se esperaba un error sin registrar nada, obtenido %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package acacia_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestProcessMetadata(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("meta.log", tmp, acacia.Level.INFO, acacia.WithProcessMetadata("billing", "1.4.2"))
+	lg.StructuredJSON(true)
+	lg.Info("mapa")
+	lg.InfoFields("tipado", acacia.String("op", "charge"))
+	lg.Info(map[string]interface{}{"msg": "propio", "service": "override"})
+	lg.Close()
+
+	host, _ := os.Hostname()
+	lines := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "meta.log"))), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Se esperaban 3 líneas, obtenidas %d: %q", len(lines), lines)
+	}
+	for i, line := range lines {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("JSON inválido %q: %v", line, err)
+		}
+		service := "billing"
+		if i == 2 {
+			service = "override"
+		}
+		if entry["host"] != host || entry["pid"] != float64(os.Getpid()) || entry["service"] != service || entry["version"] != "1.4.2" {
+			t.Fatalf("Metadatos inesperados en %q", line)
+		}
+	}
+}
+
+func TestKubernetesMetadata(t *testing.T) {
+	setenv(t, "KUBERNETES_SERVICE_HOST", "")
+	setenv(t, "POD_NAME", "billing-7d9f-x2k4")
+	setenv(t, "POD_NAMESPACE", "")
+	setenv(t, "NAMESPACE", "payments")
+	setenv(t, "NODE_NAME", "node-3")
+	setenv(t, "POD_IP", "")
+	setenv(t, "CONTAINER_NAME", "")
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("k8s.log", tmp, acacia.Level.INFO, acacia.WithKubernetesMetadata())
+	lg.StructuredJSON(true)
+	lg.InfoFields("listo")
+	lg.Close()
+
+	var entry map[string]interface{}
+	line := strings.TrimSpace(readLog(t, filepath.Join(tmp, "k8s.log")))
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		t.Fatalf("JSON inválido %q: %v", line, err)
+	}
+	if entry["k8s.pod"] != "billing-7d9f-x2k4" || entry["k8s.namespace"] != "payments" || entry["k8s.node"] != "node-3" {
+		t.Fatalf("Metadatos del pod inesperados en %q", line)
+	}
+	if _, ok := entry["k8s.pod_ip"]; ok {
+		t.Fatalf("Las variables vacías no deben generar campos: %q", line)
+	}
+}
+
+// setenv fija una variable durante el test y restaura su valor al terminar.
+func setenv(t *testing.T, key, value string) {
+	old, had := os.LookupEnv(key)
+	os.Setenv(key, value)
+	t.Cleanup(func() {
+		if had {
+			os.Setenv(key, old)
+		} else {
+			os.Unsetenv(key)
+		}
+	})
+}
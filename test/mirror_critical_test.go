@@ -0,0 +1,48 @@
+package acacia_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestCriticalStderrMirror(t *testing.T) {
+	tmp := t.TempDir()
+	errFile, err := os.Create(filepath.Join(tmp, "stderr.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	orig := os.Stderr
+	os.Stderr = errFile
+	defer func() { os.Stderr = orig }()
+
+	lg, _ := acacia.Start("crit.log", tmp, acacia.Level.INFO, acacia.WithCriticalStderr(2, time.Minute))
+	os.Stderr = orig
+
+	for i := 0; i < 5; i++ {
+		lg.Critical("disco lleno")
+	}
+	lg.Critical("base de datos caida")
+	lg.Critical("red caida")
+	lg.Error("no se replica")
+	lg.Close()
+	errFile.Close()
+
+	mirrored := readLog(t, errFile.Name())
+	if n := strings.Count(mirrored, "[CRITICAL]"); n != 2 {
+		t.Fatalf("Se esperaban 2 líneas en stderr (límite), hay %d:\n%s", n, mirrored)
+	}
+	if !strings.Contains(mirrored, "4 similar suppressed") {
+		t.Fatalf("Falta el conteo de duplicados suprimidos:\n%s", mirrored)
+	}
+	if strings.Contains(mirrored, "no se replica") {
+		t.Fatal("Sólo CRITICAL debe replicarse a stderr")
+	}
+	if n := strings.Count(readLog(t, filepath.Join(tmp, "crit.log")), "[CRITICAL]"); n != 7 {
+		t.Fatalf("El archivo debe conservar todos los CRITICAL, hay %d", n)
+	}
+}
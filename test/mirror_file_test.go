@@ -0,0 +1,44 @@
+package acacia_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestMirrorFile(t *testing.T) {
+	tmp, other := t.TempDir(), t.TempDir()
+	lg, _ := acacia.Start("app.log", tmp, acacia.Level.INFO,
+		acacia.WithMirrorFile(filepath.Join(other, "copia.log")))
+	lg.Info("uno")
+	lg.ErrorFields("dos", acacia.Int("n", 2))
+	lg.Close()
+
+	primary := readLog(t, filepath.Join(tmp, "app.log"))
+	mirror := readLog(t, filepath.Join(other, "copia.log"))
+	if primary != mirror || strings.Count(primary, "\n") != 2 {
+		t.Fatalf("El espejo no coincide:\nprincipal=%q\nespejo=%q", primary, mirror)
+	}
+	if lg.MirrorDropped() != 0 {
+		t.Fatalf("MirrorDropped() = %d, se esperaba 0", lg.MirrorDropped())
+	}
+}
+
+func TestMirrorFileUnavailable(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("app.log", tmp, acacia.Level.INFO,
+		acacia.WithMirrorFile(filepath.Join(tmp, "no-existe", "copia.log")))
+	lg.Info("uno")
+	lg.Sync()
+	lg.Info("dos")
+	lg.Close()
+
+	if got := readLog(t, filepath.Join(tmp, "app.log")); strings.Count(got, "\n") != 2 {
+		t.Fatalf("El archivo principal debía tener 2 líneas: %q", got)
+	}
+	if lg.MirrorDropped() != 2 {
+		t.Fatalf("MirrorDropped() = %d, se esperaba 2", lg.MirrorDropped())
+	}
+}
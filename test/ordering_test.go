@@ -0,0 +1,59 @@
+package acacia_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/humanjuan/acacia/v2"
+)
+
+// Cada productor mezcla fast-path, mensajes formateados y []byte: sus líneas
+// deben aparecer en el mismo orden en que se registraron.
+func TestPerProducerOrdering(t *testing.T) {
+	tmp := t.TempDir()
+	lg, err := acacia.Start("order.log", tmp, acacia.Level.DEBUG)
+	if err != nil {
+		t.Fatalf("Start falló: %v", err)
+	}
+
+	const producers, perProducer = 4, 300
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				switch i % 3 {
+				case 0:
+					lg.Info(fmt.Sprintf("p%d-%d", p, i))
+				case 1:
+					lg.Info("p%d-%d", p, i)
+				default:
+					lg.Info([]byte(fmt.Sprintf("p%d-%d", p, i)))
+				}
+			}
+		}(p)
+	}
+	wg.Wait()
+	lg.Close()
+
+	next := make([]int, producers)
+	for _, line := range strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "order.log"))), "\n") {
+		var p, i int
+		if _, err := fmt.Sscanf(line[strings.LastIndexByte(line, ' ')+1:], "p%d-%d", &p, &i); err != nil {
+			t.Fatalf("Línea inesperada %q: %v", line, err)
+		}
+		if i != next[p] {
+			t.Fatalf("Productor %d fuera de orden: esperado %d, obtenido %d", p, next[p], i)
+		}
+		next[p]++
+	}
+	for p, n := range next {
+		if n != perProducer {
+			t.Fatalf("Productor %d: esperadas %d líneas, obtenidas %d", p, perProducer, n)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package acacia_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestPreallocateKeepsApparentSize(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("prealloc.log", tmp, acacia.Level.INFO, acacia.WithPreallocate())
+	lg.Rotation(1, 2)
+
+	lg.Info("linea corta")
+	lg.Sync()
+
+	base := filepath.Join(tmp, "prealloc.log")
+	content := readLog(t, base)
+	info, err := os.Stat(base)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != int64(len(content)) {
+		t.Fatalf("La reserva alteró el tamaño aparente: %d != %d", info.Size(), len(content))
+	}
+
+	big := strings.Repeat("P", 600*1024)
+	lg.Info(big)
+	lg.Info(big)
+	lg.Close()
+	if !fileExists(t, base+".0") {
+		t.Fatal("La rotación por tamaño dejó de funcionar con reserva")
+	}
+	if backup, _ := os.Stat(base + ".0"); backup.Size() > 1024*1024 {
+		t.Fatalf("Backup excede el tamaño de rotación: %d", backup.Size())
+	}
+}
@@ -0,0 +1,79 @@
+package acacia_test
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestPrettyOutput(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("pretty.log", tmp, acacia.Level.INFO)
+	lg.OutputFormat(acacia.Format.Pretty)
+	lg.Warn(map[string]interface{}{"msg": "disco lleno", "pct": 97})
+	lg.InfoFields("arranque", acacia.String("stack", "línea 1\nlínea 2"), acacia.Int("pid", 42))
+	lg.Error("sin campos")
+	lg.Close()
+
+	out := readLog(t, filepath.Join(tmp, "pretty.log"))
+	if strings.Contains(out, "\x1b[") {
+		t.Fatalf("No debe haber colores fuera de una terminal: %q", out)
+	}
+	lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+	want := []string{
+		`^\d\d:\d\d:\d\d\.\d{3} WARN  disco lleno {29} pct=97$`,
+		`^\d\d:\d\d:\d\d\.\d{3} INFO  arranque {32} pid=42$`,
+		`^ {13}stack:$`,
+		`^ {13}│ línea 1$`,
+		`^ {13}│ línea 2$`,
+		`^\d\d:\d\d:\d\d\.\d{3} ERROR sin campos$`,
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("Se esperaban %d líneas, obtenidas %d:\n%s", len(want), len(lines), out)
+	}
+	for i, pattern := range want {
+		if !regexp.MustCompile(pattern).MatchString(lines[i]) {
+			t.Fatalf("Línea %d %q no coincide con %s", i, lines[i], pattern)
+		}
+	}
+}
+
+func TestPrettyColorEnv(t *testing.T) {
+	run := func(force, noColor string, console bool) string {
+		setenv(t, "FORCE_COLOR", force)
+		setenv(t, "NO_COLOR", noColor)
+		tmp := t.TempDir()
+		path := filepath.Join(tmp, "color.log")
+		var lg *acacia.Log
+		if console {
+			out, err := os.Create(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer out.Close()
+			lg, _ = acacia.StartWriter(out, acacia.Level.INFO)
+		} else {
+			lg, _ = acacia.Start("color.log", tmp, acacia.Level.INFO)
+		}
+		lg.OutputFormat(acacia.Format.Pretty)
+		lg.Warn("aviso")
+		lg.Close()
+		return readLog(t, path)
+	}
+	if out := run("1", "", true); !strings.Contains(out, "\x1b[33mWARN") {
+		t.Fatalf("FORCE_COLOR debe colorear la consola: %q", out)
+	}
+	if out := run("1", "1", true); strings.Contains(out, "\x1b[") {
+		t.Fatalf("NO_COLOR prevalece sobre FORCE_COLOR: %q", out)
+	}
+	if out := run("0", "", true); strings.Contains(out, "\x1b[") {
+		t.Fatalf("FORCE_COLOR=0 desactiva los colores: %q", out)
+	}
+	if out := run("1", "", false); strings.Contains(out, "\x1b[") {
+		t.Fatalf("FORCE_COLOR no se aplica a archivos de log: %q", out)
+	}
+}
@@ -0,0 +1,38 @@
+package acacia_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+// printer es la interfaz mínima que esperan muchas librerías.
+type printer interface {
+	Print(v ...interface{})
+	Printf(format string, v ...interface{})
+	Println(v ...interface{})
+}
+
+func TestPrintMethods(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("print.log", tmp, acacia.Level.INFO)
+	var p printer = lg
+	p.Print("reintento ", 3, " de ", 5)
+	p.Printf("espera %dms", 250)
+	p.Println("petición", "completada", 200)
+	p.Print("100% listo")
+	lg.Close()
+
+	want := []string{"[INFO] reintento 3 de 5", "[INFO] espera 250ms", "[INFO] petición completada 200", "[INFO] 100% listo"}
+	lines := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "print.log"))), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("Se esperaban %d líneas, obtenidas %d: %q", len(want), len(lines), lines)
+	}
+	for i, w := range want {
+		if !strings.HasSuffix(lines[i], w) {
+			t.Fatalf("Línea %d = %q, se esperaba sufijo %q", i, lines[i], w)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package acacia_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestByteQuota(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("quota.log", tmp, acacia.Level.INFO, acacia.WithByteQuota(200, time.Minute))
+	for i := 0; i < 20; i++ {
+		lg.Info("ruido %d", i)
+	}
+	lg.Error("fallo real")
+	lg.Close()
+
+	data := readLog(t, filepath.Join(tmp, "quota.log"))
+	if n := strings.Count(data, "byte quota of 200 bytes per 1m0s exceeded"); n != 1 {
+		t.Fatalf("Se esperaba un aviso, obtenidos %d:\n%s", n, data)
+	}
+	if !strings.Contains(data, "[ERROR] fallo real") {
+		t.Fatalf("Los errores deben pasar sobre la cuota:\n%s", data)
+	}
+	if strings.Contains(data, "ruido 19") {
+		t.Fatalf("Se escribió por encima de la cuota:\n%s", data)
+	}
+	written := strings.Count(data, "ruido")
+	if written == 0 || uint64(written)+lg.OverQuota() != 20 {
+		t.Fatalf("Escritos %d, OverQuota() = %d; se esperaban 20 en total", written, lg.OverQuota())
+	}
+}
+
+func TestByteQuotaWindowReset(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("quota.log", tmp, acacia.Level.INFO, acacia.WithByteQuota(1, 50*time.Millisecond))
+	lg.OutputFormat(acacia.Format.JSON)
+	lg.Info("primero")
+	lg.Info("descartado")
+	lg.Sync()
+	time.Sleep(80 * time.Millisecond)
+	lg.Info("segundo")
+	lg.Close()
+
+	data := readLog(t, filepath.Join(tmp, "quota.log"))
+	if strings.Contains(data, "descartado") || !strings.Contains(data, "segundo") {
+		t.Fatalf("La ventana nueva debe reabrir la cuota:\n%s", data)
+	}
+	if n := strings.Count(data, `"quota_bytes":1`); n != 2 {
+		t.Fatalf("Se esperaba un aviso por ventana, obtenidos %d:\n%s", n, data)
+	}
+}
@@ -0,0 +1,81 @@
+package acacia_test
+
+import (
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestReadFileMixedFormats(t *testing.T) {
+	tmp := t.TempDir()
+	n := 0
+	lg, _ := acacia.Start("app.log", tmp, acacia.Level.DEBUG,
+		acacia.WithIDGenerator(func() string { n++; return "id" + strconv.Itoa(n) }))
+	lg.TimestampFormat(acacia.TS.RFC3339Nano)
+	start := time.Now().Add(-time.Second)
+	lg.Debug("detalle")
+	lg.Warn("disco al %d%%", 91)
+	lg.Error("fallo\n\tat main.go:12")
+	lg.OutputFormat(acacia.Format.JSON)
+	lg.ErrorFields("consulta lenta", acacia.String("op", "read"), acacia.Int("ms", 250), acacia.Float64("ratio", 0.5))
+	lg.Close()
+
+	path := filepath.Join(tmp, "app.log")
+	all, err := acacia.ReadFile(path, acacia.Query{})
+	if err != nil {
+		t.Fatalf("ReadFile falló: %v", err)
+	}
+	if len(all) != 4 {
+		t.Fatalf("Se esperaban 4 entradas, obtenidas %d: %+v", len(all), all)
+	}
+	if all[0].Level != acacia.Level.DEBUG || all[0].Message != "detalle" || all[0].ID != "id1" || all[0].Logger != "app.log" {
+		t.Fatalf("Entrada de texto inesperada: %+v", all[0])
+	}
+	if d := all[0].Time.Sub(start); d < 0 || d > time.Minute {
+		t.Fatalf("Timestamp no interpretado: %v", all[0].Time)
+	}
+	if all[2].Message != "fallo\n\tat main.go:12" {
+		t.Fatalf("Línea de continuación perdida: %q", all[2].Message)
+	}
+	js := all[3]
+	if js.Message != "consulta lenta" || js.ID != "id4" || len(js.Fields) != 3 ||
+		js.Fields[0].Value() != "read" || js.Fields[1].Value() != int64(250) || js.Fields[2].Value() != 0.5 {
+		t.Fatalf("Entrada JSON inesperada: %+v", js)
+	}
+
+	errs, _ := acacia.ReadFile(path, acacia.Query{Level: acacia.Level.ERROR})
+	if len(errs) != 2 || errs[0].Level != acacia.Level.ERROR || errs[1].Level != acacia.Level.ERROR {
+		t.Fatalf("Filtro por nivel incorrecto: %+v", errs)
+	}
+	none, _ := acacia.ReadFile(path, acacia.Query{Until: start})
+	later, _ := acacia.ReadFile(path, acacia.Query{Since: start, Until: time.Now().Add(time.Minute)})
+	if len(none) != 0 || len(later) != 4 {
+		t.Fatalf("Filtro por tiempo incorrecto: %d %d", len(none), len(later))
+	}
+}
+
+func TestReaderStream(t *testing.T) {
+	in := "Jan 2, 2006 15:04:05.000000 UTC [INFO] [g7] hola [mundo]\n" +
+		`{"ts":1136214245000,"level":"warn","msg":"epoch","tags":["a"]}` + "\n" +
+		"basura sin cabecera\n"
+	rd := acacia.NewReader(strings.NewReader(in), acacia.Query{})
+	e, err := rd.Next()
+	if err != nil || e.Message != "hola [mundo]" || e.Time.Year() != 2006 || len(e.Fields) != 1 || e.Fields[0].Value() != int64(7) {
+		t.Fatalf("Entrada inesperada: %+v %v", e, err)
+	}
+	e, err = rd.Next()
+	if err != nil || e.Level != acacia.Level.WARN || !e.Time.Equal(time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)) {
+		t.Fatalf("Entrada JSON inesperada: %+v %v", e, err)
+	}
+	if e.Message != "epoch\nbasura sin cabecera" {
+		t.Fatalf("Continuación inesperada: %q", e.Message)
+	}
+	if _, err := rd.Next(); err != io.EOF {
+		t.Fatalf("Se esperaba io.EOF, obtenido %v", err)
+	}
+}
@@ -0,0 +1,55 @@
+package acacia_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestDumpRecent(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("app.log", tmp, acacia.Level.ERROR,
+		acacia.WithRecentBuffer(3),
+		acacia.WithRedaction(acacia.RedactConfig{Keys: []string{"password"}}))
+	defer lg.Close()
+	lg.OutputFormat(acacia.Format.JSON)
+
+	for i := 0; i < 5; i++ {
+		lg.Debug("paso %d", i)
+	}
+	lg.InfoFields("login", acacia.String("user", "ana"), acacia.String("password", "hunter2"))
+	lg.Error("fallo")
+
+	var out bytes.Buffer
+	if err := lg.DumpRecent(&out); err != nil {
+		t.Fatalf("DumpRecent falló: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Se esperaban 3 entradas recientes, obtenidas %d: %q", len(lines), lines)
+	}
+	want := []string{"[DEBUG] paso 4", "[INFO] login user=ana password=[REDACTED]", "[ERROR] fallo"}
+	for i, w := range want {
+		if !strings.HasSuffix(lines[i], w) {
+			t.Fatalf("Línea %d: se esperaba sufijo %q, obtenido %q", i, w, lines[i])
+		}
+	}
+
+	lg.Sync()
+	if got := readLog(t, filepath.Join(tmp, "app.log")); strings.Contains(got, "paso") || strings.Count(got, "\n") != 1 {
+		t.Fatalf("El archivo solo debía contener el ERROR: %q", got)
+	}
+}
+
+func TestDumpRecentDisabled(t *testing.T) {
+	lg, _ := acacia.Start("app.log", t.TempDir(), acacia.Level.DEBUG)
+	defer lg.Close()
+	lg.Info("hola")
+	var out bytes.Buffer
+	if err := lg.DumpRecent(&out); err != nil || out.Len() != 0 {
+		t.Fatalf("Sin WithRecentBuffer no debía escribir nada: %q %v", out.String(), err)
+	}
+}
@@ -0,0 +1,45 @@
+package acacia_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestPartialLineRecovery(t *testing.T) {
+	for _, lazy := range []bool{false, true} {
+		tmp := t.TempDir()
+		path := filepath.Join(tmp, "crash.log")
+		if err := os.WriteFile(path, []byte("completa\n{\"msg\":\"cort"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		opts := []acacia.Option{acacia.WithPartialLineRecovery()}
+		if lazy {
+			opts = append(opts, acacia.WithLazyOpen())
+		}
+		lg, err := acacia.Start("crash.log", tmp, acacia.Level.INFO, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lg.Info("nueva")
+		lg.Close()
+
+		lines := strings.Split(readLog(t, path), "\n")
+		if len(lines) != 4 || lines[1] != `{"msg":"cort`+acacia.PartialLineMarker || !strings.HasSuffix(lines[2], "[INFO] nueva") {
+			t.Fatalf("Recuperación inesperada (lazy=%v): %q", lazy, lines)
+		}
+	}
+
+	// un archivo íntegro no se toca
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "ok.log")
+	os.WriteFile(path, []byte("completa\n"), 0644)
+	lg, _ := acacia.Start("ok.log", tmp, acacia.Level.INFO, acacia.WithPartialLineRecovery())
+	lg.Close()
+	if got := readLog(t, path); got != "completa\n" {
+		t.Fatalf("Un archivo íntegro no debe modificarse: %q", got)
+	}
+}
@@ -0,0 +1,38 @@
+package acacia_test
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestRedaction(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("redact.log", tmp, acacia.Level.INFO, acacia.WithRedaction(acacia.RedactConfig{
+		Keys:     []string{"password", "Token"},
+		Patterns: []*regexp.Regexp{regexp.MustCompile(`\d{3}-\d{2}-\d{4}`)},
+	}))
+	lg.Info("ssn 123-45-6789 recibido")
+	lg.InfoBytes([]byte("bytes 987-65-4321"))
+	lg.StructuredJSON(true)
+	creds := map[string]interface{}{"msg": "login", "user": "juan", "password": "hunter2", "meta": map[string]interface{}{"token": "abc"}}
+	lg.Info(creds)
+	lg.InfoFields("tipado", acacia.String("TOKEN", "xyz"), acacia.String("ssn", "111-22-3333"))
+	lg.Close()
+
+	content := readLog(t, filepath.Join(tmp, "redact.log"))
+	for _, secret := range []string{"123-45-6789", "987-65-4321", "hunter2", "abc", "xyz", "111-22-3333"} {
+		if strings.Contains(content, secret) {
+			t.Fatalf("El secreto %q llegó al log:\n%s", secret, content)
+		}
+	}
+	if strings.Count(content, acacia.DefaultRedactMask) != 6 || !strings.Contains(content, `"user":"juan"`) {
+		t.Fatalf("Redacción inesperada:\n%s", content)
+	}
+	if creds["password"] != "hunter2" {
+		t.Fatal("El mapa del llamador no debe modificarse")
+	}
+}
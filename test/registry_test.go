@@ -0,0 +1,34 @@
+package acacia_test
+
+import (
+	"sync"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestRegistry(t *testing.T) {
+	tmp := t.TempDir()
+	db, _ := acacia.Start("db.log", tmp, acacia.Level.INFO)
+	defer db.Close()
+
+	if acacia.Get("db") != nil {
+		t.Fatalf("Get debía devolver nil para un nombre no registrado")
+	}
+	acacia.Register("db", db)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if acacia.Get("db") != db {
+				t.Errorf("Get no devolvió el logger registrado")
+			}
+		}()
+	}
+	wg.Wait()
+	acacia.Register("db", nil)
+	if acacia.Get("db") != nil {
+		t.Fatalf("Register(nil) debía eliminar el nombre")
+	}
+}
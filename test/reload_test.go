@@ -0,0 +1,67 @@
+package acacia_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestWatchConfig(t *testing.T) {
+	tmp := t.TempDir()
+	conf := filepath.Join(tmp, "acacia.json")
+	write := func(level string, sizeMB int) {
+		c := fmt.Sprintf(`{"loggers": [{"name": "recarga", "file": "recarga.log", "path": %q, "level": %q,
+		  "rotation": {"size_mb": %d, "backups": 2}, "sampling": {"window": "1s", "first": 1, "thereafter": 0}}]}`, tmp, level, sizeMB)
+		if err := os.WriteFile(conf, []byte(c), 0644); err != nil {
+			t.Fatalf("No se pudo escribir la configuración: %v", err)
+		}
+	}
+	write("error", 1)
+	logs, err := acacia.StartFromConfig(conf)
+	if err != nil {
+		t.Fatalf("StartFromConfig falló: %v", err)
+	}
+	lg := logs["recarga"]
+	defer acacia.Register("recarga", nil)
+
+	stop := acacia.WatchConfig(conf, 10*time.Millisecond)
+	defer stop()
+	lg.Info("antes")
+	lg.Error("repetido")
+	lg.Error("repetido")
+
+	write("debug", 10)
+	deadline := time.Now().Add(3 * time.Second)
+	for lg.LogLevel() != acacia.Level.DEBUG {
+		if time.Now().After(deadline) {
+			t.Fatalf("La configuración no se recargó: nivel %s", lg.LogLevel())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	lg.Debug("después")
+	lg.Close()
+
+	got := readLog(t, filepath.Join(tmp, "recarga.log"))
+	if strings.Contains(got, "antes") || strings.Count(got, "repetido") != 1 {
+		t.Fatalf("Contenido previo a la recarga inesperado: %q", got)
+	}
+	if !strings.Contains(got, "[INFO] configuration reloaded config="+conf+" level=DEBUG") || !strings.HasSuffix(strings.TrimSpace(got), "[DEBUG] después") {
+		t.Fatalf("Contenido posterior a la recarga inesperado: %q", got)
+	}
+}
+
+func TestReloadConfigInvalid(t *testing.T) {
+	tmp := t.TempDir()
+	conf := filepath.Join(tmp, "acacia.json")
+	if err := os.WriteFile(conf, []byte(`{"loggers": [{"file": "x.log", "level": "verbose"}]}`), 0644); err != nil {
+		t.Fatalf("No se pudo escribir la configuración: %v", err)
+	}
+	if err := acacia.ReloadConfig(conf); err == nil {
+		t.Fatalf("Se esperaba un error por nivel inválido")
+	}
+}
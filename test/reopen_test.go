@@ -0,0 +1,44 @@
+package acacia_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestReopen(t *testing.T) {
+	tmp := t.TempDir()
+	base := filepath.Join(tmp, "app.log")
+	lg, _ := acacia.Start("app.log", tmp, acacia.Level.INFO,
+		acacia.WithLevelFile("app.error.log", acacia.Level.ERROR, 0, 1))
+	lg.Error("antes")
+	lg.Sync()
+
+	// logrotate con `create`: mueve los archivos y espera que el proceso los reabra
+	if err := os.Rename(base, base+".1"); err != nil {
+		t.Fatalf("No se pudo mover el archivo: %v", err)
+	}
+	if err := os.Rename(filepath.Join(tmp, "app.error.log"), filepath.Join(tmp, "app.error.log.1")); err != nil {
+		t.Fatalf("No se pudo mover el archivo de nivel: %v", err)
+	}
+	lg.Error("en tránsito")
+	if err := lg.Reopen(); err != nil {
+		t.Fatalf("Reopen() = %v", err)
+	}
+	lg.Error("después")
+	lg.Close()
+
+	old := readLog(t, base+".1")
+	if !strings.Contains(old, "antes") || !strings.Contains(old, "en tránsito") {
+		t.Fatalf("El archivo movido debía conservar lo anterior a Reopen: %q", old)
+	}
+	if got := strings.TrimSpace(readLog(t, base)); !strings.HasSuffix(got, "[ERROR] después") || strings.Contains(got, "antes") {
+		t.Fatalf("Archivo reabierto inesperado: %q", got)
+	}
+	if got := strings.TrimSpace(readLog(t, filepath.Join(tmp, "app.error.log"))); !strings.HasSuffix(got, "[ERROR] después") || strings.Contains(got, "tránsito") {
+		t.Fatalf("Archivo de nivel reabierto inesperado: %q", got)
+	}
+}
@@ -0,0 +1,90 @@
+package acacia_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+// flakyWriter simula un destino remoto que puede caerse.
+type flakyWriter struct {
+	mtx  sync.Mutex
+	down bool
+	buf  bytes.Buffer
+}
+
+func (w *flakyWriter) Write(p []byte) (int, error) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if w.down {
+		return 0, errors.New("connection refused")
+	}
+	return w.buf.Write(p)
+}
+
+func (w *flakyWriter) set(down bool) {
+	w.mtx.Lock()
+	w.down = down
+	w.mtx.Unlock()
+}
+
+func (w *flakyWriter) lines() []string {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return strings.Split(strings.TrimSpace(w.buf.String()), "\n")
+}
+
+func TestRetrySpool(t *testing.T) {
+	spool := filepath.Join(t.TempDir(), "ship.spool")
+	w := &flakyWriter{}
+	lg, _ := acacia.StartWriter(w, acacia.Level.INFO, acacia.WithoutTimestamp(), acacia.WithRetrySpool(spool, 1<<20))
+	lg.Info("antes")
+	lg.Sync()
+	w.set(true)
+	for i := 0; i < 3; i++ {
+		lg.Info("caído %d", i)
+		lg.Sync()
+	}
+	if !fileExists(t, spool) || len(w.lines()) != 1 {
+		t.Fatalf("Durante el corte los lotes deben ir al spool: %q", w.lines())
+	}
+	w.set(false)
+	lg.Info("después")
+	lg.Close()
+
+	want := []string{"[INFO] antes", "[INFO] caído 0", "[INFO] caído 1", "[INFO] caído 2", "[INFO] después"}
+	if got := w.lines(); fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Fatalf("Orden inesperado tras la reconexión: %q", got)
+	}
+	if fileExists(t, spool) {
+		t.Fatal("El spool vacío debe borrarse al cerrar")
+	}
+}
+
+func TestRetrySpoolPersistsAndBounds(t *testing.T) {
+	spool := filepath.Join(t.TempDir(), "ship.spool")
+	w := &flakyWriter{down: true}
+	lg, _ := acacia.StartWriter(w, acacia.Level.INFO, acacia.WithoutTimestamp(), acacia.WithRetrySpool(spool, 64))
+	lg.Info("guardado")
+	lg.Sync()
+	lg.Info(strings.Repeat("x", 100))
+	lg.Close()
+	if lg.SpoolDropped() != 1 {
+		t.Fatalf("El lote que no entra debe descartarse, descartados %d", lg.SpoolDropped())
+	}
+
+	// otra ejecución con el destino disponible entrega lo pendiente primero
+	w.set(false)
+	lg, _ = acacia.StartWriter(w, acacia.Level.INFO, acacia.WithoutTimestamp(), acacia.WithRetrySpool(spool, 64))
+	lg.Info("nuevo")
+	lg.Close()
+	if got := w.lines(); fmt.Sprint(got) != fmt.Sprint([]string{"[INFO] guardado", "[INFO] nuevo"}) {
+		t.Fatalf("El spool debe repetirse al arrancar: %q", got)
+	}
+}
@@ -0,0 +1,38 @@
+package acacia_test
+
+import (
+	"runtime"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func heapInUse() uint64 {
+	runtime.GC()
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return m.HeapAlloc
+}
+
+func TestQueueAllocatesOnDemand(t *testing.T) {
+	tmp := t.TempDir()
+	before := heapInUse()
+	lg, err := acacia.Start("ring.log", tmp, acacia.Level.INFO, acacia.WithBufferSize(acacia.DefaultBufferSize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lg.Close()
+	// la cola de 500.000 eventos no se reserva entera al arrancar
+	if grown := int64(heapInUse()) - int64(before); grown > 4<<20 {
+		t.Fatalf("Start reservó %d KB de heap", grown>>10)
+	}
+	for i := 0; i < 10_000; i++ {
+		lg.Info("relleno %d", i)
+	}
+	if err := lg.Sync(); err != nil {
+		t.Fatalf("Sync devolvió error: %v", err)
+	}
+	if grown := int64(heapInUse()) - int64(before); grown > 8<<20 {
+		t.Fatalf("La cola vacía sigue ocupando %d KB de heap", grown>>10)
+	}
+}
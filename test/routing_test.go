@@ -0,0 +1,83 @@
+package acacia_test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+	"github.com/humanjuan/acacia/v2/binlog"
+)
+
+func TestWithRouting(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("app.log", tmp, acacia.Level.INFO,
+		acacia.WithRouting("tenant", "tenant-{value}.log", 0, 1),
+		acacia.WithLevelFile("app.error.log", acacia.Level.ERROR, 0, 1))
+	lg.InfoFields("pedido", acacia.String("tenant", "acme"), acacia.Int("n", 1))
+	lg.InfoFields("pedido", acacia.String("tenant", "globex"), acacia.Int("n", 2))
+	lg.ErrorFields("fallo", acacia.String("tenant", "acme"))
+	lg.InfoFields("escape", acacia.String("tenant", "../x"))
+	lg.Info("sin tenant")
+	lg.StructuredJSON(true)
+	lg.Info(map[string]interface{}{"msg": "mapa", "tenant": "globex"})
+	lg.Close()
+
+	acme := readLog(t, filepath.Join(tmp, "tenant-acme.log"))
+	if strings.Count(acme, "\n") != 2 || !strings.Contains(acme, "pedido tenant=acme n=1") || !strings.Contains(acme, "[ERROR] fallo") {
+		t.Fatalf("tenant-acme.log inesperado: %q", acme)
+	}
+	globex := readLog(t, filepath.Join(tmp, "tenant-globex.log"))
+	if !strings.Contains(globex, "n=2") || !strings.Contains(globex, `"msg":"mapa"`) {
+		t.Fatalf("tenant-globex.log inesperado: %q", globex)
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "tenant-.._x.log")); err != nil {
+		t.Fatalf("El valor debía sanearse para el nombre de archivo: %v", err)
+	}
+	if main := readLog(t, filepath.Join(tmp, "app.log")); strings.TrimSpace(main) == "" || strings.Contains(main, "tenant=") || strings.Contains(main, "mapa") || !strings.Contains(main, "sin tenant") {
+		t.Fatalf("app.log inesperado: %q", main)
+	}
+	if errs := readLog(t, filepath.Join(tmp, "app.error.log")); !strings.Contains(errs, "fallo tenant=acme") {
+		t.Fatalf("El archivo de nivel debía recibir la copia: %q", errs)
+	}
+}
+
+func TestWithRoutingBinary(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("app.bin", tmp, acacia.Level.INFO, acacia.WithRouting("tenant", "{value}.bin", 0, 1))
+	lg.OutputFormat(acacia.Format.Binary)
+	lg.Info("uno")
+	lg.InfoFields("dos", acacia.String("tenant", "acme"))
+	lg.Info("tres")
+	lg.Close()
+
+	for name, want := range map[string][]string{"app.bin": {"uno", "tres"}, "acme.bin": {"dos"}} {
+		f, err := os.Open(filepath.Join(tmp, name))
+		if err != nil {
+			t.Fatalf("Falta %s: %v", name, err)
+		}
+		rd := binlog.NewReader(f)
+		var got []string
+		var last int64
+		for {
+			e, err := rd.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("%s ilegible: %v", name, err)
+			}
+			if e.Time.UnixNano() < last {
+				t.Fatalf("%s: timestamps no monótonos", name)
+			}
+			last = e.Time.UnixNano()
+			got = append(got, e.Message)
+		}
+		f.Close()
+		if strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Fatalf("%s: se esperaba %v, obtenido %v", name, want, got)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package acacia_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestSampling(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("sampling.log", tmp, acacia.Level.INFO, acacia.WithSampling(time.Minute, 3, 10))
+	for i := 0; i < 25; i++ {
+		lg.Warn("reintento %d fallido", i)
+	}
+	lg.Info("único")
+	lg.Error("reintento %d fallido", 0) // otro nivel, otro presupuesto
+	lg.Close()
+
+	lines := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "sampling.log"))), "\n")
+	want := []string{
+		"[WARN] reintento 0 fallido",
+		"[WARN] reintento 1 fallido",
+		"[WARN] reintento 2 fallido",
+		"[WARN] reintento 12 fallido",
+		"[WARN] reintento 22 fallido",
+		"[INFO] único",
+		"[ERROR] reintento 0 fallido",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("Se esperaban %d líneas, obtenidas %d: %q", len(want), len(lines), lines)
+	}
+	for i, suffix := range want {
+		if !strings.HasSuffix(lines[i], suffix) {
+			t.Fatalf("Línea %d: se esperaba sufijo %q, obtenida %q", i, suffix, lines[i])
+		}
+	}
+	if got := lg.Sampled(); got != 20 {
+		t.Fatalf("Sampled() = %d, se esperaba 20", got)
+	}
+}
+
+func TestSamplingWindowReset(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("sampling.log", tmp, acacia.Level.INFO, acacia.WithSampling(50*time.Millisecond, 1, 0))
+	lg.Info("tormenta")
+	lg.Info("tormenta")
+	time.Sleep(80 * time.Millisecond)
+	lg.Info("tormenta")
+	lg.Close()
+
+	if n := strings.Count(readLog(t, filepath.Join(tmp, "sampling.log")), "tormenta"); n != 2 {
+		t.Fatalf("Se esperaban 2 líneas tras reiniciar la ventana, obtenidas %d", n)
+	}
+}
@@ -0,0 +1,70 @@
+package acacia_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestWithSeverity(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("app.log", tmp, acacia.Level.DEBUG, acacia.WithSeverity(acacia.Severity.Syslog))
+	lg.StructuredJSON(true)
+	lg.Warn("disco casi lleno")
+	lg.ErrorFields("fallo", acacia.Int("pct", 97))
+	lg.Debug(map[string]interface{}{"msg": "mapa"})
+	lg.Close()
+
+	lines := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "app.log"))), "\n")
+	want := []float64{4, 3, 7}
+	if len(lines) != len(want) {
+		t.Fatalf("Se esperaban %d líneas: %q", len(want), lines)
+	}
+	for i, sev := range want {
+		var m map[string]interface{}
+		if err := json.Unmarshal([]byte(lines[i]), &m); err != nil {
+			t.Fatalf("JSON inválido %q: %v", lines[i], err)
+		}
+		if m["severity"] != sev {
+			t.Fatalf("Línea %d: severity %v, se esperaba %v: %s", i, m["severity"], sev, lines[i])
+		}
+	}
+}
+
+func TestWithSeverityOTelAndGKE(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("gke.log", tmp, acacia.Level.INFO, acacia.WithGKE(), acacia.WithSeverity(acacia.Severity.OTel))
+	lg.Critical("caída")
+	lg.Close()
+
+	var m map[string]interface{}
+	line := strings.TrimSpace(readLog(t, filepath.Join(tmp, "gke.log")))
+	if err := json.Unmarshal([]byte(line), &m); err != nil {
+		t.Fatalf("JSON inválido %q: %v", line, err)
+	}
+	if m["severity"] != "CRITICAL" || m["severity_number"] != float64(21) {
+		t.Fatalf("Línea inesperada: %s", line)
+	}
+
+	lf, _ := acacia.Start("fmt.log", tmp, acacia.Level.INFO, acacia.WithSeverity(acacia.Severity.OTel))
+	lf.OutputFormat(acacia.Format.Logfmt)
+	lf.Info("hola")
+	lf.Close()
+	if got := readLog(t, filepath.Join(tmp, "fmt.log")); !strings.Contains(got, "level=info severity=9 msg=hola") {
+		t.Fatalf("logfmt inesperado: %q", got)
+	}
+}
+
+func TestSyslogSeverity(t *testing.T) {
+	for level, want := range map[string]int{
+		acacia.Level.DEBUG: 7, acacia.Level.INFO: 6, acacia.Level.WARN: 4,
+		acacia.Level.ERROR: 3, acacia.Level.CRITICAL: 2, "TRACE": 6,
+	} {
+		if got := acacia.SyslogSeverity(level); got != want {
+			t.Fatalf("SyslogSeverity(%s) = %d, se esperaba %d", level, got, want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package acacia_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestShutdown(t *testing.T) {
+	tmp := t.TempDir()
+	db, _ := acacia.Start("db.log", tmp, acacia.Level.INFO)
+	acacia.Register("db", db)
+	def, _ := acacia.Start("main.log", tmp, acacia.Level.INFO)
+	acacia.SetDefault(def)
+	m, err := acacia.NewManager(acacia.Config{Loggers: []acacia.LoggerConfig{{Name: "access", File: "access.log", Path: tmp}}})
+	if err != nil {
+		t.Fatalf("NewManager falló: %v", err)
+	}
+
+	db.Info("consulta")
+	acacia.Info("arranque")
+	m.Get("access").Info("GET /")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := acacia.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown falló: %v", err)
+	}
+	if acacia.Get("db") != nil || acacia.Get("access") != nil || acacia.Default() != nil {
+		t.Fatal("Shutdown debe vaciar el registro")
+	}
+	if err := db.Close(); !errors.Is(err, acacia.ErrLoggerClosed) {
+		t.Fatalf("db debía estar cerrado: %v", err)
+	}
+	if err := def.Close(); !errors.Is(err, acacia.ErrLoggerClosed) {
+		t.Fatalf("El logger por defecto debía estar cerrado: %v", err)
+	}
+	if err := m.Close(); err != nil {
+		t.Fatalf("Close del manager tras Shutdown: %v", err)
+	}
+	for file, want := range map[string]string{"db.log": "consulta", "main.log": "arranque", "access.log": "GET /"} {
+		if got := readLog(t, filepath.Join(tmp, file)); !strings.Contains(got, want) {
+			t.Fatalf("%s sin vaciar: %q", file, got)
+		}
+	}
+	// nada que cerrar
+	if err := acacia.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown vacío falló: %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package acacia_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestSnapshot(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("snap.log", tmp, acacia.Level.INFO)
+	defer lg.Close()
+
+	lg.Rotation(1, 2)
+	big := strings.Repeat("S", 700*1024)
+	for i := 0; i < 3; i++ {
+		lg.Info(big)
+	}
+	lg.Info("ultima linea")
+
+	dst := filepath.Join(t.TempDir(), "snapshot")
+	if err := lg.Snapshot(dst); err != nil {
+		t.Fatalf("Snapshot falló: %v", err)
+	}
+
+	lg.Info("despues del snapshot")
+	lg.Sync()
+
+	if !fileExists(t, filepath.Join(dst, "snap.log.0")) {
+		t.Fatal("El snapshot no incluye los backups")
+	}
+	content := readLog(t, filepath.Join(dst, "snap.log"))
+	if !strings.Contains(content, "ultima linea") {
+		t.Fatal("El snapshot no incluye lo encolado antes de la llamada")
+	}
+	if strings.Contains(content, "despues del snapshot") {
+		t.Fatal("El snapshot no es consistente: incluye escrituras posteriores")
+	}
+
+	if err := lg.Snapshot(dst); err == nil {
+		t.Fatal("Se esperaba error si el destino ya existe")
+	}
+	if _, err := os.Stat(dst + ".tmp"); err == nil {
+		t.Fatal("Quedó un directorio temporal")
+	}
+}
@@ -0,0 +1,109 @@
+package acacia_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestSpillover(t *testing.T) {
+	tmp := t.TempDir()
+	lg, err := acacia.Start("spill.log", tmp, acacia.Level.INFO,
+		acacia.WithBufferSize(256), acacia.WithSpillover(16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg.Rotation(1, 50)
+
+	const goroutines, perG = 8, 2000
+	var wg sync.WaitGroup
+	for g := 0; g < goroutines; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < perG; i++ {
+				lg.Info(fmt.Sprintf("g%d n%05d %s", g, i, strings.Repeat("x", 64)))
+			}
+		}(g)
+	}
+	wg.Wait()
+	if err := lg.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if lg.Spilled() == 0 {
+		t.Fatal("La ráfaga debería haber pasado por el archivo de desborde")
+	}
+	if _, err := os.Stat(filepath.Join(tmp, "spill.log.wal")); !os.IsNotExist(err) {
+		t.Fatalf("El archivo de desborde debería borrarse al cerrar vacío: %v", err)
+	}
+
+	// todas las líneas, y en orden por goroutine, repartidas entre los respaldos
+	var lines []string
+	for i := 50; i >= 0; i-- {
+		if content, err := os.ReadFile(filepath.Join(tmp, fmt.Sprintf("spill.log.%d", i))); err == nil {
+			lines = append(lines, strings.Split(strings.TrimSpace(string(content)), "\n")...)
+		}
+	}
+	lines = append(lines, strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "spill.log"))), "\n")...)
+	next := make([]int, goroutines)
+	for _, line := range lines {
+		var g, n int
+		i := strings.Index(line, "] g")
+		if i < 0 {
+			continue
+		}
+		if _, err := fmt.Sscanf(line[i+2:], "g%d n%d", &g, &n); err != nil {
+			t.Fatalf("Línea inesperada %q: %v", line, err)
+		}
+		if n != next[g] {
+			t.Fatalf("g%d: se esperaba n%05d, llegó %q", g, next[g], line)
+		}
+		next[g]++
+	}
+	for g, n := range next {
+		if n != perG {
+			t.Fatalf("g%d: %d de %d líneas", g, n, perG)
+		}
+	}
+}
+
+func TestSpilloverRecovery(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "app.log")
+	if err := os.WriteFile(path, []byte("2025-11-18 10:00:00 [INFO] antes de la caída\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// lo que el writer había desviado cuando el proceso cayó
+	wal := "2025-11-18 10:00:01 [INFO] desviado 1\n2025-11-18 10:00:02 [INFO] desviado 2\n"
+	if err := os.WriteFile(path+".wal", []byte(wal), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	lg, err := acacia.Start("app.log", tmp, acacia.Level.INFO, acacia.WithSpillover(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg.Info("después del reinicio")
+	if err := lg.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(readLog(t, path)), "\n")
+	want := []string{"antes de la caída", "desviado 1", "desviado 2", "después del reinicio"}
+	if len(lines) != len(want) {
+		t.Fatalf("Se esperaban %d líneas, hay %d: %q", len(want), len(lines), lines)
+	}
+	for i, w := range want {
+		if !strings.HasSuffix(lines[i], w) {
+			t.Fatalf("Línea %d: se esperaba %q, llegó %q", i, w, lines[i])
+		}
+	}
+	if _, err := os.Stat(path + ".wal"); !os.IsNotExist(err) {
+		t.Fatalf("El archivo de desborde recuperado debería borrarse: %v", err)
+	}
+}
@@ -0,0 +1,47 @@
+package acacia_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestStackTraceText(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("stack.log", tmp, acacia.Level.INFO, acacia.WithStackTrace(acacia.Level.ERROR))
+	lg.Warn("sin stack")
+	lg.Error("falló %s", "escritura")
+	lg.Close()
+
+	content := readLog(t, filepath.Join(tmp, "stack.log"))
+	lines := strings.Split(strings.TrimSpace(content), "\n")
+	if len(lines) < 3 || !strings.HasSuffix(lines[0], "[WARN] sin stack") || !strings.HasSuffix(lines[1], "[ERROR] falló escritura") {
+		t.Fatalf("Salida inesperada:\n%s", content)
+	}
+	if !strings.HasPrefix(lines[2], "\tat github.com/humanjuan/acacia/v2/test_test.TestStackTraceText (stack_test.go:") {
+		t.Fatalf("El primer frame debería ser el llamador, obtenido %q", lines[2])
+	}
+	if strings.Contains(content, "(*Log)") {
+		t.Fatalf("El stack no debería incluir frames de acacia:\n%s", content)
+	}
+}
+
+func TestStackTraceJSON(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("stack.json", tmp, acacia.Level.INFO, acacia.WithStackTrace(acacia.Level.ERROR))
+	lg.StructuredJSON(true)
+	lg.CriticalFields("caído", acacia.String("svc", "db"))
+	lg.Close()
+
+	var entry map[string]interface{}
+	if err := json.Unmarshal([]byte(readLog(t, filepath.Join(tmp, "stack.json"))), &entry); err != nil {
+		t.Fatalf("JSON inválido: %v", err)
+	}
+	stack, _ := entry["stack"].(string)
+	if !strings.HasPrefix(stack, "github.com/humanjuan/acacia/v2/test_test.TestStackTraceJSON (stack_test.go:") || entry["svc"] != "db" {
+		t.Fatalf("Entrada inesperada: %v", entry)
+	}
+}
@@ -0,0 +1,42 @@
+package acacia_test
+
+import (
+	"log"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestStdLogBridge(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("std.log", tmp, acacia.Level.DEBUG)
+	std := lg.StdLogger()
+	std.Println("ERROR: conexión perdida")
+	std.Println("warning: disco al 90%")
+	std.Println("[DEBUG] detalle")
+	std.Println("FATAL no hay memoria")
+	std.Println("Errors found: 3")
+	legacy := log.New(lg.StdWriter(), "", log.LstdFlags)
+	legacy.Printf("info: arrancando")
+	lg.Close()
+
+	want := []string{
+		"[ERROR] conexión perdida",
+		"[WARN] disco al 90%",
+		"[DEBUG] detalle",
+		"[CRITICAL] no hay memoria",
+		"[INFO] Errors found: 3",
+		"[INFO] arrancando",
+	}
+	lines := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "std.log"))), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("Se esperaban %d líneas, obtenidas %d: %q", len(want), len(lines), lines)
+	}
+	for i, w := range want {
+		if !strings.HasSuffix(lines[i], w) {
+			t.Fatalf("Línea %d = %q, se esperaba sufijo %q", i, lines[i], w)
+		}
+	}
+}
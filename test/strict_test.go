@@ -0,0 +1,57 @@
+package acacia_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestStrictUTF8Text(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("strict.log", tmp, acacia.Level.INFO, acacia.WithStrictUTF8())
+	lg.Info("roto \xff\xfe fin")
+	lg.Info("válido ñ")
+	lg.Close()
+
+	content := readLog(t, filepath.Join(tmp, "strict.log"))
+	if !utf8.ValidString(content) {
+		t.Fatalf("Quedó UTF-8 inválido: %q", content)
+	}
+	if !strings.Contains(content, "roto � fin") || !strings.Contains(content, "válido ñ") {
+		t.Fatalf("Contenido inesperado: %q", content)
+	}
+}
+
+func TestStrictUTF8JSON(t *testing.T) {
+	tmp := t.TempDir()
+	broken := acacia.EncoderFunc(func(v interface{}) ([]byte, error) {
+		return []byte(`{"a":`), nil
+	})
+	lg, _ := acacia.Start("strict.log", tmp, acacia.Level.INFO, acacia.WithStrictUTF8(), acacia.WithEncoder(broken))
+	lg.StructuredJSON(true)
+	lg.InfoFields("mal", acacia.Any("obj", struct{ A int }{1}))
+	lg.InfoFields("bien \xff", acacia.String("k", "v"))
+	lg.Close()
+
+	lines := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "strict.log"))), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Se esperaban 2 líneas: %q", lines)
+	}
+	for _, line := range lines {
+		if !json.Valid([]byte(line)) {
+			t.Fatalf("Línea JSON inválida: %q", line)
+		}
+	}
+	var rec map[string]interface{}
+	_ = json.Unmarshal([]byte(lines[0]), &rec)
+	if rec["invalid_json"] != true || rec["level"] != "INFO" || !strings.Contains(rec["msg"].(string), `"msg":"mal"`) {
+		t.Fatalf("Registro reenvuelto inesperado: %v", rec)
+	}
+	if strings.Contains(lines[1], "invalid_json") {
+		t.Fatalf("Una línea válida no debe reenvolverse: %q", lines[1])
+	}
+}
@@ -0,0 +1,186 @@
+package acacia_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+type requestInfo struct {
+	Method  string `json:"method"`
+	Path    string `json:"path"`
+	Status  int    `json:"status"`
+	TraceID string `json:"trace_id,omitempty"`
+	secret  string
+}
+
+// readJSONEntries decodifica cada línea del log como un objeto JSON.
+func readJSONEntries(t *testing.T, path string) []map[string]interface{} {
+	t.Helper()
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(readLog(t, path)), "\n") {
+		var entry map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("JSON inválido %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestStructuredStruct(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("struct.log", tmp, acacia.Level.INFO)
+	lg.StructuredJSON(true)
+	lg.Info(requestInfo{Method: "GET", Path: "/health", Status: 200, secret: "x"})
+	lg.Info(&requestInfo{Method: "POST", Path: "/login", Status: 401, TraceID: "abc"})
+	lg.Close()
+
+	entries := readJSONEntries(t, filepath.Join(tmp, "struct.log"))
+	if len(entries) != 2 {
+		t.Fatalf("Se esperaban 2 entradas, obtenidas %d", len(entries))
+	}
+	first := entries[0]
+	if first["method"] != "GET" || first["path"] != "/health" || first["status"] != float64(200) {
+		t.Fatalf("Campos del struct no aplanados: %v", first)
+	}
+	if _, ok := first["trace_id"]; ok {
+		t.Fatalf("omitempty no respetado: %v", first)
+	}
+	if _, ok := first["secret"]; ok {
+		t.Fatalf("Campo no exportado emitido: %v", first)
+	}
+	if first["level"] != "INFO" || first["ts"] == nil {
+		t.Fatalf("Faltan ts/level: %v", first)
+	}
+	if entries[1]["trace_id"] != "abc" || entries[1]["status"] != float64(401) {
+		t.Fatalf("Puntero a struct mal codificado: %v", entries[1])
+	}
+}
+
+type color int
+
+func (c color) String() string { return [...]string{"rojo", "verde"}[c] }
+
+type ipAddr [4]byte
+
+func (ip ipAddr) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d.%d.%d.%d", ip[0], ip[1], ip[2], ip[3])), nil
+}
+
+type money struct{ cents int64 }
+
+func (m money) MarshalJSON() ([]byte, error) {
+	return []byte(fmt.Sprintf(`"%d.%02d"`, m.cents/100, m.cents%100)), nil
+}
+
+func TestStructuredFieldMarshalers(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("values.log", tmp, acacia.Level.INFO)
+	lg.StructuredJSON(true)
+	var missing *nilStringer
+	lg.Info(map[string]interface{}{
+		"color": color(1),
+		"ip":    ipAddr{10, 0, 0, 1},
+		"total": money{cents: 1234},
+		"err":   errors.New("sin conexión"),
+		"nil":   missing,
+	})
+	lg.Close()
+
+	entry := readJSONEntries(t, filepath.Join(tmp, "values.log"))[0]
+	want := map[string]interface{}{
+		"color": "verde",
+		"ip":    "10.0.0.1",
+		"total": "12.34",
+		"err":   "sin conexión",
+		"nil":   "<nil>",
+	}
+	for k, v := range want {
+		if entry[k] != v {
+			t.Fatalf("Campo %q: esperado %v, obtenido %#v", k, v, entry[k])
+		}
+	}
+}
+
+func TestStructuredCustomEncoder(t *testing.T) {
+	tmp := t.TempDir()
+	var calls int32
+	enc := acacia.EncoderFunc(func(v interface{}) ([]byte, error) {
+		atomic.AddInt32(&calls, 1)
+		return json.Marshal(v)
+	})
+	lg, _ := acacia.Start("enc.log", tmp, acacia.Level.INFO, acacia.WithEncoder(enc))
+	lg.StructuredJSON(true)
+	lg.Info(map[string]interface{}{"k": "v"})
+	lg.Close()
+
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Fatalf("Encoder personalizado no usado: %d llamadas", calls)
+	}
+	if entry := readJSONEntries(t, filepath.Join(tmp, "enc.log"))[0]; entry["k"] != "v" {
+		t.Fatalf("Entrada inesperada: %v", entry)
+	}
+}
+
+func TestStructuredMapEncodedByWriter(t *testing.T) {
+	tmp := t.TempDir()
+	clock := &fakeClock{}
+	clock.Set(time.Date(2025, 11, 18, 10, 0, 0, 0, time.UTC))
+	lg, _ := acacia.Start("deferred.log", tmp, acacia.Level.INFO, acacia.WithClock(clock.Now))
+	lg.UseUTC(true)
+	lg.TimestampFormat(acacia.TS.RFC3339)
+	lg.StructuredJSON(true)
+	m := map[string]interface{}{"msg": "primero", "n": 1}
+	lg.Info(m)
+	// el mapa se codifica en el writer: reutilizarlo no altera lo ya registrado
+	m["msg"], m["n"] = "segundo", 2
+	clock.Set(time.Date(2025, 11, 18, 10, 0, 5, 0, time.UTC))
+	lg.Info(m)
+	lg.Close()
+
+	entries := readJSONEntries(t, filepath.Join(tmp, "deferred.log"))
+	if len(entries) != 2 {
+		t.Fatalf("Se esperaban 2 entradas, hay %d", len(entries))
+	}
+	if entries[0]["msg"] != "primero" || entries[0]["n"] != float64(1) || entries[0]["ts"] != "2025-11-18T10:00:00Z" {
+		t.Fatalf("Primera entrada alterada: %v", entries[0])
+	}
+	if entries[1]["msg"] != "segundo" || entries[1]["ts"] != "2025-11-18T10:00:05Z" {
+		t.Fatalf("Segunda entrada inesperada: %v", entries[1])
+	}
+}
+
+func TestStructuredMapNestedValues(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("nested.log", tmp, acacia.Level.INFO)
+	lg.StructuredJSON(true)
+	meta := map[string]int{}
+	tags := []string{"a"}
+	for i := 0; i < 200; i++ {
+		lg.Info(map[string]interface{}{"msg": "anidado", "meta": meta, "tags": tags, "i": i})
+		// con -race: el writer no debe leer lo que el llamador sigue modificando
+		meta["k"] = i + 1
+		tags[0] = fmt.Sprint(i + 1)
+	}
+	lg.Close()
+
+	entries := readJSONEntries(t, filepath.Join(tmp, "nested.log"))
+	if len(entries) != 200 {
+		t.Fatalf("Se esperaban 200 entradas, hay %d", len(entries))
+	}
+	for i, entry := range entries {
+		meta, _ := entry["meta"].(map[string]interface{})
+		tags, _ := entry["tags"].([]interface{})
+		if i > 0 && (meta["k"] != float64(i) || len(tags) != 1 || tags[0] != fmt.Sprint(i)) {
+			t.Fatalf("La entrada %d no refleja los valores al registrar: %v", i, entry)
+		}
+	}
+}
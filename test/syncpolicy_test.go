@@ -0,0 +1,69 @@
+package acacia_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestSyncPolicies(t *testing.T) {
+	policies := map[string]acacia.SyncPolicy{
+		"never":    acacia.SyncNever,
+		"flush":    acacia.SyncEveryFlush,
+		"interval": acacia.SyncInterval(20 * time.Millisecond),
+		"level":    acacia.SyncOnLevel(acacia.Level.ERROR),
+	}
+	for name, policy := range policies {
+		t.Run(name, func(t *testing.T) {
+			tmp := t.TempDir()
+			lg, _ := acacia.Start("sync.log", tmp, acacia.Level.INFO,
+				acacia.WithSyncPolicy(policy), acacia.WithFlushInterval(10*time.Millisecond))
+			lg.Info("info antes")
+			lg.Error("error durable")
+
+			// sin Sync(): el writer debe escribir por sí solo en el siguiente flush
+			deadline := time.Now().Add(2 * time.Second)
+			for time.Now().Before(deadline) {
+				if strings.Contains(readLog(t, filepath.Join(tmp, "sync.log")), "error durable") {
+					break
+				}
+				time.Sleep(10 * time.Millisecond)
+			}
+			lg.Close()
+			if !strings.Contains(readLog(t, filepath.Join(tmp, "sync.log")), "error durable") {
+				t.Fatal("No se escribió el registro")
+			}
+		})
+	}
+}
+
+func TestSyncPolicyBackground(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("bg.log", tmp, acacia.Level.INFO,
+		acacia.WithSyncPolicy(acacia.SyncEveryFlush), acacia.WithFlushInterval(time.Millisecond))
+	lg.Rotation(1, 3)
+	line := strings.Repeat("d", 200)
+	for i := 0; i < 20000; i++ {
+		lg.Info(line)
+	}
+	// los fsync en segundo plano no deben chocar con las rotaciones ni con Sync
+	if err := lg.Sync(); err != nil {
+		t.Fatalf("Sync devolvió error: %v", err)
+	}
+	if err := lg.Close(); err != nil {
+		t.Fatalf("Close devolvió error: %v", err)
+	}
+	total := 0
+	for _, name := range []string{"bg.log", "bg.log.0", "bg.log.1", "bg.log.2"} {
+		path := filepath.Join(tmp, name)
+		if fileExists(t, path) {
+			total += strings.Count(readLog(t, path), "\n")
+		}
+	}
+	if total == 0 {
+		t.Fatal("No se escribió ningún registro")
+	}
+}
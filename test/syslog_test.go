@@ -0,0 +1,62 @@
+package acacia_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestSyslogOutput(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("syslog.log", tmp, acacia.Level.INFO, acacia.WithSyslog(acacia.SyslogConfig{
+		Facility: 16,
+		Hostname: "web 01",
+		AppName:  "gateway",
+		SDID:     "acme@32473",
+	}))
+	lg.Warn(map[string]interface{}{
+		"msg":       "login fallido\nreintentar",
+		"event":     "auth-failure",
+		"ip":        "10.0.0.7",
+		"query":     `a="b"]\c`,
+		"user name": "juan",
+	})
+	lg.ErrorFields("intrusión", acacia.String("ip", "10.0.0.8"), acacia.Int("attempts", 12))
+	lg.Info("hola")
+	lg.Close()
+
+	lines := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "syslog.log"))), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Se esperaban 3 líneas, obtenidas %d: %q", len(lines), lines)
+	}
+	ts := `\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{6}(Z|[+-]\d\d:\d\d)`
+	pid := fmt.Sprint(os.Getpid())
+	want := []string{
+		`^<132>1 ` + ts + ` web01 gateway ` + pid + ` auth-failure \[acme@32473 ip="10\.0\.0\.7" query="a=\\"b\\"\\]\\\\c" user_name="juan"\] login fallido reintentar$`,
+		`^<131>1 ` + ts + ` web01 gateway ` + pid + ` - \[acme@32473 ip="10\.0\.0\.8" attempts="12"\] intrusión$`,
+		`^<134>1 ` + ts + ` web01 gateway ` + pid + ` - - hola$`,
+	}
+	for i, pattern := range want {
+		if !regexp.MustCompile(pattern).MatchString(lines[i]) {
+			t.Fatalf("Línea %d %q no coincide con %s", i, lines[i], pattern)
+		}
+	}
+}
+
+func TestSyslogDefaults(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("syslog.log", tmp, acacia.Level.INFO, acacia.WithoutTimestamp())
+	lg.OutputFormat(acacia.Format.Syslog)
+	lg.CriticalFields("caída", acacia.String("db", "main"))
+	lg.Close()
+
+	line := strings.TrimSpace(readLog(t, filepath.Join(tmp, "syslog.log")))
+	if !regexp.MustCompile(`^<10>1 - \S+ \S+ \d+ - \[fields@32473 db="main"\] caída$`).MatchString(line) {
+		t.Fatalf("Línea RFC 5424 inesperada: %q", line)
+	}
+}
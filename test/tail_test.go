@@ -0,0 +1,68 @@
+package acacia_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestTailFollowsRotation(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("app.log", tmp, acacia.Level.DEBUG)
+	lg.Rotation(1, 3)
+	lg.Info("antes")
+	lg.Sync()
+
+	done := make(chan struct{})
+	entries, err := acacia.Tail(filepath.Join(tmp, "app.log"), acacia.TailOptions{
+		Query:    acacia.Query{Level: acacia.Level.INFO},
+		Interval: 5 * time.Millisecond,
+		Done:     done,
+	})
+	if err != nil {
+		t.Fatalf("Tail falló: %v", err)
+	}
+	chunk := strings.Repeat("x", 100*1024)
+	go func() {
+		lg.Debug("filtrado")
+		for i := 0; i < 15; i++ {
+			lg.Info(chunk)
+			lg.Sync()
+		}
+		lg.Warn("final")
+		lg.Close()
+	}()
+
+	chunks := 0
+	timeout := time.After(10 * time.Second)
+	for done != nil {
+		select {
+		case e := <-entries:
+			switch {
+			case e.Message == chunk:
+				chunks++
+			case e.Message == "final":
+				close(done)
+				done = nil
+			default:
+				t.Fatalf("Entrada inesperada: %.40q", e.Message)
+			}
+		case <-timeout:
+			t.Fatalf("Tail no entregó la entrada final (%d bloques)", chunks)
+		}
+	}
+	if chunks != 15 {
+		t.Fatalf("Se esperaban 15 bloques a través de la rotación, obtenidos %d", chunks)
+	}
+	for range entries {
+	}
+}
+
+func TestTailMissingFile(t *testing.T) {
+	if _, err := acacia.Tail(filepath.Join(t.TempDir(), "nada.log"), acacia.TailOptions{}); err == nil {
+		t.Fatalf("Se esperaba error para un archivo inexistente")
+	}
+}
@@ -0,0 +1,196 @@
+package acacia_test
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestPreciseTimestamps(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("precise.log", tmp, acacia.Level.INFO, acacia.WithPreciseTimestamps(true))
+	lg.TimestampFormat(acacia.TS.RFC3339Nano)
+
+	for i := 0; i < 20; i++ {
+		lg.Info("rapido")
+		time.Sleep(time.Millisecond)
+	}
+	lg.Close()
+
+	var prev time.Time
+	for _, line := range strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "precise.log"))), "\n") {
+		ts, err := time.Parse(time.RFC3339Nano, line[:strings.IndexByte(line, ' ')])
+		if err != nil {
+			t.Fatalf("Timestamp inválido en %q: %v", line, err)
+		}
+		if !ts.After(prev) {
+			t.Fatalf("Timestamps no estrictamente crecientes: %s <= %s", ts, prev)
+		}
+		prev = ts
+	}
+}
+
+func TestTimestampFormatPerInstance(t *testing.T) {
+	tmp := t.TempDir()
+	a, _ := acacia.Start("a.log", tmp, acacia.Level.INFO)
+	b, _ := acacia.Start("b.log", tmp, acacia.Level.INFO)
+	a.TimestampFormat(acacia.TS.RFC3339)
+	b.TimestampFormat(acacia.TS.Kitchen)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			b.TimestampFormat(acacia.TS.Kitchen)
+		}
+	}()
+	a.Info("uno")
+	b.Info("dos")
+	<-done
+	a.Close()
+	b.Close()
+
+	lineA := strings.TrimSpace(readLog(t, filepath.Join(tmp, "a.log")))
+	if _, err := time.Parse(time.RFC3339, lineA[:strings.IndexByte(lineA, ' ')]); err != nil {
+		t.Fatalf("Formato de a.log inesperado %q: %v", lineA, err)
+	}
+	lineB := strings.TrimSpace(readLog(t, filepath.Join(tmp, "b.log")))
+	if _, err := time.Parse(time.Kitchen, lineB[:strings.IndexByte(lineB, ' ')]); err != nil {
+		t.Fatalf("Formato de b.log inesperado %q: %v", lineB, err)
+	}
+}
+
+func TestSharedTimestampCache(t *testing.T) {
+	tmp := t.TempDir()
+	a, _ := acacia.Start("a.log", tmp, acacia.Level.INFO)
+	b, _ := acacia.Start("b.log", tmp, acacia.Level.INFO)
+	c, _ := acacia.Start("c.log", tmp, acacia.Level.INFO)
+	b.TimestampFormat(acacia.TS.RFC3339Nano)
+	c.TimestampFormat(acacia.TS.Kitchen)
+	a.Close()
+
+	// la caché sigue refrescándose para los demás tras cerrar uno
+	before := time.Now()
+	time.Sleep(300 * time.Millisecond)
+	b.Info("dos")
+	c.Info("tres")
+	b.Close()
+	c.Close()
+
+	lineB := strings.TrimSpace(readLog(t, filepath.Join(tmp, "b.log")))
+	ts, err := time.Parse(time.RFC3339Nano, lineB[:strings.IndexByte(lineB, ' ')])
+	if err != nil {
+		t.Fatalf("Formato de b.log inesperado %q: %v", lineB, err)
+	}
+	if ts.Before(before.Add(100 * time.Millisecond)) {
+		t.Fatalf("Timestamp no refrescado: %s, inicio %s", ts, before)
+	}
+	lineC := strings.TrimSpace(readLog(t, filepath.Join(tmp, "c.log")))
+	if _, err := time.Parse(time.Kitchen, lineC[:strings.IndexByte(lineC, ' ')]); err != nil {
+		t.Fatalf("Formato de c.log inesperado %q: %v", lineC, err)
+	}
+}
+
+func TestUseUTC(t *testing.T) {
+	local := time.Local
+	time.Local = time.FixedZone("ACA", 3*3600)
+	defer func() { time.Local = local }()
+
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("utc.log", tmp, acacia.Level.INFO)
+	lg.TimestampFormat(acacia.TS.RFC3339)
+	lg.UseUTC(true)
+	lg.Info("texto")
+	lg.StructuredJSON(true)
+	lg.Info("json")
+	lg.Close()
+
+	out := readLog(t, filepath.Join(tmp, "utc.log"))
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Se esperaban 2 líneas, obtenidas %d:\n%s", len(lines), out)
+	}
+	if ts := lines[0][:strings.IndexByte(lines[0], ' ')]; !strings.HasSuffix(ts, "Z") {
+		t.Fatalf("Timestamp de texto no está en UTC: %q", ts)
+	}
+	if !strings.Contains(lines[1], `Z"`) || strings.Contains(lines[1], "+03:00") {
+		t.Fatalf("Timestamp JSON no está en UTC: %q", lines[1])
+	}
+}
+
+func TestEpochTimestamps(t *testing.T) {
+	tmp := t.TempDir()
+	before := time.Now().UnixNano() / int64(time.Millisecond)
+	lg, _ := acacia.Start("epoch.log", tmp, acacia.Level.INFO, acacia.WithEpochTimestamps(time.Millisecond))
+	lg.StructuredJSON(true)
+	lg.Info("epoch")
+	lg.Close()
+	after := time.Now().UnixNano() / int64(time.Millisecond)
+
+	var entry map[string]interface{}
+	d := json.NewDecoder(strings.NewReader(readLog(t, filepath.Join(tmp, "epoch.log"))))
+	d.UseNumber()
+	if err := d.Decode(&entry); err != nil {
+		t.Fatalf("JSON inválido: %v", err)
+	}
+	n, ok := entry["ts"].(json.Number)
+	if !ok {
+		t.Fatalf("ts no es numérico: %#v", entry["ts"])
+	}
+	ms, err := n.Int64()
+	if err != nil || ms < before || ms > after {
+		t.Fatalf("ts fuera de rango: %v (esperado entre %d y %d)", n, before, after)
+	}
+}
+
+func TestWithoutTimestamp(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("nots.log", tmp, acacia.Level.INFO, acacia.WithoutTimestamp(), acacia.WithPreciseTimestamps(true))
+	lg.Info("texto")
+	lg.Info("formato %d", 1)
+	lg.InfoFields("campos", acacia.Int("n", 2))
+	lg.Sync()
+	lg.StructuredJSON(true)
+	lg.Info("json mapa")
+	lg.InfoFields("json campos")
+	lg.Sync()
+	lg.OutputFormat(acacia.Format.Logfmt)
+	lg.InfoFields("logfmt")
+	lg.Close()
+
+	lines := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "nots.log"))), "\n")
+	want := []string{
+		"[INFO] texto",
+		"[INFO] formato 1",
+		"[INFO] campos n=2",
+		`{"level":"INFO","msg":"json mapa"}`,
+		`{"level":"INFO","msg":"json campos"}`,
+		`level=info msg=logfmt`,
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("Se esperaban %d líneas, hay %d: %q", len(want), len(lines), lines)
+	}
+	for i := range want {
+		if lines[i] != want[i] {
+			t.Fatalf("Línea %d: se esperaba %q, obtenida %q", i, want[i], lines[i])
+		}
+	}
+}
+
+func TestStaticPrefix(t *testing.T) {
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("prefix.log", tmp, acacia.Level.INFO, acacia.WithStaticPrefix("web-1"))
+	lg.Info("hola")
+	lg.TimestampFormat(acacia.TS.RFC3339)
+	lg.Warn("sigue %s", "fijo")
+	lg.Close()
+
+	got := readLog(t, filepath.Join(tmp, "prefix.log"))
+	if got != "web-1 [INFO] hola\nweb-1 [WARN] sigue fijo\n" {
+		t.Fatalf("Prefijo inesperado: %q", got)
+	}
+}
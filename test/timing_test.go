@@ -0,0 +1,39 @@
+package acacia_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestTimeTrack(t *testing.T) {
+	tmp := t.TempDir()
+	clock := &fakeClock{t: time.Date(2025, 11, 18, 10, 0, 0, 0, time.UTC)}
+	lg, err := acacia.Start("timing.log", tmp, acacia.Level.INFO, acacia.WithClock(clock.Now))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	func() {
+		defer lg.TimeTrack("query users")()
+		clock.Set(clock.Now().Add(1500 * time.Millisecond))
+	}()
+	stop := lg.Timed("warn", "slow call", acacia.String("db", "main"))
+	clock.Set(clock.Now().Add(2 * time.Second))
+	stop()
+	lg.Timed(acacia.Level.DEBUG, "oculto")()
+	lg.Close()
+
+	content := readLog(t, filepath.Join(tmp, "timing.log"))
+	for _, want := range []string{"[INFO] query users elapsed=1.5s\n", "[WARN] slow call elapsed=2s db=main\n"} {
+		if !strings.Contains(content, want) {
+			t.Fatalf("Falta %q en:\n%s", want, content)
+		}
+	}
+	if strings.Contains(content, "oculto") {
+		t.Fatalf("Un nivel desactivado no debe escribirse:\n%s", content)
+	}
+}
@@ -0,0 +1,56 @@
+package acacia_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestTransforms(t *testing.T) {
+	addRegion := func(e *acacia.Entry) {
+		e.Fields = append(e.Fields, acacia.String("region", "eu"))
+	}
+	downgrade := func(e *acacia.Entry) {
+		if strings.Contains(e.Message, "timeout") {
+			e.Level = "warn"
+		}
+		if strings.Contains(e.Message, "ruido") {
+			e.Level = acacia.Level.DEBUG
+		}
+		e.Message = strings.Replace(e.Message, "secreto", "***", -1)
+	}
+
+	tmp := t.TempDir()
+	lg, _ := acacia.Start("transform.log", tmp, acacia.Level.INFO,
+		acacia.WithTransform(addRegion), acacia.WithTransform(downgrade))
+	fields := []acacia.Field{acacia.Int("n", 1)}
+	lg.Error("timeout en %s", "db")
+	lg.InfoBytes([]byte("valor secreto"))
+	lg.Info("ruido")
+	lg.InfoFields("request", fields...)
+	lg.StructuredJSON(true)
+	lg.Info(map[string]interface{}{"msg": "login", "user": "ana"})
+	lg.Close()
+
+	if len(fields) != 1 {
+		t.Fatalf("Los campos del llamador fueron modificados: %v", fields)
+	}
+	lines := strings.Split(strings.TrimSpace(readLog(t, filepath.Join(tmp, "transform.log"))), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Se esperaban 4 líneas, obtenidas %d: %q", len(lines), lines)
+	}
+	if !strings.Contains(lines[0], "[WARN]") || !strings.HasSuffix(lines[0], "timeout en db region=eu") {
+		t.Fatalf("Línea inesperada: %q", lines[0])
+	}
+	if !strings.HasSuffix(lines[1], "valor *** region=eu") {
+		t.Fatalf("Línea inesperada: %q", lines[1])
+	}
+	if !strings.HasSuffix(lines[2], "request n=1 region=eu") {
+		t.Fatalf("Línea inesperada: %q", lines[2])
+	}
+	if !strings.Contains(lines[3], `"msg":"login"`) || !strings.Contains(lines[3], `"user":"ana"`) || !strings.Contains(lines[3], `"region":"eu"`) {
+		t.Fatalf("Línea JSON inesperada: %q", lines[3])
+	}
+}
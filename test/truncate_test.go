@@ -0,0 +1,56 @@
+package acacia_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestMaxMessageSize(t *testing.T) {
+	tmp := t.TempDir()
+	path := filepath.Join(tmp, "cap.log")
+	lg, err := acacia.Start("cap.log", tmp, acacia.Level.INFO, acacia.WithMaxMessageSize(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	big := strings.Repeat("a", 10000)
+	lg.Info(big)
+	lg.Info("archivo: %s", big)
+	lg.InfoFields(big, acacia.Int("n", 1))
+	fmt.Fprintln(lg, big)
+	lg.Info(strings.Repeat("ñ", 100))
+	lg.Info("corto")
+	lg.Sync()
+
+	lines := strings.Split(strings.TrimSpace(readLog(t, path)), "\n")
+	if len(lines) != 6 {
+		t.Fatalf("Se esperaban 6 líneas, hay %d", len(lines))
+	}
+	for i, want := range []string{"...[truncated 9926 bytes]", "...[truncated 9935 bytes]", "...[truncated 9926 bytes] n=1", "...[truncated 9927 bytes]"} {
+		if !strings.HasSuffix(lines[i], want) {
+			t.Fatalf("Línea %d debería terminar en %q: %q", i, want, lines[i])
+		}
+	}
+	msg := lines[4][strings.Index(lines[4], "] ")+2:]
+	if len(msg) > 100 || !utf8.ValidString(msg) || !strings.HasSuffix(msg, "...[truncated 124 bytes]") {
+		t.Fatalf("El corte debe respetar UTF-8 y el límite: %q (%d bytes)", msg, len(msg))
+	}
+	if !strings.HasSuffix(lines[5], "] corto") {
+		t.Fatalf("Un mensaje corto no se toca: %q", lines[5])
+	}
+
+	lg.StructuredJSON(true)
+	lg.Info(map[string]interface{}{"msg": big, "file": "a.txt"})
+	lg.Close()
+	content := readLog(t, path)
+	if !strings.Contains(content, `...[truncated 9926 bytes]"`) || !strings.Contains(content, `"file":"a.txt"`) {
+		t.Fatalf("El msg del mapa debería truncarse:\n%s", content)
+	}
+	if strings.Count(content, strings.Repeat("a", 200)) != 0 {
+		t.Fatal("Ningún mensaje debería superar el límite")
+	}
+}
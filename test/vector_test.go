@@ -0,0 +1,59 @@
+package acacia_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+// Las líneas grandes se escriben con writev fuera del buffer del lote: el
+// orden con las pequeñas debe mantenerse, también al rotar.
+func TestVectoredBatchOrder(t *testing.T) {
+	for _, rotate := range []bool{false, true} {
+		t.Run(fmt.Sprint("rotate=", rotate), func(t *testing.T) {
+			tmp := t.TempDir()
+			lg, err := acacia.Start("vec.log", tmp, acacia.Level.INFO)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rotate {
+				lg.Rotation(1, 50)
+			}
+			lg.StructuredJSON(true)
+			big := strings.Repeat("v", 3000)
+			const n = 2000
+			for i := 0; i < n; i++ {
+				if i%3 == 0 {
+					lg.Info(fmt.Sprintf("big-%d %s", i, big))
+				} else {
+					lg.Info(fmt.Sprintf("small-%d", i))
+				}
+			}
+			if err := lg.Close(); err != nil {
+				t.Fatalf("Close devolvió error: %v", err)
+			}
+
+			var content string
+			for i := 49; i >= 0; i-- {
+				path := filepath.Join(tmp, fmt.Sprintf("vec.log.%d", i))
+				if fileExists(t, path) {
+					content += readLog(t, path)
+				}
+			}
+			content += readLog(t, filepath.Join(tmp, "vec.log"))
+			lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+			if len(lines) != n {
+				t.Fatalf("Se esperaban %d líneas, hay %d", n, len(lines))
+			}
+			for i, line := range lines {
+				want := fmt.Sprintf(`-%d`, i)
+				if !strings.Contains(line, want+" ") && !strings.Contains(line, want+`"`) {
+					t.Fatalf("Línea %d fuera de orden: %.60q", i, line)
+				}
+			}
+		})
+	}
+}
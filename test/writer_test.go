@@ -0,0 +1,70 @@
+package acacia_test
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+// syncBuffer es un io.Writer con Sync, como *os.File.
+type syncBuffer struct {
+	bytes.Buffer
+	syncs int
+}
+
+func (b *syncBuffer) Sync() error {
+	b.syncs++
+	return nil
+}
+
+func TestStartWriter(t *testing.T) {
+	out := &syncBuffer{}
+	lg, err := acacia.StartWriter(out, acacia.Level.INFO)
+	if err != nil {
+		t.Fatalf("StartWriter falló: %v", err)
+	}
+	lg.OutputFormat(acacia.Format.JSON)
+	lg.Rotation(1, 1)
+	lg.DailyRotation(true)
+	lg.Debug("oculto")
+	lg.InfoFields("listo", acacia.Int("port", 8080))
+	if err := lg.Sync(); err != nil {
+		t.Fatalf("Sync falló: %v", err)
+	}
+	if got := out.String(); !strings.Contains(got, `"msg":"listo","port":8080`) || strings.Contains(got, "oculto") {
+		t.Fatalf("Salida inesperada: %q", got)
+	}
+	if out.syncs == 0 {
+		t.Fatalf("Sync debía llamar al Sync del writer")
+	}
+	if err := lg.Snapshot(filepath.Join(t.TempDir(), "snap")); !errors.Is(err, acacia.ErrNoFile) {
+		t.Fatalf("Se esperaba ErrNoFile, obtenido %v", err)
+	}
+	if err := lg.Reopen(); err != nil {
+		t.Fatalf("Reopen falló: %v", err)
+	}
+	if err := lg.Healthy(); err != nil {
+		t.Fatalf("Healthy falló: %v", err)
+	}
+	lg.Warn("fin")
+	if err := lg.Close(); err != nil {
+		t.Fatalf("Close falló: %v", err)
+	}
+	if !strings.Contains(out.String(), `"msg":"fin"`) {
+		t.Fatalf("Close no escribió lo pendiente: %q", out.String())
+	}
+	if _, err := os.Stat("writer"); err == nil {
+		t.Fatalf("StartWriter no debía crear archivos")
+	}
+}
+
+func TestStartWriterNil(t *testing.T) {
+	if _, err := acacia.StartWriter(nil, acacia.Level.INFO); err == nil {
+		t.Fatalf("Se esperaba error con writer nil")
+	}
+}
@@ -0,0 +1,136 @@
+package acacia_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	acacia "github.com/humanjuan/acacia/v2"
+)
+
+func TestWithWriters(t *testing.T) {
+	tmp := t.TempDir()
+	lg, err := acacia.Start("striped.log", tmp, acacia.Level.INFO, acacia.WithWriters(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lg.StructuredJSON(true)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 250; i++ {
+				lg.Info(fmt.Sprintf("g%d-%d", g, i))
+			}
+		}(g)
+	}
+	wg.Wait()
+	if err := lg.Sync(); err != nil {
+		t.Fatalf("Sync devolvió error: %v", err)
+	}
+
+	seen := make(map[string]bool)
+	for i := 0; i < 4; i++ {
+		content := readLog(t, filepath.Join(tmp, fmt.Sprintf("striped.log.%d", i)))
+		lines := strings.Split(strings.TrimSpace(content), "\n")
+		if content == "" || len(lines) == 0 {
+			t.Fatalf("El archivo %d no recibió registros", i)
+		}
+		for _, line := range lines {
+			if !strings.HasPrefix(line, "{") {
+				t.Fatalf("El formato JSON no llegó al archivo %d: %q", i, line)
+			}
+			seen[line[strings.Index(line, `"msg":`):]] = true
+		}
+	}
+	if len(seen) != 2000 {
+		t.Fatalf("Se esperaban 2000 registros distintos, hay %d", len(seen))
+	}
+	if fileExists(t, filepath.Join(tmp, "striped.log")) {
+		t.Fatal("El logger repartido no debe crear el archivo base")
+	}
+
+	if err := lg.Close(); err != nil {
+		t.Fatalf("Close devolvió error: %v", err)
+	}
+	lg.Info("tarde")
+	if lg.Dropped() != 1 {
+		t.Fatalf("Dropped debería sumar los archivos: %d", lg.Dropped())
+	}
+}
+
+func TestWithWritersRotationOff(t *testing.T) {
+	tmp := t.TempDir()
+	lg, err := acacia.Start("striped.log", tmp, acacia.Level.INFO, acacia.WithWriters(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lg.Close()
+	lg.Rotation(1, 2)
+	lg.Rotation(0, 2)
+
+	line := strings.Repeat("x", 1024)
+	for i := 0; i < 2048; i++ {
+		lg.Info(line)
+	}
+	if err := lg.Sync(); err != nil {
+		t.Fatalf("Sync devolvió error: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if fileExists(t, filepath.Join(tmp, fmt.Sprintf("striped.log.%d.0", i))) {
+			t.Fatalf("Rotation(0, n) no desactivó la rotación del archivo %d", i)
+		}
+	}
+}
+
+func TestWithWritersParentAndFilters(t *testing.T) {
+	tmp := t.TempDir()
+	var inside int32
+	calls := 0 // sin atomic: con -race, dos filtros a la vez fallarían aquí
+	filter := func(e acacia.Entry) bool {
+		if atomic.AddInt32(&inside, 1) > 1 {
+			t.Error("Dos archivos ejecutaron filtros a la vez")
+		}
+		calls++
+		time.Sleep(10 * time.Microsecond)
+		atomic.AddInt32(&inside, -1)
+		return !strings.Contains(e.Message, "health")
+	}
+	before := heapInUse()
+	lg, err := acacia.Start("striped.log", tmp, acacia.Level.INFO, acacia.WithWriters(4), acacia.WithFilter(filter))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer lg.Close()
+	if grown := int64(heapInUse()) - int64(before); grown > 4<<20 {
+		t.Fatalf("WithWriters(4) reservó %d KB de heap", grown>>10)
+	}
+	if err := lg.Healthy(); err != nil {
+		t.Fatalf("El logger repartido debería estar sano: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				lg.Info("health")
+				lg.Info("pedido")
+			}
+		}()
+	}
+	wg.Wait()
+	if err := lg.Sync(); err != nil {
+		t.Fatalf("Sync devolvió error: %v", err)
+	}
+	if calls != 800 || lg.Filtered() != 400 {
+		t.Fatalf("Filtros: %d llamadas, %d descartadas", calls, lg.Filtered())
+	}
+}
@@ -0,0 +1,64 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import "strings"
+
+// TimeTrack starts timing name and returns a function that logs, at INFO
+// level, name as the message with the elapsed time in an "elapsed" field:
+//
+//	defer log.TimeTrack("query users")()
+//	// ... [INFO] query users elapsed=12.3ms
+//
+// The stop function can be called more than once; each call logs the time
+// since TimeTrack.
+func (_log *Log) TimeTrack(name string, fields ...Field) func() {
+	return _log.Timed(Level.INFO, name, fields...)
+}
+
+// Timed is TimeTrack at the given level. fields are logged after "elapsed".
+// Time comes from the logger's clock (see WithClock).
+func (_log *Log) Timed(level, name string, fields ...Field) func() {
+	level = strings.ToUpper(level)
+	start := _log.clock()
+	return func() {
+		elapsed := Duration("elapsed", _log.clock().Sub(start))
+		if len(fields) == 0 {
+			_log.logFields(level, name, []Field{elapsed})
+			return
+		}
+		_log.logFields(level, name, append([]Field{elapsed}, fields...))
+	}
+}
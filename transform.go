@@ -0,0 +1,88 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import "strings"
+
+// TransformFunc rewrites an entry before it is encoded: it may add or remove
+// fields, rewrite the message or change the level. Time and ID are
+// informational; the written timestamp and ID are the logger's own.
+type TransformFunc func(e *Entry)
+
+// WithTransform adds t to the chain of transforms every entry goes through,
+// in the order they were added, on the logging goroutine. Entries whose level
+// is changed below the logger's level are dropped; an unknown level keeps the
+// original one. With transforms, maps and structs are logged like typed
+// fields (msg first, then the other keys in sorted order).
+func WithTransform(t TransformFunc) Option {
+	return func(conf *config) {
+		if t != nil {
+			conf.transforms = append(conf.transforms, t)
+		}
+	}
+}
+
+// transform runs the chain over a copy of fields.
+func (_log *Log) transform(level, msg string, fields []Field) (string, string, []Field) {
+	e := Entry{
+		Time:    _log.now(),
+		Level:   level,
+		Logger:  _log.name,
+		Message: msg,
+		Fields:  append([]Field(nil), fields...),
+	}
+	for _, t := range _log.transforms {
+		t(&e)
+	}
+	if lvl := strings.ToUpper(e.Level); verifyLevel(lvl) {
+		level = lvl
+	}
+	return level, e.Message, e.Fields
+}
+
+// dataParts splits the data of a logging call into message and fields.
+func (_log *Log) dataParts(data interface{}, args []interface{}) (string, []Field) {
+	if len(args) == 0 {
+		fields, ok := data.(map[string]interface{})
+		if !ok {
+			fields, ok = structFields(_log.encoder, data)
+		}
+		if ok {
+			msg, _, typed := mapEntryParts(fields)
+			return msg, typed
+		}
+	}
+	return _log.formatMessageString(data, args...), nil
+}
@@ -0,0 +1,132 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"strconv"
+	"unicode/utf8"
+)
+
+// minMessageSize is the smallest cap WithMaxMessageSize accepts, so that the
+// truncation marker always fits.
+const minMessageSize = 64
+
+// WithMaxMessageSize caps log messages at n bytes (64 at least). A longer
+// message keeps its first bytes, cut at a UTF-8 boundary, followed by
+// "...[truncated N bytes]", N being how many bytes were dropped, so that the
+// result is at most n bytes long. It applies to text messages, formatted
+// messages, the "msg" of structured maps, the message of *Fields calls and
+// Write; field values are left as they are.
+func WithMaxMessageSize(n int) Option {
+	return func(conf *config) {
+		if n <= 0 {
+			return
+		}
+		if n < minMessageSize {
+			n = minMessageSize
+		}
+		conf.maxMessageSize = n
+	}
+}
+
+// capMessage truncates the message carried by data and args, formatting it
+// first when needed. Structs and maps without an oversized "msg" are left
+// alone.
+func (_log *Log) capMessage(data interface{}, args []interface{}) (interface{}, []interface{}) {
+	switch v := data.(type) {
+	case string:
+		if len(args) == 0 {
+			return _log.truncateMessage(v), nil
+		}
+	case map[string]interface{}:
+		if len(args) > 0 {
+			break
+		}
+		if msg, ok := v["msg"].(string); ok && len(msg) > _log.maxMessageSize {
+			m := make(map[string]interface{}, len(v))
+			for k, val := range v {
+				m[k] = val
+			}
+			m["msg"] = _log.truncateMessage(msg)
+			return m, nil
+		}
+		return data, nil
+	default:
+		if len(args) == 0 && _log.structured() {
+			return data, nil
+		}
+	}
+	return _log.truncateMessage(_log.formatMessageString(data, args...)), nil
+}
+
+func (_log *Log) truncateMessage(msg string) string {
+	if len(msg) <= _log.maxMessageSize {
+		return msg
+	}
+	keep := _log.keepBytes(len(msg))
+	for keep > 0 && !utf8.RuneStart(msg[keep]) {
+		keep--
+	}
+	return string(appendTruncated(make([]byte, 0, _log.maxMessageSize), msg[:keep], len(msg)-keep))
+}
+
+func (_log *Log) truncateBytes(msg []byte) []byte {
+	if len(msg) <= _log.maxMessageSize {
+		return msg
+	}
+	keep := _log.keepBytes(len(msg))
+	for keep > 0 && !utf8.RuneStart(msg[keep]) {
+		keep--
+	}
+	return appendTruncated(make([]byte, 0, _log.maxMessageSize), string(msg[:keep]), len(msg)-keep)
+}
+
+// keepBytes returns how much of an n-byte message fits with the marker.
+func (_log *Log) keepBytes(n int) int {
+	// el marcador cuenta los bytes descartados, a lo sumo n
+	return _log.maxMessageSize - len(truncatedPrefix) - len(strconv.Itoa(n)) - len(truncatedSuffix)
+}
+
+const (
+	truncatedPrefix = "...[truncated "
+	truncatedSuffix = " bytes]"
+)
+
+func appendTruncated(dst []byte, kept string, dropped int) []byte {
+	dst = append(dst, kept...)
+	dst = append(dst, truncatedPrefix...)
+	dst = strconv.AppendInt(dst, int64(dropped), 10)
+	return append(dst, truncatedSuffix...)
+}
@@ -0,0 +1,115 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// sharedTimestamps refreshes the timestamp cache of every logger from a
+// single goroutine, formatting each layout and time zone once per tick, so N
+// loggers do not run N tickers. Loggers without timestamps need no cache and
+// those with WithClock keep their own, which reads their clock.
+var sharedTimestamps = struct {
+	sync.Mutex
+	logs map[*Log]struct{}
+	stop chan struct{}
+}{logs: make(map[*Log]struct{})}
+
+// timestampKey identifies loggers that can share a formatted timestamp.
+type timestampKey struct {
+	layout string
+	utc    bool
+}
+
+// shareTimestamps subscribes _log to the shared cache, starting the
+// refreshing goroutine for the first one.
+func (_log *Log) shareTimestamps() {
+	sharedTimestamps.Lock()
+	sharedTimestamps.logs[_log] = struct{}{}
+	if sharedTimestamps.stop == nil {
+		sharedTimestamps.stop = make(chan struct{})
+		go refreshSharedTimestamps(sharedTimestamps.stop)
+	}
+	sharedTimestamps.Unlock()
+}
+
+// unshareTimestamps unsubscribes _log, stopping the goroutine with the last
+// one.
+func (_log *Log) unshareTimestamps() {
+	sharedTimestamps.Lock()
+	delete(sharedTimestamps.logs, _log)
+	if len(sharedTimestamps.logs) == 0 && sharedTimestamps.stop != nil {
+		close(sharedTimestamps.stop)
+		sharedTimestamps.stop = nil
+	}
+	sharedTimestamps.Unlock()
+}
+
+func refreshSharedTimestamps(stop chan struct{}) {
+	ticker := time.NewTicker(cacheInterval)
+	defer ticker.Stop()
+	formatted := make(map[timestampKey][]byte)
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+		now := time.Now()
+		sharedTimestamps.Lock()
+		for lg := range sharedTimestamps.logs {
+			key := timestampKey{lg.timestampLayout(), atomic.LoadInt32(&lg.utc) == 1}
+			ts, ok := formatted[key]
+			if !ok {
+				t := now
+				if key.utc {
+					t = t.UTC()
+				}
+				ts = t.AppendFormat(nil, key.layout)
+				// sin capacidad libre: nadie puede escribir en el slice compartido
+				ts = ts[:len(ts):len(ts)]
+				formatted[key] = ts
+			}
+			lg.cachedTime.Store(ts)
+		}
+		sharedTimestamps.Unlock()
+		for key := range formatted {
+			delete(formatted, key)
+		}
+	}
+}
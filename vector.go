@@ -0,0 +1,136 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import "os"
+
+// vectorMinLine is the size from which a pooled line is handed to the kernel
+// as it is instead of being copied into the batch buffer; below it the copy
+// is cheaper than one more iovec.
+const vectorMinLine = 1024
+
+// segment is a piece of a vectored batch: a pooled line of its own, or the
+// [start, end) region of the batch buffer.
+type segment struct {
+	line       []byte
+	start, end int
+}
+
+// vectored reports whether lines may stay out of the batch buffer: only when
+// nothing reads the buffer line by line while it fills (per-level files,
+// routing, redaction patterns, the hash chain) or needs the whole batch in
+// one piece (mirror file, spillover, StartWriter, Format.Binary).
+func (_log *Log) vectored() bool {
+	return canWritev && _log.levelFiles == nil && _log.router == nil && _log.mirror == nil &&
+		_log.spill == nil && _log.sink == nil && _log.chain == nil &&
+		(_log.redact == nil || len(_log.redact.scrubbers) == 0) && _log.lineFormat() != Format.Binary && !_log.gzipOut
+}
+
+// holdLine deja la línea fuera del buffer del lote, tras lo que ya hay en él.
+// Writer goroutine only, with mtx held.
+func (_log *Log) holdLine(line []byte) {
+	if len(_log.buffer) > _log.segStart {
+		_log.segs = append(_log.segs, segment{start: _log.segStart, end: len(_log.buffer)})
+	}
+	_log.segs = append(_log.segs, segment{line: line})
+	_log.segStart = len(_log.buffer)
+	_log.segBytes += len(line)
+}
+
+// takeSegments devuelve los trozos del lote que acaba de pasar a writeBuf,
+// con la región final del buffer, y su tamaño total, y deja listos los del
+// siguiente. Writer goroutine only, with mtx held.
+func (_log *Log) takeSegments() ([]segment, int64) {
+	segs := _log.segs
+	if len(segs) == 0 {
+		return nil, 0
+	}
+	if len(_log.writeBuf) > _log.segStart {
+		segs = append(segs, segment{start: _log.segStart, end: len(_log.writeBuf)})
+	}
+	size := int64(len(_log.writeBuf) + _log.segBytes)
+	_log.segs, _log.segsOut = _log.segsOut[:0], segs
+	_log.segStart, _log.segBytes = 0, 0
+	return segs, size
+}
+
+// writeSegments writes a vectored batch to f in one writev call.
+func (_log *Log) writeSegments(f *os.File, segs []segment) {
+	bufs := _log.iovBufs[:0]
+	for _, s := range segs {
+		if s.line != nil {
+			bufs = append(bufs, s.line)
+		} else {
+			bufs = append(bufs, _log.writeBuf[s.start:s.end])
+		}
+	}
+	_log.iovBufs = bufs
+	written, err := writev(f, bufs)
+	if written > 0 {
+		_log.currentSize += int64(written)
+	}
+	if err != nil {
+		_log.keepWriteErr(err)
+	}
+	for i := range bufs {
+		bufs[i] = nil
+	}
+	releaseSegments(segs)
+}
+
+// joinSegments rebuilds the batch in one buffer, for the paths that write
+// it line by line (rotation) or not at all (lazy open failures).
+func (_log *Log) joinSegments(segs []segment) {
+	joined := _log.joinBuf[:0]
+	for _, s := range segs {
+		if s.line != nil {
+			joined = append(joined, s.line...)
+		} else {
+			joined = append(joined, _log.writeBuf[s.start:s.end]...)
+		}
+	}
+	_log.joinBuf, _log.writeBuf = _log.writeBuf, joined
+	releaseSegments(segs)
+}
+
+// releaseSegments devuelve al pool las líneas del lote.
+func releaseSegments(segs []segment) {
+	for i := range segs {
+		if segs[i].line != nil {
+			putBuf(segs[i].line)
+			segs[i].line = nil
+		}
+	}
+}
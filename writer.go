@@ -0,0 +1,77 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// ErrNoFile is returned by file operations (Snapshot) on loggers started
+// with StartWriter.
+var ErrNoFile = errors.New("acacia: logger writes to an io.Writer, not a file")
+
+// StartWriter runs the whole pipeline (batching, formats, filters, level
+// files…) against w instead of a log file, e.g. os.Stdout in containers
+// whose contract is "write JSON to stdout". There is no file to rotate:
+// Rotation and DailyRotation have no effect, Reopen only reopens level files
+// and Snapshot returns ErrNoFile. w is never closed; Sync and sync policies
+// call its Sync method when it has one, as *os.File does. Level files are
+// created in the working directory.
+func StartWriter(w io.Writer, logLevel string, opts ...Option) (*Log, error) {
+	if w == nil {
+		return nil, fmt.Errorf("writer cannot be nil")
+	}
+	name := "writer"
+	if f, ok := w.(*os.File); ok {
+		name = filepath.Base(f.Name())
+	}
+	return startLog(name, "."+string(os.PathSeparator), logLevel, newConfig(opts), nil, w), nil
+}
+
+// syncOut fsyncs the log file or, if it can be synced, the StartWriter
+// destination.
+func (_log *Log) syncOut() error {
+	if s, ok := _log.sink.(interface{ Sync() error }); ok {
+		return syncFile(s)
+	}
+	if f := _log.getFile(); f != nil {
+		return syncFile(f)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+//                                                                                                                    //
+//  Author: Juan Alejandro Perez Chandia                                                                              //
+//  Contact: juan.alejandro@humanjuan.com                                                                             //
+//  Website: https://humanjuan.com/                                                                                   //
+//                                                                                                                    //
+//  HumanJuan Acacia - High-performance concurrent logger with real file rotation                                     //
+//                                                                                                                    //
+//  Version: 2.2.0                                                                                                    //
+//                                                                                                                    //
+//  MIT License                                                                                                       //
+//                                                                                                                    //
+//  Copyright (c) 2020 Juan Alejandro                                                                                 //
+//                                                                                                                    //
+//  Permission is hereby granted, free of charge, to any person obtaining a copy                                      //
+//  of this software and associated documentation files (the "Software"), to deal                                     //
+//  in the Software without restriction, including without limitation the rights                                      //
+//  to use, copy, modify, merge, publish, distribute, sublicense, and/or sell                                         //
+//  copies of the Software, and to permit persons to whom the Software is                                             //
+//  furnished to do so, subject to the following conditions:                                                          //
+//                                                                                                                    //
+//  The above copyright notice and this permission notice shall be included in all                                    //
+//  copies or substantial portions of the Software.                                                                   //
+//                                                                                                                    //
+//  THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR                                        //
+//  IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,                                          //
+//  FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE                                       //
+//  AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER                                            //
+//  LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,                                     //
+//  OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE                                     //
+//  SOFTWARE.                                                                                                         //
+//                                                                                                                    //
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+package acacia
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// WithWriters spreads records over n files, app.log.0 to app.log.<n-1>,
+// each with its own queue and writer goroutine, for throughputs where a
+// single file descriptor is the bottleneck. Records are dealt round-robin
+// (a batch stays together), so order is kept within a file but not across
+// them; merge by timestamp when reading. Rotation, format and timestamp
+// settings, Barrier, Sync, Flush, Reopen and Close apply to every file.
+// WithLevelFile, WithRouting, WithMirrorFile and WithDuplicateSuppression are
+// not supported with it, and it is ignored by StartWriter and with
+// AuditMode. n < 2 is ignored.
+func WithWriters(n int) Option {
+	return func(conf *config) {
+		if n > 1 {
+			conf.writers = n
+		}
+	}
+}
+
+// startSharded arranca un logger por archivo y devuelve el que reparte los
+// registros entre ellos; este no abre archivo ni tiene cola ni writer.
+func startSharded(logName, logPath, logLevel string, cfg *config) (*Log, error) {
+	shardCfg := *cfg
+	shardCfg.writers = 0
+	shardCfg.levelFiles = nil
+	shardCfg.router = nil
+	shardCfg.mirror = nil
+	shardCfg.dedup = nil
+	shardCfg.critMirror = nil
+	shardCfg.errs = newErrSink(logName)
+	if cfg.filters != nil {
+		shardCfg.filterMtx = new(sync.Mutex)
+	}
+
+	names := make([]string, cfg.writers)
+	for i := range names {
+		names[i] = fmt.Sprintf("%s.%d", logName, i)
+		if strings.HasSuffix(logName, gzipExt) {
+			names[i] = fmt.Sprintf("%s.%d%s", strings.TrimSuffix(logName, gzipExt), i, gzipExt)
+		}
+	}
+	if cfg.disk != nil {
+		// cada franja purga sus backups, pero nunca el archivo vivo de otra
+		for _, name := range names {
+			cfg.disk.stripes = append(cfg.disk.stripes, filepath.Join(logPath, name))
+		}
+	}
+
+	shards := make([]*Log, cfg.writers)
+	for i := range shards {
+		name := names[i]
+		var f *os.File
+		if !cfg.lazyOpen {
+			var err error
+			f, err = openLogFile(filepath.Join(logPath, name), cfg.fileMode)
+			if err != nil {
+				for _, shard := range shards[:i] {
+					_ = shard.Close()
+				}
+				return nil, err
+			}
+		}
+		c := shardCfg
+		shards[i] = startLog(name, logPath, logLevel, &c, f, nil)
+	}
+
+	// el padre conserva lo que corre en el productor (nivel, muestreo,
+	// campos, espejo de CRITICAL) y nada de lo que toca archivos
+	parentCfg := shardCfg
+	parentCfg.critMirror = cfg.critMirror
+	parentCfg.lazyOpen = true
+	parentCfg.spillThreshold = 0
+	parentCfg.chainKey = nil
+	parentCfg.header = nil
+	parentCfg.fileHeader = nil
+	parentCfg.fileFooter = nil
+	parentCfg.recoverPartial = false
+	parentCfg.disk = nil
+	parentCfg.bufferSize = 0
+	parentCfg.batchSize = 0
+	parentCfg.noWriter = true
+	parent := startLog(logName, logPath, logLevel, &parentCfg, nil, nil)
+	parent.shards = shards
+	shardCfg.errs.owner = parent
+	return parent, nil
+}
+
+// nextShard elige el archivo del siguiente registro.
+func (_log *Log) nextShard() *Log {
+	i := atomic.AddUint32(&_log.shardNext, 1)
+	return _log.shards[i%uint32(len(_log.shards))]
+}
+
+// eachShard aplica fn a todos los archivos y devuelve el primer error.
+func (_log *Log) eachShard(fn func(*Log) error) error {
+	var first error
+	for _, shard := range _log.shards {
+		if err := fn(shard); err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// closeShards cierra los archivos tras el padre, que ya no les envía nada.
+func (_log *Log) closeShards() error {
+	return _log.eachShard((*Log).Close)
+}